@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulSnow/orchestrator/internal/runner"
+)
+
+func cmdBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator bench - Run Go benchmarks and compare to a baseline
+
+DESCRIPTION
+  Runs "go test ./... -run=^$ -bench=<regex> -benchmem -benchtime=3s" in the
+  repository, logging to /tmp/orchestrator-bench-<repo>.log, and compares the
+  results with state/bench-<repo>.json.
+
+  Benchmarks more than 10% slower than the baseline are marked REGRESSION.
+  The first run, or any run with --save, writes a new baseline.
+
+USAGE
+  orchestrator bench <repo>
+  orchestrator bench <repo> --bench 'Encode' --save
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	bench := fs.String("bench", ".", "Regular expression selecting benchmarks to run")
+	save := fs.Bool("save", false, "Store these results as the new baseline")
+	name := parseIDFlags(fs, args)
+	if name == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg := loadRepoConfig()
+	repo, ok := cfg.GetRepo(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", name)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Benchmarking %s...\n", repo.Name)
+	result := runner.BenchmarkRepo(repo, *bench)
+	if !result.Success {
+		fmt.Fprintf(os.Stderr, "Error: benchmarks failed (exit %d) -> %s\n", result.ExitCode, result.LogFile)
+		os.Exit(1)
+	}
+
+	benchmarks, err := runner.ParseBenchOutput(result.LogFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", result.LogFile, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d benchmarks (%.1fs) -> %s\n\n", len(benchmarks), result.Duration, result.LogFile)
+
+	deltas, err := runner.CompareBenchToBaseline(cfg.RootPath, repo.Name, benchmarks)
+	switch {
+	case os.IsNotExist(err):
+		*save = true
+		for _, b := range benchmarks {
+			fmt.Printf("  %-40s %12.0f ns/op %8d B/op %6d allocs/op\n", b.Name, b.NsPerOp, b.BytesPerOp, b.AllocsPerOp)
+		}
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Error reading baseline: %v\n", err)
+		os.Exit(1)
+	default:
+		regressions := 0
+		for _, d := range deltas {
+			mark := ""
+			if d.Regression {
+				mark = " " + red("REGRESSION")
+				regressions++
+			}
+			fmt.Printf("  %-40s %12.0f -> %12.0f ns/op (%+.1f%%)%s\n", d.Name, d.BaselineNsPerOp, d.NsPerOp, d.DeltaPercent, mark)
+		}
+		fmt.Printf("\n%d regressions against baseline\n", regressions)
+	}
+
+	if *save {
+		if err := runner.WriteBenchBaseline(cfg.RootPath, repo.Name, benchmarks); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Baseline written to state/bench-%s.json\n", repo.Name)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import "os"
+
+const (
+	colorRed   = "\033[31m"
+	colorReset = "\033[0m"
+)
+
+// colorEnabled reports whether stdout is a terminal that should receive ANSI
+// colors. Setting NO_COLOR disables colors regardless of the terminal.
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// red wraps s in red ANSI codes when colors are enabled.
+func red(s string) string {
+	if !colorEnabled() {
+		return s
+	}
+	return colorRed + s + colorReset
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/tasks"
+)
+
+// Words offered by the completion scripts. Keep these in step with the
+// switches in main, cmdTask, and cmdConfig.
+var (
+	completionCommands = []string{
+		"launch", "review", "cleanup", "status", "dashboard", "metrics", "activity",
+		"add-issue", "scan", "repo-status", "health", "build", "bench", "run", "sync",
+		"test", "test-all", "init", "config", "task", "report", "logs",
+		"whoami", "completion", "version", "help",
+	}
+	completionTaskCommands = []string{
+		"list", "start", "complete", "pause", "resume", "edit", "import-github",
+		"import-csv", "bulk-start", "bulk-complete", "gantt", "stats", "check",
+		"move", "split", "merge", "reorder", "link", "open", "assign", "unassign",
+		"export", "shard-backlog", "create", "templates", "help",
+	}
+	// completionTaskIDCommands are the task subcommands whose first argument
+	// is a task ID.
+	completionTaskIDCommands = []string{
+		"start", "complete", "pause", "resume", "edit", "move", "split", "merge",
+		"reorder", "link", "open", "assign", "unassign",
+	}
+	completionConfigCommands = []string{"validate", "add-repo", "remove-repo", "help"}
+	// completionRepoCommands take a repository name as their first argument.
+	completionRepoCommands = []string{"build", "bench", "sync", "test"}
+	completionShells       = []string{"bash", "zsh", "fish"}
+)
+
+func cmdCompletion(args []string) {
+	if len(args) != 1 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Println(`orchestrator completion - Print a shell completion script
+
+USAGE
+  orchestrator completion bash|zsh|fish
+
+  Completes subcommands, repository names from config/repos.json, and the
+  IDs of backlog and active tasks. To install, add one of these to your
+  shell's startup file:
+
+    bash (~/.bashrc)             . <(orchestrator completion bash)
+    zsh (~/.zshrc)               . <(orchestrator completion zsh)
+    fish (~/.config/fish/config.fish)
+                                 orchestrator completion fish | source`)
+		if len(args) != 1 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	script, err := completionScript(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(script)
+}
+
+// cmdComplete is the hidden "__complete repos|tasks" command the completion
+// scripts call for dynamic words. It prints one word per line and stays
+// silent on errors so a broken config never garbles the prompt.
+func cmdComplete(args []string) {
+	if len(args) != 1 {
+		return
+	}
+	var words []string
+	switch args[0] {
+	case "repos":
+		words = completeRepoNames(orchestratorRoot())
+	case "tasks":
+		words = completeTaskIDs(orchestratorRoot())
+	}
+	for _, w := range words {
+		fmt.Println(w)
+	}
+}
+
+// completeRepoNames returns the names of every configured repository,
+// archived ones included.
+func completeRepoNames(root string) []string {
+	cfg, err := config.Load(root)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, repo := range cfg.AllReposIncludingArchived() {
+		names = append(names, repo.Name)
+	}
+	return names
+}
+
+// completeTaskIDs returns the IDs of the backlog and active tasks.
+func completeTaskIDs(root string) []string {
+	mgr := tasks.NewManager(root)
+	var ids []string
+	for _, list := range []func() ([]tasks.Task, error){mgr.ListBacklog, mgr.ListActive} {
+		ts, err := list()
+		if err != nil {
+			continue
+		}
+		for _, t := range ts {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// completionScript returns the completion script for shell.
+func completionScript(shell string) (string, error) {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return "", fmt.Errorf("unknown shell %q (want %s)", shell, strings.Join(completionShells, ", "))
+	}
+	return strings.NewReplacer(
+		"@COMMANDS@", strings.Join(completionCommands, " "),
+		"@TASK_COMMANDS@", strings.Join(completionTaskCommands, " "),
+		"@TASK_ID_COMMANDS@", strings.Join(completionTaskIDCommands, " "),
+		"@TASK_ID_PATTERN@", strings.Join(completionTaskIDCommands, "|"),
+		"@CONFIG_COMMANDS@", strings.Join(completionConfigCommands, " "),
+		"@REPO_COMMANDS@", strings.Join(completionRepoCommands, " "),
+		"@REPO_PATTERN@", strings.Join(completionRepoCommands, "|"),
+		"@SHELLS@", strings.Join(completionShells, " "),
+	).Replace(script), nil
+}
+
+const bashCompletion = `# bash completion for orchestrator
+_orchestrator() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    COMPREPLY=()
+
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W "@COMMANDS@" -- "$cur"))
+        return
+    fi
+
+    case "${COMP_WORDS[1]}" in
+    @REPO_PATTERN@)
+        [ "$COMP_CWORD" -eq 2 ] && COMPREPLY=($(compgen -W "$(orchestrator __complete repos 2>/dev/null)" -- "$cur"))
+        ;;
+    run|logs)
+        [ "$prev" = "--repo" ] && COMPREPLY=($(compgen -W "$(orchestrator __complete repos 2>/dev/null)" -- "$cur"))
+        ;;
+    config)
+        if [ "$COMP_CWORD" -eq 2 ]; then
+            COMPREPLY=($(compgen -W "@CONFIG_COMMANDS@" -- "$cur"))
+        elif [ "$COMP_CWORD" -eq 3 ] && [ "${COMP_WORDS[2]}" = "remove-repo" ]; then
+            COMPREPLY=($(compgen -W "$(orchestrator __complete repos 2>/dev/null)" -- "$cur"))
+        fi
+        ;;
+    task)
+        if [ "$COMP_CWORD" -eq 2 ]; then
+            COMPREPLY=($(compgen -W "@TASK_COMMANDS@" -- "$cur"))
+        elif [ "$COMP_CWORD" -eq 3 ]; then
+            case "${COMP_WORDS[2]}" in
+            @TASK_ID_PATTERN@)
+                COMPREPLY=($(compgen -W "$(orchestrator __complete tasks 2>/dev/null)" -- "$cur"))
+                ;;
+            esac
+        fi
+        ;;
+    completion)
+        [ "$COMP_CWORD" -eq 2 ] && COMPREPLY=($(compgen -W "@SHELLS@" -- "$cur"))
+        ;;
+    esac
+}
+complete -F _orchestrator orchestrator
+`
+
+const zshCompletion = `#compdef orchestrator
+# zsh completion for orchestrator
+_orchestrator() {
+    if (( CURRENT == 2 )); then
+        compadd -- @COMMANDS@
+        return
+    fi
+
+    case $words[2] in
+    @REPO_PATTERN@)
+        (( CURRENT == 3 )) && compadd -- ${(f)"$(orchestrator __complete repos 2>/dev/null)"}
+        ;;
+    run|logs)
+        [[ $words[CURRENT-1] == --repo ]] && compadd -- ${(f)"$(orchestrator __complete repos 2>/dev/null)"}
+        ;;
+    config)
+        if (( CURRENT == 3 )); then
+            compadd -- @CONFIG_COMMANDS@
+        elif (( CURRENT == 4 )) && [[ $words[3] == remove-repo ]]; then
+            compadd -- ${(f)"$(orchestrator __complete repos 2>/dev/null)"}
+        fi
+        ;;
+    task)
+        if (( CURRENT == 3 )); then
+            compadd -- @TASK_COMMANDS@
+        elif (( CURRENT == 4 )) && [[ $words[3] == (@TASK_ID_PATTERN@) ]]; then
+            compadd -- ${(f)"$(orchestrator __complete tasks 2>/dev/null)"}
+        fi
+        ;;
+    completion)
+        (( CURRENT == 3 )) && compadd -- @SHELLS@
+        ;;
+    esac
+}
+
+if ! (( $+functions[compdef] )); then
+    autoload -Uz compinit && compinit
+fi
+compdef _orchestrator orchestrator
+`
+
+const fishCompletion = `# fish completion for orchestrator
+function __orchestrator_repos
+    orchestrator __complete repos 2>/dev/null
+end
+
+function __orchestrator_tasks
+    orchestrator __complete tasks 2>/dev/null
+end
+
+complete -c orchestrator -f
+complete -c orchestrator -n __fish_use_subcommand -a "@COMMANDS@"
+complete -c orchestrator -n "__fish_seen_subcommand_from @REPO_COMMANDS@" -a "(__orchestrator_repos)"
+complete -c orchestrator -n "__fish_seen_subcommand_from run logs" -l repo -x -a "(__orchestrator_repos)"
+complete -c orchestrator -n "__fish_seen_subcommand_from config; and not __fish_seen_subcommand_from @CONFIG_COMMANDS@" -a "@CONFIG_COMMANDS@"
+complete -c orchestrator -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from remove-repo" -a "(__orchestrator_repos)"
+complete -c orchestrator -n "__fish_seen_subcommand_from task; and not __fish_seen_subcommand_from @TASK_COMMANDS@" -a "@TASK_COMMANDS@"
+complete -c orchestrator -n "__fish_seen_subcommand_from task; and __fish_seen_subcommand_from @TASK_ID_COMMANDS@" -a "(__orchestrator_tasks)"
+complete -c orchestrator -n "__fish_seen_subcommand_from completion" -a "@SHELLS@"
+`
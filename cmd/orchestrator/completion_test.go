@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompletionScript(t *testing.T) {
+	for _, shell := range completionShells {
+		script, err := completionScript(shell)
+		if err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		if strings.Contains(script, "@") {
+			t.Errorf("%s: unreplaced placeholder in script:\n%s", shell, script)
+		}
+		for _, want := range []string{"__complete repos", "__complete tasks", "repo-status", "shard-backlog"} {
+			if !strings.Contains(script, want) {
+				t.Errorf("%s: script does not mention %q", shell, want)
+			}
+		}
+	}
+	if _, err := completionScript("tcsh"); err == nil {
+		t.Error("expected an error for an unknown shell")
+	}
+}
+
+func TestCompleteWords(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "config"), 0755)
+	os.MkdirAll(filepath.Join(root, "tasks"), 0755)
+	os.WriteFile(filepath.Join(root, "config", "repos.json"), []byte(`{"repositories": [
+		{"name": "alpha", "local": "/tmp/alpha", "default_branch": "main"},
+		{"name": "beta", "local": "/tmp/beta", "default_branch": "main", "archived": true}
+	]}`), 0644)
+	os.WriteFile(filepath.Join(root, "tasks", "backlog.md"), []byte("# Backlog\n\n### [task-002] Queued\n- **repo**: alpha\n"), 0644)
+	os.WriteFile(filepath.Join(root, "tasks", "active.md"), []byte("# Active\n\n### [task-001] Running\n- **repo**: alpha\n"), 0644)
+
+	if got, want := completeRepoNames(root), []string{"alpha", "beta"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("repo names = %v, want %v", got, want)
+	}
+	if got, want := completeTaskIDs(root), []string{"task-002", "task-001"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("task IDs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/repos"
+)
+
+func cmdConfig(args []string) {
+	if len(args) < 1 {
+		printConfigUsage()
+		os.Exit(1)
+	}
+
+	sub := args[0]
+	subArgs := args[1:]
+
+	switch sub {
+	case "validate":
+		cmdConfigValidate(subArgs)
+	case "add-repo":
+		cmdConfigAddRepo(subArgs)
+	case "remove-repo":
+		cmdConfigRemoveRepo(subArgs)
+	case "help", "-h", "--help":
+		printConfigUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown config command: %s\n", sub)
+		printConfigUsage()
+		os.Exit(1)
+	}
+}
+
+func printConfigUsage() {
+	fmt.Println(`orchestrator config - Manage config/repos.json
+
+USAGE
+  orchestrator config validate        Check repos.json for mistakes
+  orchestrator config add-repo --name N --local PATH [--remote URL] [--language L]
+                                      Add a repository (prompts for each field if
+                                      run without flags)
+  orchestrator config remove-repo <name> [--purge]
+                                      Remove a repository from repos.json and
+                                      repos.local.json; --purge also deletes
+                                      its local clone`)
+}
+
+func cmdConfigValidate(args []string) {
+	fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+	fs.Parse(args)
+
+	cfg, err := config.Load(orchestratorRoot())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	errs := cfg.Validate()
+	if len(errs) == 0 {
+		fmt.Printf("repos.json OK (%d repositories)\n", len(cfg.AllReposIncludingArchived()))
+		return
+	}
+	for _, e := range errs {
+		fmt.Printf("  [ERROR] %v\n", e)
+	}
+	fmt.Printf("\n%d problems in repos.json\n", len(errs))
+	os.Exit(1)
+}
+
+func cmdConfigAddRepo(args []string) {
+	fs := flag.NewFlagSet("config add-repo", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator config add-repo - Add a repository to repos.json
+
+DESCRIPTION
+  Appends a repository to config/repos.json after validating it. If --local
+  already exists on disk, the language, origin remote, default branch, and
+  CLAUDE.md presence are detected from it and used for any unset flag. Run
+  with no flags to be prompted for each field.
+
+USAGE
+  orchestrator config add-repo --name myrepo --local /path/to/repo \
+      --remote git@github.com:org/repo.git --language go
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	name := fs.String("name", "", "Repository name (required)")
+	local := fs.String("local", "", "Absolute path of the local checkout (required)")
+	remote := fs.String("remote", "", "Git remote URL")
+	language := fs.String("language", "", "Language: go, javascript, make, rust, python, dotnet, elixir, java, php, swift (default: detected)")
+	branch := fs.String("default-branch", "", "Default branch (default: detected, or main)")
+	platform := fs.String("platform", "", "Hosting platform (default: derived from --remote)")
+	tags := fs.String("tags", "", "Comma-separated tags")
+	description := fs.String("description", "", "Short description")
+	fs.Parse(args)
+
+	var repo config.RepoConfig
+	if len(args) == 0 {
+		repo = promptRepoConfig(os.Stdin, os.Stdout)
+	} else {
+		if *name == "" || *local == "" {
+			fs.Usage()
+			os.Exit(1)
+		}
+		repo = config.RepoConfig{
+			Name:          *name,
+			Local:         *local,
+			Remote:        *remote,
+			Language:      *language,
+			DefaultBranch: *branch,
+			Platform:      *platform,
+			Tags:          splitTags(*tags),
+			Description:   *description,
+		}
+		fillDetected(&repo)
+	}
+
+	if err := config.AddRepo(orchestratorRoot(), repo); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Added %s (%s, %s) to repos.json\n", repo.Name, repo.Language, repo.Local)
+}
+
+func cmdConfigRemoveRepo(args []string) {
+	fs := flag.NewFlagSet("config remove-repo", flag.ExitOnError)
+	purge := fs.Bool("purge", false, "Also delete the local clone (asks for confirmation)")
+	name := parseIDFlags(fs, args)
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator config remove-repo <name> [--purge]")
+		os.Exit(1)
+	}
+
+	repo, ok := loadRepoConfig().GetRepo(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", name)
+		os.Exit(1)
+	}
+
+	active, err := newTaskManager().ListActive()
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error reading active tasks: %v\n", err)
+		os.Exit(1)
+	}
+	var blocking []string
+	for _, t := range active {
+		if t.Repo == name {
+			blocking = append(blocking, t.ID)
+		}
+	}
+	if len(blocking) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %s is referenced by active tasks: %s\n", name, strings.Join(blocking, ", "))
+		os.Exit(1)
+	}
+
+	if *purge {
+		if !confirm(os.Stdin, os.Stdout, fmt.Sprintf("Delete %s and everything in it?", repo.Local)) {
+			fmt.Println("Aborted.")
+			os.Exit(1)
+		}
+	}
+
+	if _, err := config.RemoveRepo(orchestratorRoot(), name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Removed %s\n", name)
+
+	// Delete the path the user confirmed, which includes any
+	// repos.local.json override.
+	if *purge {
+		if info, err := os.Stat(repo.Local); err != nil || !info.IsDir() {
+			fmt.Printf("No local clone at %s\n", repo.Local)
+			return
+		}
+		if err := os.RemoveAll(repo.Local); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting %s: %v\n", repo.Local, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Deleted %s\n", repo.Local)
+	}
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
+// fillDetected fills unset fields from the checkout at repo.Local, if one
+// exists, and applies defaults for the rest.
+func fillDetected(repo *config.RepoConfig) {
+	local := os.Expand(repo.Local, os.Getenv)
+	if info, err := os.Stat(local); err == nil && info.IsDir() {
+		detected := repos.DescribeRepo(local)
+		if repo.Language == "" {
+			repo.Language = detected.Language
+		}
+		if repo.Remote == "" {
+			repo.Remote = detected.Remote
+		}
+		if repo.DefaultBranch == "" {
+			repo.DefaultBranch = detected.DefaultBranch
+		}
+		repo.HasClaudeMD = detected.HasClaudeMD
+		if repo.CI.System == "" {
+			repo.CI.System = detected.CI.System
+		}
+	}
+	if repo.Language == "" {
+		repo.Language = "unknown"
+	}
+	if repo.DefaultBranch == "" {
+		repo.DefaultBranch = "main"
+	}
+	if repo.Platform == "" {
+		repo.Platform = repos.PlatformFromRemote(repo.Remote)
+	}
+}
+
+// promptRepoConfig asks for each field in turn, offering detected values as
+// defaults once the local path is known.
+func promptRepoConfig(in io.Reader, out io.Writer) config.RepoConfig {
+	r := bufio.NewReader(in)
+	// askShown offers def as the default, displayed as shown.
+	askShown := func(label, def, shown string) string {
+		if def != "" {
+			fmt.Fprintf(out, "%s [%s]: ", label, shown)
+		} else {
+			fmt.Fprintf(out, "%s: ", label)
+		}
+		line, _ := r.ReadString('\n')
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+		return def
+	}
+	ask := func(label, def string) string { return askShown(label, def, def) }
+
+	var repo config.RepoConfig
+	repo.Name = ask("Name", "")
+	repo.Local = ask("Local path", "")
+	fillDetected(&repo)
+	repo.Remote = askShown("Remote", repo.Remote, repo.RedactedRemote())
+	repo.Platform = repos.PlatformFromRemote(repo.Remote)
+	repo.Language = ask("Language", repo.Language)
+	repo.DefaultBranch = ask("Default branch", repo.DefaultBranch)
+	repo.Tags = splitTags(ask("Tags (comma-separated)", ""))
+	repo.Description = ask("Description", "")
+	return repo
+}
+
+func splitTags(s string) []string {
+	tags := []string{}
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/runner"
+)
+
+func cmdLogs(args []string) {
+	if len(args) > 0 && args[0] == "clean" {
+		cmdLogsClean(args[1:])
+		return
+	}
+
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator logs - View build, test, and sync log files
+
+DESCRIPTION
+  Lists /tmp/orchestrator-<type>-<repo>.log files, and the .stderr.log
+  files beside them, for configured repos, newest first, with size, age,
+  and the first error line found in either.
+
+USAGE
+  orchestrator logs [--repo name] [--type build|test|sync] [--last N]
+  orchestrator logs --repo staking --type test --tail
+  orchestrator logs --repo staking --type build --cat
+  orchestrator logs clean --older-than 7d
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	repo := fs.String("repo", "", "Only show logs for this repo")
+	logType := fs.String("type", "", "Only show logs of this type (build, test, sync, ...)")
+	last := fs.Int("last", 20, "Show at most N logs")
+	tail := fs.Bool("tail", false, "Follow the most recent matching log with tail -f")
+	cat := fs.Bool("cat", false, "Print the full content of the most recent matching log")
+	fs.Parse(args)
+
+	logs := findRepoLogs(*repo, *logType)
+	if len(logs) == 0 {
+		fmt.Println("No matching log files found.")
+		return
+	}
+
+	// --tail and --cat follow the run log rather than its stderr file.
+	newest := logs[0]
+	for _, l := range logs {
+		if !l.Stderr {
+			newest = l
+			break
+		}
+	}
+
+	if *tail {
+		cmd := exec.Command("tail", "-f", newest.Path)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cat {
+		data, err := os.ReadFile(newest.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(data)
+		return
+	}
+
+	if *last > 0 && len(logs) > *last {
+		logs = logs[:*last]
+	}
+
+	now := time.Now()
+	for _, l := range logs {
+		fmt.Printf("  %-50s %8s  %6s ago\n", l.Path, formatSize(l.Size), formatAge(now.Sub(l.ModTime)))
+		line := runner.FirstErrorLine(l.Path)
+		if line == "" && !l.Stderr {
+			line = runner.FirstErrorLine(runner.StderrPath(l.Path))
+		}
+		if line != "" {
+			if len(line) > 100 {
+				line = line[:100] + "..."
+			}
+			fmt.Printf("      %s\n", line)
+		}
+	}
+}
+
+func cmdLogsClean(args []string) {
+	fs := flag.NewFlagSet("logs clean", flag.ExitOnError)
+	olderThan := fs.String("older-than", "7d", "Remove logs not modified within this duration (e.g. 7d, 12h)")
+	dryRun := fs.Bool("dry-run", false, "List files that would be removed without removing them")
+	fs.Parse(args)
+
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --older-than %q: %v\n", *olderThan, err)
+		os.Exit(1)
+	}
+
+	cutoff := time.Now().Add(-age)
+	removed := 0
+	for _, l := range findRepoLogs("", "") {
+		if l.ModTime.After(cutoff) {
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("  would remove %s\n", l.Path)
+			removed++
+			continue
+		}
+		if os.Remove(l.Path) == nil {
+			removed++
+		}
+	}
+	verb := "Removed"
+	if *dryRun {
+		verb = "Would remove"
+	}
+	fmt.Printf("%s %d log files older than %s\n", verb, removed, *olderThan)
+}
+
+// findRepoLogs returns runner logs for the configured repos.
+func findRepoLogs(repo, logType string) []runner.LogFile {
+	cfg := loadRepoConfig()
+	var names []string
+	for _, r := range cfg.AllReposIncludingArchived() {
+		names = append(names, r.Name)
+	}
+	logs, err := runner.FindLogs(names, repo, logType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing logs: %v\n", err)
+		os.Exit(1)
+	}
+	return logs
+}
+
+// parseAge parses a duration that also accepts a "d" (days) suffix.
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
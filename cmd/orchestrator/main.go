@@ -11,26 +11,27 @@ import (
 	"strings"
 	"time"
 
+	"github.com/PaulSnow/orchestrator/internal/config"
 	"github.com/PaulSnow/orchestrator/internal/orchestrator"
-)
-
-// Version info - set via ldflags at build time
-var (
-	Version   = "dev"
-	GitCommit = "unknown"
-	BuildDate = "unknown"
+	"github.com/PaulSnow/orchestrator/internal/runner"
+	"github.com/PaulSnow/orchestrator/internal/version"
 )
 
 const defaultNumWorkers = 5
 
 func main() {
-	if len(os.Args) < 2 {
+	argv, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(argv) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
-	args := os.Args[2:]
+	command := argv[0]
+	args := argv[1:]
 
 	switch command {
 	case "launch":
@@ -49,6 +50,40 @@ func main() {
 		cmdActivity(args)
 	case "add-issue":
 		cmdAddIssue(args)
+	case "scan":
+		cmdScan(args)
+	case "repo-status":
+		cmdRepoStatus(args)
+	case "build":
+		cmdBuild(args)
+	case "bench":
+		cmdBench(args)
+	case "run":
+		cmdRun(args)
+	case "sync":
+		cmdSync(args)
+	case "test":
+		cmdTest(args)
+	case "test-all":
+		cmdTestAll(args)
+	case "init":
+		cmdInit(args)
+	case "config":
+		cmdConfig(args)
+	case "task":
+		cmdTask(args)
+	case "health":
+		cmdHealth(args)
+	case "report":
+		cmdReport(args)
+	case "logs":
+		cmdLogs(args)
+	case "completion":
+		cmdCompletion(args)
+	case "whoami":
+		cmdWhoami(args)
+	case "__complete":
+		cmdComplete(args)
 	case "version", "-v", "--version":
 		printVersion()
 	case "help", "-h", "--help":
@@ -60,6 +95,46 @@ func main() {
 	}
 }
 
+// parseGlobalFlags consumes the flags that precede the subcommand and returns
+// the remaining arguments: --root, --config, and --verbose.
+func parseGlobalFlags(args []string) ([]string, error) {
+	for len(args) > 0 {
+		switch arg := args[0]; {
+		case arg == "--root" || arg == "-root":
+			if len(args) < 2 || args[1] == "" {
+				return nil, fmt.Errorf("%s requires a path", arg)
+			}
+			rootFlag = args[1]
+			args = args[2:]
+		case strings.HasPrefix(arg, "--root=") || strings.HasPrefix(arg, "-root="):
+			_, rootFlag, _ = strings.Cut(arg, "=")
+			if rootFlag == "" {
+				return nil, fmt.Errorf("--root requires a path")
+			}
+			args = args[1:]
+		case arg == "--config" || arg == "-config":
+			if len(args) < 2 || args[1] == "" {
+				return nil, fmt.Errorf("%s requires a path", arg)
+			}
+			os.Setenv(config.ConfigEnv, args[1])
+			args = args[2:]
+		case strings.HasPrefix(arg, "--config=") || strings.HasPrefix(arg, "-config="):
+			_, path, _ := strings.Cut(arg, "=")
+			if path == "" {
+				return nil, fmt.Errorf("--config requires a path")
+			}
+			os.Setenv(config.ConfigEnv, path)
+			args = args[1:]
+		case arg == "--verbose" || arg == "-verbose":
+			os.Setenv(runner.VerboseEnv, "1")
+			args = args[1:]
+		default:
+			return args, nil
+		}
+	}
+	return args, nil
+}
+
 func printUsage() {
 	fmt.Println(`orchestrator - Parallel Claude Code worker orchestration via tmux
 
@@ -128,6 +203,40 @@ COMMANDS
   add-issue  Add an issue to config mid-run
   version    Show version information
 
+REPOSITORY COMMANDS
+  init         Bootstrap config/repos.json from local git checkouts
+  scan         Scan git status of all repos in config/repos.json
+  repo-status  Table of branch and working tree status for all repos
+  health       Repo health scores (0-100) from the last scan, worst first
+  build        Build a repo (--lint adds go vet / staticcheck / npm lint)
+  bench        Run Go benchmarks and compare against a stored baseline
+  test         Run tests for one repo (--race runs the Go race detector)
+  test-all     Run tests across all repos (-j N for parallel)
+  run          Run any command in one repo or all of them (run --all -- git gc)
+  sync         Fetch and fast-forward one repo or all of them (sync --all)
+  report       Regenerate state/test-report.html from the last test-all run
+  logs         List, view, tail, or clean build/test/sync log files
+  task         Manage tasks in tasks/*.md (list, start, complete)
+  whoami       Print the name to pass to task list --assigned for your tasks
+  config       Manage config/repos.json (validate, add-repo, remove-repo)
+  completion   Print a bash, zsh, or fish completion script
+
+GLOBAL OPTIONS
+  --root PATH  Orchestrator checkout to use for config/, tasks/, and state/.
+               The root is taken from, in order: --root, $ORCHESTRATOR_ROOT,
+               the nearest parent of the current directory with go.mod and
+               config/repos.json, and finally the built-in default location.
+               Must come before the command: orchestrator --root PATH scan
+  --config PATH
+               Read repositories from PATH instead of config/repos.json
+               under the root. Same as setting ORCHESTRATOR_CONFIG=PATH.
+               A repos.local.json beside PATH is still merged over it.
+               Must come before the command; after it, --config is the
+               issue config file of launch and similar commands.
+  --verbose    Also copy the output of every build, test, and sync command
+               to stderr, each line prefixed with [repo]. Same as setting
+               ORCHESTRATOR_VERBOSE=1.
+
 EXAMPLES
 
   # Launch from epic issue (recommended - run from within the repo)
@@ -182,14 +291,14 @@ LOG FILES
 Use "orchestrator <command> -h" for command-specific options.`)
 }
 
+// printVersion prints the version and build details set via ldflags; see
+// internal/version.
 func printVersion() {
-	fmt.Printf("orchestrator %s\n", Version)
-	if GitCommit != "unknown" {
-		fmt.Printf("  commit: %s\n", GitCommit)
-	}
-	if BuildDate != "unknown" {
-		fmt.Printf("  built:  %s\n", BuildDate)
+	fmt.Printf("orchestrator %s\n", version.Version)
+	if version.Commit != "unknown" {
+		fmt.Printf("  commit: %s\n", version.Commit)
 	}
+	fmt.Printf("  built:  %s\n", version.BuildDate)
 }
 
 func cmdLaunch(args []string) {
@@ -369,7 +478,7 @@ OPTIONS`)
 	orchestrator.SetGlobalEventBroadcaster(events)
 
 	// Set version for dashboard
-	orchestrator.Version = Version
+	orchestrator.Version = version.Version
 
 	fmt.Println("+" + strings.Repeat("=", 58) + "+")
 	fmt.Println("|  Unified Orchestrator — Multi-Project                    |")
@@ -703,7 +812,7 @@ func cmdReview(args []string) {
 	// Create event broadcaster
 	events := orchestrator.NewEventBroadcaster(primaryCfg.Project)
 	orchestrator.SetGlobalEventBroadcaster(events)
-	orchestrator.Version = Version
+	orchestrator.Version = version.Version
 
 	fmt.Println("+" + strings.Repeat("=", 58) + "+")
 	fmt.Println("|  Orchestrator Review Gate                                |")
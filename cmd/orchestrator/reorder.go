@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/PaulSnow/orchestrator/internal/tasks"
+)
+
+// Keys decoded by readKey. Any other key is returned as the character typed.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyEnter     = "enter"
+	keySpace     = "space"
+	keyBackspace = "backspace"
+	keyEsc       = "esc"
+	keyQuit      = "quit"
+)
+
+// reorderPicker is the state of task reorder --interactive: a cursor over
+// the backlog, whether the task under it is grabbed so the arrow keys drag
+// it, and any rank being typed for it.
+type reorderPicker struct {
+	mgr     *tasks.Manager
+	groups  []tasks.BacklogGroup
+	rows    []tasks.Task // every backlog task, in group order
+	cursor  int
+	grabbed bool
+	rank    string
+	message string
+	moved   bool // whether any task changed place
+}
+
+// reorderInteractive opens a full-screen picker over the backlog. The arrow
+// keys, or j and k, select a task; space or Enter grabs it so the arrow keys
+// drag it, and drops it again. Typing a number and pressing Enter moves the
+// selected task to that rank. A sharded backlog is listed shard by shard,
+// and tasks move within their shard. q, Esc, or Ctrl-C quits.
+func reorderInteractive(mgr *tasks.Manager) {
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		fmt.Fprintln(os.Stderr, "Error: --interactive needs a terminal; use --up, --down, --top, or --bottom")
+		os.Exit(1)
+	}
+	p := &reorderPicker{mgr: mgr}
+	if err := p.load(""); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading backlog: %v\n", err)
+		os.Exit(1)
+	}
+	if len(p.rows) == 0 {
+		fmt.Println("Backlog is empty.")
+		return
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// Draw on the alternate screen with the cursor hidden, so the shell's
+	// scrollback is left as it was.
+	fmt.Print("\033[?1049h\033[?25l")
+	err = p.run(bufio.NewReader(os.Stdin), os.Stdout, out)
+	fmt.Print("\033[?25h\033[?1049l")
+	term.Restore(in, state)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !p.moved {
+		fmt.Println("Backlog unchanged.")
+		return
+	}
+	fmt.Println("Backlog reordered.")
+}
+
+// run draws the picker and handles keys until the user quits or input ends.
+func (p *reorderPicker) run(in *bufio.Reader, w io.Writer, fd int) error {
+	for {
+		width, height, err := term.GetSize(fd)
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		p.draw(w, width, height)
+		key, err := readKey(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !p.handle(key) {
+			return nil
+		}
+	}
+}
+
+// load rereads the backlog and puts the cursor on the task with the given
+// ID, if there is one.
+func (p *reorderPicker) load(id string) error {
+	groups, err := p.mgr.BacklogGroups()
+	if err != nil {
+		return err
+	}
+	p.groups, p.rows = groups, nil
+	for _, g := range groups {
+		p.rows = append(p.rows, g.Tasks...)
+	}
+	for i, t := range p.rows {
+		if t.ID == id {
+			p.cursor = i
+		}
+	}
+	p.cursor = min(p.cursor, max(len(p.rows)-1, 0))
+	return nil
+}
+
+// handle applies one key and reports whether the picker should keep running.
+func (p *reorderPicker) handle(key string) bool {
+	p.message = ""
+	switch {
+	case key == keyQuit:
+		return false
+	case key == keyEsc:
+		if !p.grabbed && p.rank == "" {
+			return false
+		}
+		p.grabbed, p.rank = false, ""
+	case key == keyUp || key == keyDown:
+		step := 1
+		if key == keyUp {
+			step = -1
+		}
+		if p.grabbed {
+			p.move(func(id string) (int, error) { return p.mgr.MoveTask(id, step) })
+		} else {
+			p.cursor = min(max(p.cursor+step, 0), len(p.rows)-1)
+		}
+	case key == keyEnter && p.rank != "":
+		rank, _ := strconv.Atoi(p.rank)
+		p.rank = ""
+		p.move(func(id string) (int, error) { return p.mgr.MoveTaskTo(id, rank) })
+	case key == keyEnter || key == keySpace:
+		p.grabbed = !p.grabbed
+	case key == keyBackspace && p.rank != "":
+		p.rank = p.rank[:len(p.rank)-1]
+	case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+		if len(p.rank) < 6 {
+			p.rank += key
+		}
+	}
+	return true
+}
+
+// move applies a move to the task under the cursor, then reloads the
+// backlog with the cursor still on that task.
+func (p *reorderPicker) move(do func(id string) (int, error)) {
+	id := p.rows[p.cursor].ID
+	_, before := p.position(p.cursor)
+	after, err := do(id)
+	if err != nil {
+		p.message = "Error: " + err.Error()
+		return
+	}
+	if after != before {
+		p.moved = true
+	}
+	if err := p.load(id); err != nil {
+		p.message = "Error reading backlog: " + err.Error()
+	}
+}
+
+// position returns the group of row i and its 1-based rank within it.
+func (p *reorderPicker) position(i int) (tasks.BacklogGroup, int) {
+	for _, g := range p.groups {
+		if i < len(g.Tasks) {
+			return g, i + 1
+		}
+		i -= len(g.Tasks)
+	}
+	return tasks.BacklogGroup{}, 0
+}
+
+// draw renders the picker for a width by height terminal, scrolling so the
+// cursor stays in view. Raw mode needs explicit carriage returns.
+func (p *reorderPicker) draw(w io.Writer, width, height int) {
+	var lines []string
+	cursorLine, row := 0, 0
+	for _, g := range p.groups {
+		if len(p.groups) > 1 {
+			lines = append(lines, g.File)
+		}
+		for i, t := range g.Tasks {
+			mark := "  "
+			if row == p.cursor {
+				cursorLine, mark = len(lines), "> "
+				if p.grabbed {
+					mark = "* "
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%s%3d. [%s] %s", mark, i+1, t.ID, t.Title))
+			row++
+		}
+	}
+
+	status := p.message
+	switch {
+	case p.rank != "":
+		status = "Move to rank " + p.rank + ", then press Enter"
+	case p.grabbed:
+		status = "Moving " + p.rows[p.cursor].ID + ": up/down to move, space to drop"
+	}
+
+	view := max(height-3, 1)
+	top := min(max(cursorLine-view/2, 0), max(len(lines)-view, 0))
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	b.WriteString(fitWidth("up/down select, space grab/drop, number+Enter set rank, q quit", width) + "\r\n\r\n")
+	for _, line := range lines[top:min(top+view, len(lines))] {
+		b.WriteString(fitWidth(line, width) + "\r\n")
+	}
+	b.WriteString(fitWidth(status, width))
+	io.WriteString(w, b.String())
+}
+
+// fitWidth cuts s to at most width characters so lines never wrap.
+func fitWidth(s string, width int) string {
+	if r := []rune(s); len(r) > width {
+		return string(r[:max(width, 0)])
+	}
+	return s
+}
+
+// readKey reads one key press from a terminal in raw mode.
+func readKey(in *bufio.Reader) (string, error) {
+	b, err := in.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 3, 4, 'q': // Ctrl-C, Ctrl-D
+		return keyQuit, nil
+	case '\r', '\n':
+		return keyEnter, nil
+	case ' ':
+		return keySpace, nil
+	case 8, 127:
+		return keyBackspace, nil
+	case 'k':
+		return keyUp, nil
+	case 'j':
+		return keyDown, nil
+	case 27:
+		// An arrow key arrives as ESC [ A or ESC O A in a single read, so a
+		// lone Esc has nothing buffered after it.
+		if in.Buffered() < 2 {
+			return keyEsc, nil
+		}
+		if next, _ := in.ReadByte(); next != '[' && next != 'O' {
+			return keyEsc, nil
+		}
+		switch code, _ := in.ReadByte(); code {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		}
+		return "", nil
+	}
+	return string(b), nil
+}
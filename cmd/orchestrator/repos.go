@@ -0,0 +1,827 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/orchestrator"
+	"github.com/PaulSnow/orchestrator/internal/repos"
+	"github.com/PaulSnow/orchestrator/internal/runner"
+)
+
+// knownRoot is the checkout location used when no other root can be found.
+const knownRoot = "/home/paul/go/src/github.com/PaulSnow/orchestrator"
+
+// rootFlag holds the global --root flag, if given.
+var rootFlag string
+
+// orchestratorRoot returns the orchestrator repository root. It is resolved
+// in order from the --root flag, the ORCHESTRATOR_ROOT environment variable,
+// walk-up discovery from the current directory, and finally knownRoot.
+func orchestratorRoot() string {
+	if rootFlag != "" {
+		return rootFlag
+	}
+	if env := os.Getenv("ORCHESTRATOR_ROOT"); env != "" {
+		return env
+	}
+	return findRoot()
+}
+
+// findRoot walks up from the current directory looking for the orchestrator
+// repository root (a directory with both go.mod and config/repos.json).
+func findRoot() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return knownRoot
+	}
+	for {
+		if fileExists(filepath.Join(dir, "go.mod")) && fileExists(filepath.Join(dir, "config", "repos.json")) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return knownRoot
+		}
+		dir = parent
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// loadRepoConfig loads repos.json from the orchestrator root, exiting on
+// failure. Validation problems are printed as warnings.
+func loadRepoConfig() *config.Config {
+	cfg, err := config.Load(orchestratorRoot())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	for _, w := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: repos.json: %v\n", w)
+	}
+	return cfg
+}
+
+// selectRepos returns the repositories a command should operate on: all
+// non-archived repos (or all repos with includeArchived), narrowed to those
+// carrying tag when it is non-empty.
+func selectRepos(cfg *config.Config, tag string, includeArchived bool) []config.RepoConfig {
+	all := cfg.AllRepos()
+	if includeArchived {
+		all = cfg.AllReposIncludingArchived()
+	}
+	if tag == "" {
+		return all
+	}
+	var selected []config.RepoConfig
+	for _, r := range all {
+		if r.HasTag(tag) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+func cmdScan(args []string) {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator scan - Scan all managed repositories
+
+DESCRIPTION
+  Checks the git status of every repository in config/repos.json and
+  writes the results to state/repo-status.json, with a readable table in
+  state/repo-status.txt. Changes relative to the previous scan are listed
+  in a CHANGED section below the summary. Each repository's health score
+  (see orchestrator health) is written to state/health.json.
+
+  Archived repositories are skipped unless --include-archived is given. With
+  --tag, only repositories carrying that tag are scanned; entries for other
+  repositories in state/repo-status.json are left as they were.
+
+  --write-graphviz writes a Graphviz DOT graph of the scanned repositories
+  to the given file, with an edge from each Go repository to the managed
+  repositories it requires directly (from go list -m -json all, matched
+  against each repository's remote). Render it with: dot -Tsvg deps.dot
+
+  Ctrl-C (or SIGTERM) stops the scan after the repository in progress. The
+  repositories scanned so far are written as with --tag, and the
+  command exits with status 1.
+
+  --check-orphans skips the status scan and instead walks the given
+  directory for git checkouts that are not in repos.json, matching each
+  origin remote against the configured remotes (SSH and HTTPS URLs for the
+  same repository match). Each one is printed as [ORPHAN]; with --adopt it
+  is also added to repos.json with its detected language and branch.
+
+USAGE
+  orchestrator scan
+  orchestrator scan --tag critical
+  orchestrator scan --write-graphviz state/deps.dot
+  orchestrator scan --check-orphans ~/src
+  orchestrator scan --check-orphans ~/src --adopt
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	tag := fs.String("tag", "", "Only scan repositories with this tag")
+	includeArchived := fs.Bool("include-archived", false, "Also scan archived repositories")
+	graphviz := fs.String("write-graphviz", "", "Write a DOT dependency graph of the scanned repos to this file")
+	orphanDir := fs.String("check-orphans", "", "List git checkouts under this directory that are not in repos.json")
+	adopt := fs.Bool("adopt", false, "With --check-orphans, add the orphans to repos.json")
+	fs.Parse(args)
+
+	if *adopt && *orphanDir == "" {
+		fmt.Fprintln(os.Stderr, "Error: --adopt requires --check-orphans")
+		os.Exit(1)
+	}
+	cfg := loadRepoConfig()
+	if *orphanDir != "" {
+		checkOrphans(cfg, *orphanDir, *adopt)
+		return
+	}
+	selected := selectRepos(cfg, *tag, *includeArchived)
+	fmt.Printf("Scanning %d repositories...\n", len(selected))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// The repository being scanned finishes its git commands before the scan
+	// stops, so restore default signal handling once interrupted: a second
+	// Ctrl-C then kills a scan stuck on a slow git command.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	previous, _ := repos.LoadStatusFile(cfg.RootPath)
+	statuses, scanErr := repos.ScanReposContext(ctx, selected, func(s repos.RepoStatus) {
+		fmt.Printf("  Scanning %s... done (%s)\n", s.Name, repos.StatusColumn(s))
+	})
+
+	snapshot := statuses
+	if *tag != "" || *includeArchived || scanErr != nil {
+		snapshot, previous = mergeStatuses(previous, statuses)
+	}
+	if err := repos.WriteStatusFile(cfg.RootPath, snapshot); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing status file: %v\n", err)
+		os.Exit(1)
+	}
+	if err := repos.WriteHealthFile(cfg.RootPath, snapshot); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing health file: %v\n", err)
+		os.Exit(1)
+	}
+
+	clean, dirty, missing := 0, 0, 0
+	for _, s := range statuses {
+		switch {
+		case !s.Exists:
+			missing++
+		case s.Clean:
+			clean++
+		default:
+			dirty++
+		}
+	}
+
+	fmt.Printf("\nSummary: %d clean, %d dirty, %d missing (total: %d)\n",
+		clean, dirty, missing, len(statuses))
+	fmt.Println("State written to state/repo-status.json, state/repo-status.txt, and state/health.json")
+
+	if scanErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: scan interrupted after %d of %d repositories: %v\n", len(statuses), len(selected), scanErr)
+		os.Exit(1)
+	}
+
+	if *graphviz != "" {
+		repos.ResolveDependencies(selected, statuses)
+		if err := repos.WriteDotFile(*graphviz, statuses); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing dependency graph: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Dependency graph written to %s\n", *graphviz)
+	}
+
+	if previous != nil {
+		printChanges(repos.DiffStatus(previous, statuses))
+	}
+}
+
+// mergeStatuses overlays a partial scan onto the previous snapshot. It returns
+// the merged snapshot and the previous entries for just the scanned repos, so
+// that unscanned repos are not reported as removed.
+func mergeStatuses(previous, scanned []repos.RepoStatus) (merged, before []repos.RepoStatus) {
+	byName := make(map[string]repos.RepoStatus, len(scanned))
+	for _, s := range scanned {
+		byName[s.Name] = s
+	}
+	if previous != nil {
+		before = []repos.RepoStatus{}
+	}
+
+	for _, p := range previous {
+		if s, ok := byName[p.Name]; ok {
+			merged = append(merged, s)
+			before = append(before, p)
+			delete(byName, p.Name)
+		} else {
+			merged = append(merged, p)
+		}
+	}
+	for _, s := range scanned {
+		if _, ok := byName[s.Name]; ok {
+			merged = append(merged, s)
+		}
+	}
+	return merged, before
+}
+
+// printChanges prints the CHANGED section for a scan diff.
+func printChanges(changes []repos.StatusChange) {
+	fmt.Println()
+	if len(changes) == 0 {
+		fmt.Println("CHANGED: none since last scan")
+		return
+	}
+	fmt.Println("CHANGED:")
+	for _, c := range changes {
+		switch c.Field {
+		case "added":
+			fmt.Printf("  %s: added\n", c.Name)
+		case "removed":
+			fmt.Printf("  %s: removed\n", c.Name)
+		default:
+			fmt.Printf("  %s: %s %q -> %q\n", c.Name, c.Field, c.OldValue, c.NewValue)
+		}
+	}
+}
+
+// lockedWriter serializes writes from concurrent goroutines.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Printf(format string, a ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, format, a...)
+}
+
+func cmdTestAll(args []string) {
+	fs := flag.NewFlagSet("test-all", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator test-all - Run tests across all managed repositories
+
+DESCRIPTION
+  Runs the language-appropriate test command in every repository with a
+  known language. Output for each repo goes to /tmp/orchestrator-test-<repo>.log
+  and a summary is written to state/test-results.json.
+
+  With -j N, up to N repositories are tested concurrently and results are
+  printed as they complete. -j 0 uses one job per CPU. Archived repositories
+  are skipped unless --include-archived is given.
+
+  With --append, this run's results are merged into the existing
+  state/test-results.json instead of replacing it: a repository tested
+  again keeps only its latest result, and the others are left as they were.
+  The text summary and HTML report cover the merged results.
+
+  With --junit PATH, the results written to state/test-results.json are also
+  written to PATH as a JUnit XML report, one <testsuite> per repository, for
+  CI systems such as GitHub Actions and Jenkins.
+
+USAGE
+  orchestrator test-all
+  orchestrator test-all -j 4
+  orchestrator test-all --tag critical
+  orchestrator test-all --tag ready --append
+  orchestrator test-all --junit state/junit.xml
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	jobs := fs.Int("jobs", 1, "Number of repos to test concurrently (0 = NumCPU)")
+	fs.IntVar(jobs, "j", 1, "Shorthand for --jobs")
+	tag := fs.String("tag", "", "Only test repositories with this tag")
+	includeArchived := fs.Bool("include-archived", false, "Also test archived repositories")
+	appendResults := fs.Bool("append", false, "Merge results into the existing state/test-results.json")
+	junit := fs.String("junit", "", "Also write the results as JUnit XML to `path`")
+	fs.Parse(args)
+
+	if *jobs <= 0 {
+		*jobs = runtime.NumCPU()
+	}
+
+	cfg := loadRepoConfig()
+	allRepos := selectRepos(cfg, *tag, *includeArchived)
+	fmt.Printf("Running tests across %d repositories (%d concurrent)...\n", len(allRepos), *jobs)
+	fmt.Println("All output redirected to /tmp/orchestrator-test-*.log files.")
+	fmt.Println()
+
+	out := &lockedWriter{w: os.Stdout}
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []runner.Result
+	)
+	passed, failed, skipped := 0, 0, 0
+	sem := make(chan struct{}, *jobs)
+	start := time.Now()
+
+	for _, repo := range allRepos {
+		if repo.Language == "unknown" {
+			out.Printf("  [SKIP]  %s (unknown language)\n", repo.Name)
+			skipped++
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(repo config.RepoConfig) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := runner.TestRepo(repo)
+
+			mu.Lock()
+			results = append(results, result)
+			if result.Success {
+				passed++
+			} else {
+				failed++
+			}
+			mu.Unlock()
+
+			status := "PASS"
+			if !result.Success {
+				status = "FAIL"
+			}
+			out.Printf("  [%s] %s (%.1fs) -> %s\n", status, repo.Name, result.Duration, result.LogFile)
+		}(repo)
+	}
+	wg.Wait()
+	wall := time.Since(start).Seconds()
+
+	report := writeTestResults(cfg.RootPath, results, *appendResults)
+	if err := runner.WriteHTMLReport(cfg.RootPath, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing HTML report: %v\n", err)
+	}
+	if *junit != "" {
+		if err := runner.WriteJUnitXMLFile(*junit, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JUnit XML: %v\n", err)
+			*junit = ""
+		}
+	}
+
+	var serial float64
+	for _, r := range results {
+		serial += r.Duration
+	}
+
+	fmt.Printf("\nResults: %d passed, %d failed, %d skipped (total: %d)\n",
+		passed, failed, skipped, len(allRepos))
+	fmt.Printf("Wall time: %.1fs (serial: %.1fs, saved: %.1fs)\n", wall, serial, max(serial-wall, 0))
+	fmt.Println("Results written to state/test-results.json and state/test-report.html")
+	if *junit != "" {
+		fmt.Printf("JUnit XML written to %s\n", *junit)
+	}
+}
+
+// writeTestResults writes state/test-results.json and state/test-results.txt,
+// merging into the existing JSON when appendResults is set, and returns the
+// results the files now hold.
+func writeTestResults(root string, results []runner.Result, appendResults bool) []runner.Result {
+	report := results
+	if appendResults {
+		if err := runner.AppendResults(root, "test-results.json", results); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+		} else if merged, err := runner.ReadResults(root, "test-results.json"); err == nil {
+			report = merged
+		}
+	} else if err := runner.WriteResults(root, "test-results.json", results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+	}
+	if err := runner.WriteResultsText(root, "test-results.txt", report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+	}
+	return report
+}
+
+func cmdInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator init - Bootstrap a repos.json from local checkouts
+
+DESCRIPTION
+  Walks --scan-dir looking for git repositories and emits a starter
+  repos.json. Language is detected from marker files (go.mod, package.json,
+  Cargo.toml, requirements.txt) and the remote from "git remote get-url origin".
+
+  Output goes to stdout unless --output is given. An existing output file is
+  never overwritten without --force.
+
+USAGE
+  orchestrator init --scan-dir ~/projects
+  orchestrator init --scan-dir ~/projects --output config/repos.json
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	scanDir := fs.String("scan-dir", ".", "Directory to search for git repositories")
+	output := fs.String("output", "", "Write repos.json to this path instead of stdout")
+	force := fs.Bool("force", false, "Overwrite an existing output file")
+	fs.Parse(args)
+
+	found, err := repos.DiscoverRepos(*scanDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning %s: %v\n", *scanDir, err)
+		os.Exit(1)
+	}
+	if found == nil {
+		found = []config.RepoConfig{}
+	}
+	reposFile := config.ReposFile{Repositories: found}
+
+	if *output == "" {
+		data, _ := json.MarshalIndent(reposFile, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if fileExists(*output) && !*force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists (use --force to overwrite)\n", *output)
+		os.Exit(1)
+	}
+	if err := orchestrator.AtomicWrite(*output, reposFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d repositories to %s\n", len(found), *output)
+}
+
+func cmdReport(args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator report - Regenerate the HTML test report
+
+DESCRIPTION
+  Reads state/test-results.json from the last test-all run and writes
+  state/test-report.html without re-running any tests.
+
+USAGE
+  orchestrator report`)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	root := orchestratorRoot()
+	results, err := runner.ReadResults(root, "test-results.json")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading test results: %v\n", err)
+		os.Exit(1)
+	}
+	if err := runner.WriteHTMLReport(root, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing HTML report: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Report for %d results written to state/test-report.html\n", len(results))
+}
+
+func cmdHealth(args []string) {
+	fs := flag.NewFlagSet("health", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator health - Show repository health scores
+
+DESCRIPTION
+  Reads state/health.json from the last orchestrator scan and prints each
+  repository's health score, lowest first, with the issues behind it and
+  the overall average.
+
+  Scores start at 100 and lose:
+    20  missing            the local directory does not exist
+    10  dirty              uncommitted changes
+     5  per untracked file (at most 20)
+    10  behind             the upstream has commits HEAD lacks
+     5  no upstream        no tracking branch configured
+    15  conflicts          unmerged files
+    10  broken symlinks    tracked symlinks with missing targets
+
+USAGE
+  orchestrator health`)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	report, err := repos.LoadHealthFile(orchestratorRoot())
+	if os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "Error: no state/health.json (run orchestrator scan first)")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	repos.WriteHealthTable(os.Stdout, report)
+}
+
+func cmdRepoStatus(args []string) {
+	fs := flag.NewFlagSet("repo-status", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator repo-status - Show git status of all managed repositories
+
+DESCRIPTION
+  Scans every repository in config/repos.json and prints a table with the
+  current branch, working tree status, and last commit.
+
+  STATUS column:
+    clean      nothing to commit, no stashes
+    NS/NM/NU   N stashes / N modified / N untracked (stashes shown only if present)
+    MISSING    local directory does not exist
+    CONFLICT   unmerged paths from an unfinished merge or rebase
+    NO-UP      current branch has no upstream tracking branch
+    [SYMLINKS] a tracked symlink points at a path that does not exist
+    [LARGE]    a staged file is over 1 MB, or $ORCHESTRATOR_LARGE_FILE_THRESHOLD bytes
+    [HOOKS]    a git hook listed in expected_hooks is not installed
+
+  CI column: GH (GitHub Actions), GL (GitLab CI), J (Jenkins), or - for none,
+  detected from .github/workflows/, .gitlab-ci.yml, or a Jenkinsfile, with
+  ci.system in repos.json as the fallback.
+
+  --sort orders the table by name, branch, status (dirty first, then
+  missing, then clean), or age (oldest last commit first). --filter shows
+  only dirty, clean, or missing repos. Archived repositories are listed in a
+  separate ARCHIVED section at the bottom.
+
+  --from-file skips the live scan and shows the snapshot last written to
+  state/repo-status.json by orchestrator scan.
+
+  --show-go-version adds a GO column with the go directive from each
+  repository's go.mod, or - for repositories without one.
+
+USAGE
+  orchestrator repo-status
+  orchestrator repo-status --sort age
+  orchestrator repo-status --filter dirty
+  orchestrator repo-status --from-file
+  orchestrator repo-status --show-go-version
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	sortBy := fs.String("sort", "", "Sort by name, branch, status, or age")
+	filter := fs.String("filter", "", "Show only dirty, clean, or missing repos")
+	fromFile := fs.Bool("from-file", false, "Show the last snapshot written by scan instead of scanning")
+	showGo := fs.Bool("show-go-version", false, "Add a GO column with the go.mod go version")
+	fs.Parse(args)
+
+	less, ok := statusSorts[*sortBy]
+	if *sortBy != "" && !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown sort %q (use name, branch, status, or age)\n", *sortBy)
+		os.Exit(1)
+	}
+	if *filter != "" && *filter != "dirty" && *filter != "clean" && *filter != "missing" {
+		fmt.Fprintf(os.Stderr, "Error: unknown filter %q (use dirty, clean, or missing)\n", *filter)
+		os.Exit(1)
+	}
+
+	cfg := loadRepoConfig()
+	if *fromFile {
+		printStatusSnapshot(cfg, *filter, less, *showGo)
+		return
+	}
+
+	statuses := filterAndSort(repos.ScanAll(cfg), *filter, less)
+	printStatusTable(os.Stdout, statuses, *showGo)
+
+	if archived := cfg.ArchivedRepos(); len(archived) > 0 {
+		var scanned []repos.RepoStatus
+		for _, r := range archived {
+			scanned = append(scanned, repos.ScanRepo(r))
+		}
+		if scanned = filterAndSort(scanned, *filter, less); len(scanned) > 0 {
+			fmt.Println("\nARCHIVED")
+			printStatusTable(os.Stdout, scanned, *showGo)
+		}
+	}
+}
+
+// checkOrphans prints the unmanaged checkouts under dir and, with adopt, adds
+// each to repos.json.
+func checkOrphans(cfg *config.Config, dir string, adopt bool) {
+	orphans, err := repos.FindOrphans(dir, cfg.AllReposIncludingArchived())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning %s: %v\n", dir, err)
+		os.Exit(1)
+	}
+	if len(orphans) == 0 {
+		fmt.Printf("No orphaned repositories under %s.\n", dir)
+		return
+	}
+
+	failed := false
+	for _, repo := range orphans {
+		origin := repo.RedactedRemote()
+		if origin == "" {
+			origin = "none"
+		}
+		fmt.Printf("[ORPHAN] %s (origin: %s)\n", repo.Local, origin)
+		if !adopt {
+			continue
+		}
+		fillDetected(&repo)
+		if err := config.AddRepo(cfg.RootPath, repo); err != nil {
+			fmt.Fprintf(os.Stderr, "  Error adopting %s: %v\n", repo.Local, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("  adopted as %s\n", repo.Name)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printStatusSnapshot prints the repo-status table from state/repo-status.json.
+func printStatusSnapshot(cfg *config.Config, filter string, less func(a, b repos.RepoStatus) bool, showGo bool) {
+	snapshot, err := repos.LoadStatusFile(cfg.RootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading snapshot (run orchestrator scan first): %v\n", err)
+		os.Exit(1)
+	}
+
+	var current, archived []repos.RepoStatus
+	var scannedAt time.Time
+	for _, s := range snapshot {
+		if s.ScannedAt.After(scannedAt) {
+			scannedAt = s.ScannedAt
+		}
+		if r, ok := cfg.GetRepo(s.Name); ok && r.Archived {
+			archived = append(archived, s)
+		} else {
+			current = append(current, s)
+		}
+	}
+
+	fmt.Printf("Snapshot from %s\n\n", scannedAt.Format(time.RFC3339))
+	printStatusTable(os.Stdout, filterAndSort(current, filter, less), showGo)
+	if archived = filterAndSort(archived, filter, less); len(archived) > 0 {
+		fmt.Println("\nARCHIVED")
+		printStatusTable(os.Stdout, archived, showGo)
+	}
+}
+
+// filterAndSort applies the repo-status --filter and --sort options.
+func filterAndSort(statuses []repos.RepoStatus, filter string, less func(a, b repos.RepoStatus) bool) []repos.RepoStatus {
+	if filter != "" {
+		var kept []repos.RepoStatus
+		for _, s := range statuses {
+			if statusState(s) == filter {
+				kept = append(kept, s)
+			}
+		}
+		statuses = kept
+	}
+	if less != nil {
+		sort.SliceStable(statuses, func(i, j int) bool { return less(statuses[i], statuses[j]) })
+	}
+	return statuses
+}
+
+// statusState returns "missing", "clean", or "dirty" for a scanned repo.
+func statusState(s repos.RepoStatus) string {
+	switch {
+	case !s.Exists:
+		return "missing"
+	case s.Clean:
+		return "clean"
+	default:
+		return "dirty"
+	}
+}
+
+// statusRank orders states for --sort status: dirty, then missing, then clean.
+var statusRank = map[string]int{"dirty": 0, "missing": 1, "clean": 2}
+
+// statusSorts are the --sort orderings for repo-status. Ties fall back to name.
+var statusSorts = map[string]func(a, b repos.RepoStatus) bool{
+	"name": func(a, b repos.RepoStatus) bool { return a.Name < b.Name },
+	"branch": func(a, b repos.RepoStatus) bool {
+		if a.Branch != b.Branch {
+			return a.Branch < b.Branch
+		}
+		return a.Name < b.Name
+	},
+	"status": func(a, b repos.RepoStatus) bool {
+		ra, rb := statusRank[statusState(a)], statusRank[statusState(b)]
+		if ra != rb {
+			return ra < rb
+		}
+		return a.Name < b.Name
+	},
+	"age": func(a, b repos.RepoStatus) bool {
+		// Repos without a commit time (missing, empty) sort last.
+		if a.LastCommitAt.IsZero() != b.LastCommitAt.IsZero() {
+			return !a.LastCommitAt.IsZero()
+		}
+		if !a.LastCommitAt.Equal(b.LastCommitAt) {
+			return a.LastCommitAt.Before(b.LastCommitAt)
+		}
+		return a.Name < b.Name
+	},
+}
+
+// printStatusTable writes the repo-status table for a set of scan results.
+func printStatusTable(w io.Writer, statuses []repos.RepoStatus, showGo bool) {
+	repos.WriteStatusTable(w, statuses, red, showGo)
+}
+
+func cmdBuild(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator build - Build a managed repository
+
+DESCRIPTION
+  Runs the language-appropriate build command in the repository. Output goes
+  to /tmp/orchestrator-build-<repo>.log.
+
+  With --lint, a successful build is followed by static analysis (go vet and
+  staticcheck for Go, "npm run lint" for JavaScript) logged to
+  /tmp/orchestrator-build-lint-<repo>.log. Lint failures fail the build.
+
+  With --tag instead of a repo name, every repository carrying that tag is
+  built in turn.
+
+USAGE
+  orchestrator build <repo>
+  orchestrator build <repo> --lint
+  orchestrator build --tag critical
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	lint := fs.Bool("lint", false, "Run static analysis after a successful build")
+	tag := fs.String("tag", "", "Build all repositories with this tag")
+	name := parseIDFlags(fs, args)
+	if (name == "") == (*tag == "") {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg := loadRepoConfig()
+	var targets []config.RepoConfig
+	if name != "" {
+		repo, ok := cfg.GetRepo(name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", name)
+			os.Exit(1)
+		}
+		targets = []config.RepoConfig{repo}
+	} else {
+		targets = cfg.ReposByTag(*tag)
+		if len(targets) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no repos tagged %s\n", *tag)
+			os.Exit(1)
+		}
+	}
+
+	failed := 0
+	for _, repo := range targets {
+		var result runner.Result
+		if *lint {
+			result = runner.BuildAndLintRepo(repo)
+		} else {
+			result = runner.BuildRepo(repo)
+		}
+
+		status := "PASS"
+		if !result.Success {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("[%s] %s (%.1fs) -> %s\n", status, repo.Name, result.Duration, result.LogFile)
+		if result.LintOutput != "" {
+			fmt.Printf("  lint -> %s\n", result.LintOutput)
+		}
+		for _, pkg := range result.ExcludedPackages {
+			fmt.Printf("  SKIP %s\n", pkg)
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
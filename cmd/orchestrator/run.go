@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/runner"
+)
+
+func cmdRun(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator run - Run a command in one or more managed repositories
+
+DESCRIPTION
+  Runs the command after "--" in each target repository's directory, one
+  repository at a time, and prints PASS or FAIL for each. Output goes to
+  /tmp/orchestrator-run-<repo>.log.
+
+  With --all, every non-archived repository is a target except those whose
+  directory does not exist and, unless --include-unknown is given, those
+  with language "unknown".
+
+USAGE
+  orchestrator run --repo myrepo -- go mod tidy
+  orchestrator run --all -- git gc
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	name := fs.String("repo", "", "Repository to run the command in")
+	all := fs.Bool("all", false, "Run the command in every repository")
+	includeUnknown := fs.Bool("include-unknown", false, "With --all, also run in repositories of unknown language")
+	fs.Parse(args)
+
+	command := fs.Args()
+	if (*name == "") == !*all || len(command) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg := loadRepoConfig()
+	var targets []config.RepoConfig
+	if *name != "" {
+		repo, ok := cfg.GetRepo(*name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", *name)
+			os.Exit(1)
+		}
+		targets = []config.RepoConfig{repo}
+	} else {
+		targets = cfg.AllRepos()
+	}
+
+	failed := 0
+	for _, repo := range targets {
+		if *all {
+			if _, err := os.Stat(repo.Local); err != nil {
+				fmt.Printf("[SKIP] %s (directory does not exist)\n", repo.Name)
+				continue
+			}
+			if repo.Language == "unknown" && !*includeUnknown {
+				fmt.Printf("[SKIP] %s (unknown language)\n", repo.Name)
+				continue
+			}
+		}
+
+		result := runner.RunInRepo(repo, command[0], command[1:], "run")
+		status := "PASS"
+		if !result.Success {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("[%s] %s (%.1fs) -> %s\n", status, repo.Name, result.Duration, result.LogFile)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
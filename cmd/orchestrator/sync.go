@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/runner"
+)
+
+func cmdSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator sync - Fetch and fast-forward managed repositories
+
+DESCRIPTION
+  Runs "git fetch origin" and then "git pull --ff-only" in each target
+  repository and prints one line per repository:
+
+    SYNCED      new commits were fast-forwarded
+    UP-TO-DATE  there was nothing to pull
+    MISSING     the repository directory does not exist
+    DIVERGED    the branch has diverged from its upstream; rebase or merge
+    FAIL        the fetch or pull failed for another reason
+
+  Output goes to /tmp/orchestrator-sync-fetch-<repo>.log and
+  /tmp/orchestrator-sync-pull-<repo>.log.
+
+USAGE
+  orchestrator sync myrepo
+  orchestrator sync --all
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	all := fs.Bool("all", false, "Sync every non-archived repository")
+	fs.Parse(args)
+
+	if (fs.NArg() == 0) == !*all || fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg := loadRepoConfig()
+	targets := cfg.AllRepos()
+	if !*all {
+		repo, ok := cfg.GetRepo(fs.Arg(0))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", fs.Arg(0))
+			os.Exit(1)
+		}
+		targets = []config.RepoConfig{repo}
+	}
+
+	failed := 0
+	for _, repo := range targets {
+		result := runner.SyncRepo(repo)
+		last := result.Last()
+		switch {
+		case result.WasUpToDate:
+			fmt.Printf("[UP-TO-DATE] %s (%.1fs)\n", repo.Name, result.Duration())
+		case result.Synced:
+			fmt.Printf("[SYNCED]     %s (%.1fs) -> %s\n", repo.Name, result.Duration(), last.LogFile)
+		case result.FailureKind == runner.FailureMissing:
+			fmt.Printf("[MISSING]    %s (%s does not exist)\n", repo.Name, repo.Local)
+		case result.FailureKind == runner.FailureFFOnlyConflict:
+			fmt.Printf("[DIVERGED]   %s -> %s\n", repo.Name, last.LogFile)
+		default:
+			fmt.Printf("[FAIL]       %s: %s failed (exit %d, %s) -> %s\n", repo.Name, last.Command, last.ExitCode, result.FailureKind, last.LogFile)
+		}
+		if !result.Synced {
+			failed++
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
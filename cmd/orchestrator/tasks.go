@@ -0,0 +1,1058 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/tasks"
+)
+
+func cmdTask(args []string) {
+	if len(args) < 1 {
+		printTaskUsage()
+		os.Exit(1)
+	}
+
+	sub := args[0]
+	subArgs := args[1:]
+
+	switch sub {
+	case "list":
+		cmdTaskList(subArgs)
+	case "start":
+		cmdTaskStart(subArgs)
+	case "complete":
+		cmdTaskComplete(subArgs)
+	case "pause":
+		cmdTaskPause(subArgs)
+	case "resume":
+		cmdTaskResume(subArgs)
+	case "edit":
+		cmdTaskEdit(subArgs)
+	case "import-github":
+		cmdTaskImportGitHub(subArgs)
+	case "import-csv":
+		cmdTaskImportCSV(subArgs)
+	case "bulk-start":
+		cmdTaskBulk("start", subArgs)
+	case "bulk-complete":
+		cmdTaskBulk("complete", subArgs)
+	case "gantt":
+		cmdTaskGantt(subArgs)
+	case "stats":
+		cmdTaskStats(subArgs)
+	case "check":
+		cmdTaskCheck(subArgs)
+	case "move":
+		cmdTaskMove(subArgs)
+	case "split":
+		cmdTaskSplit(subArgs)
+	case "merge":
+		cmdTaskMerge(subArgs)
+	case "reorder":
+		cmdTaskReorder(subArgs)
+	case "link":
+		cmdTaskLink(subArgs)
+	case "open":
+		cmdTaskOpen(subArgs)
+	case "assign":
+		cmdTaskAssign(subArgs)
+	case "unassign":
+		cmdTaskUnassign(subArgs)
+	case "export":
+		cmdTaskExport(subArgs)
+	case "shard-backlog":
+		cmdTaskShardBacklog(subArgs)
+	case "create":
+		cmdTaskCreate(subArgs)
+	case "templates":
+		cmdTaskTemplates(subArgs)
+	case "help", "-h", "--help":
+		printTaskUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown task command: %s\n", sub)
+		printTaskUsage()
+		os.Exit(1)
+	}
+}
+
+func printTaskUsage() {
+	fmt.Println(`orchestrator task - Manage tasks in tasks/*.md
+
+USAGE
+  orchestrator task list              List active and backlog tasks
+  orchestrator task list --completed  Also list completed tasks
+  orchestrator task list --group-by assigned
+                                      Group tasks by assignee
+  orchestrator task list --assigned <name>
+                                      Only tasks assigned to name (any case);
+                                      see "orchestrator whoami"
+  orchestrator task start <id>        Move a task from backlog to active
+  orchestrator task complete <id>     Move a task from active to completed
+  orchestrator task bulk-start [--tag T] [--repo R] [--all] [--dry-run]
+                                      Start every backlog task matching the filters
+  orchestrator task bulk-complete [--tag T] [--repo R] [--all] [--dry-run]
+                                      Complete every active task matching the filters
+  orchestrator task pause <id> [--reason "..."]
+                                      Park an active task in paused.md
+  orchestrator task resume <id>       Move a paused task back to active
+  orchestrator task edit <id>         Edit a task's markdown block in $EDITOR
+  orchestrator task move <id> --repo <name>
+                                      Reassign a task to another repository
+  orchestrator task split <id> --into N [--suffix "Part {n}"]
+                                      Replace a large task with N backlog subtasks
+  orchestrator task merge <id1> <id2> [--into id1|id2|new]
+                                      Combine duplicate tasks into one
+  orchestrator task reorder <id> --up N | --down N | --top | --bottom
+                                      Change a task's position in the backlog
+  orchestrator task reorder --interactive
+                                      Drag backlog tasks into order in a
+                                      full-screen picker
+  orchestrator task link <id> <url>   Record an issue, ticket, or PR URL on a task
+  orchestrator task open <id>         Open a task's first link in the browser
+  orchestrator task assign <id> <assignee>
+                                      Record who owns a task
+  orchestrator task unassign <id>     Clear a task's assignee
+  orchestrator task stats [--json]    Show counts, cycle time, and backlog age
+  orchestrator task check [--max-active-days N]
+                                      List tasks active more than N days (default 7);
+                                      exits 1 if there are any
+  orchestrator task gantt [--width N] [--json]
+                                      Chart task start and completion dates by day
+  orchestrator task export [--format csv|json|markdown] [--state backlog|active|paused|completed|all]
+                                      Write tasks to stdout
+  orchestrator task shard-backlog     Split backlog.md into tasks/backlog/*.md
+  orchestrator task create --template NAME [--var key=value ...] [--id ID] [--allow-custom-prefix]
+                                      Add a backlog task from tasks/templates/NAME.yaml
+  orchestrator task templates list    List available task templates
+  orchestrator task import-github --owner ORG --repo REPO [--label L] [--dry-run]
+                                      Add open GitHub issues to the backlog
+  orchestrator task import-csv <file.csv> [--dry-run]
+                                      Add tasks from a CSV with a header row of
+                                      title,repo,type,priority,description,due
+
+TASK IDS
+  The ID prefix names the task's source: GH-N for GitHub issues, LI-N for
+  Linear issues, and T-N for local tasks. task list shows the source before
+  each task. New IDs with any other prefix are rejected unless
+  --allow-custom-prefix is given.`)
+}
+
+func newTaskManager() *tasks.Manager {
+	return tasks.NewManager(orchestratorRoot())
+}
+
+func cmdTaskList(args []string) {
+	fs := flag.NewFlagSet("task list", flag.ExitOnError)
+	showCompleted := fs.Bool("completed", false, "Also show completed tasks")
+	groupBy := fs.String("group-by", "", "Group tasks by field instead of state (assigned)")
+	assigned := fs.String("assigned", "", "Only show tasks assigned to this name (case-insensitive)")
+	fs.Parse(args)
+
+	if *groupBy != "" && *groupBy != "assigned" {
+		fmt.Fprintf(os.Stderr, "Error: unknown --group-by %q (want assigned)\n", *groupBy)
+		os.Exit(1)
+	}
+
+	mgr := newTaskManager()
+	now := time.Now()
+
+	// Without --completed, --assigned is exactly TasksByAssignee: the active
+	// and backlog tasks assigned to the name.
+	var active, backlog, completed []tasks.Task
+	if *assigned != "" && !*showCompleted {
+		mine, err := mgr.TasksByAssignee(*assigned)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading tasks: %v\n", err)
+			os.Exit(1)
+		}
+		for _, t := range mine {
+			if t.State == "active" {
+				active = append(active, t)
+			} else {
+				backlog = append(backlog, t)
+			}
+		}
+	} else {
+		var err error
+		active, err = mgr.ListActive()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading active tasks: %v\n", err)
+			os.Exit(1)
+		}
+		// State lets formatTaskLine mark stuck tasks, even when grouped by assignee.
+		for i := range active {
+			active[i].State = "active"
+		}
+		backlog, err = mgr.ListBacklog()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading backlog: %v\n", err)
+			os.Exit(1)
+		}
+		if *showCompleted {
+			completed, err = mgr.ListCompleted()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading completed tasks: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *assigned != "" {
+			active = filterAssigned(active, *assigned)
+			backlog = filterAssigned(backlog, *assigned)
+			completed = filterAssigned(completed, *assigned)
+		}
+	}
+	// TasksByAssignee leaves out paused tasks, so they are always filtered here.
+	paused, err := mgr.ListPaused()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading paused tasks: %v\n", err)
+		os.Exit(1)
+	}
+	if *assigned != "" {
+		paused = filterAssigned(paused, *assigned)
+	}
+
+	if *groupBy == "assigned" {
+		var all []tasks.Task
+		all = append(all, active...)
+		all = append(all, paused...)
+		all = append(all, backlog...)
+		all = append(all, completed...)
+		printTasksByAssignee(all, now)
+		return
+	}
+
+	printTaskSection("ACTIVE", active, now)
+	fmt.Println()
+	printTaskSection("PAUSED", paused, now)
+	fmt.Println()
+	printTaskSection("BACKLOG", backlog, now)
+
+	if *showCompleted {
+		fmt.Println()
+		printTaskSection("COMPLETED", completed, now)
+	}
+}
+
+// filterAssigned returns the tasks in list assigned to assignee.
+func filterAssigned(list []tasks.Task, assignee string) []tasks.Task {
+	var out []tasks.Task
+	for _, t := range list {
+		if t.IsAssignedTo(assignee) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// cmdWhoami prints this machine's hostname, which task start records as
+// started_by, for use with task list --assigned and task assign.
+func cmdWhoami(args []string) {
+	if len(args) > 0 {
+		fmt.Println(`orchestrator whoami - Print the name to look up your own tasks by
+
+USAGE
+  orchestrator whoami
+  orchestrator task list --assigned "$(orchestrator whoami)"
+
+  Prints this machine's hostname, the same name task start records as
+  started_by.`)
+		if args[0] != "help" && args[0] != "-h" && args[0] != "--help" {
+			os.Exit(1)
+		}
+		return
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(host)
+}
+
+// printTasksByAssignee prints one section per assignee, sorted by name, with
+// unassigned tasks last.
+func printTasksByAssignee(list []tasks.Task, now time.Time) {
+	groups := map[string][]tasks.Task{}
+	var unassigned []tasks.Task
+	for _, t := range list {
+		if t.Assigned == "" {
+			unassigned = append(unassigned, t)
+			continue
+		}
+		groups[t.Assigned] = append(groups[t.Assigned], t)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		printTaskSection(name, groups[name], now)
+		fmt.Println()
+	}
+	printTaskSection("UNASSIGNED", unassigned, now)
+}
+
+func printTaskSection(heading string, list []tasks.Task, now time.Time) {
+	fmt.Printf("%s (%d)\n", heading, len(list))
+	if len(list) == 0 {
+		fmt.Println("  (none)")
+		return
+	}
+	for _, t := range list {
+		fmt.Println("  " + formatTaskLine(t, now))
+	}
+}
+
+// formatTaskLine renders a single task for list output, led by its source.
+func formatTaskLine(t tasks.Task, now time.Time) string {
+	line := fmt.Sprintf("%-7s [%s] %s", t.Source(), t.ID, t.Title)
+	if t.Repo != "" {
+		line += fmt.Sprintf(" (%s)", t.Repo)
+	}
+	if t.Priority != "" {
+		line += " " + t.Priority
+	}
+	if t.Completed != "" {
+		line += " completed " + t.Completed
+	} else if t.DueDate != "" {
+		line += " due " + t.DueDate
+	}
+	if t.Completed == "" && t.IsOverdue(now) {
+		line += " " + red("[OVERDUE]")
+	}
+	if t.State == "active" && t.IsStuck(now, tasks.DefaultMaxActiveDays) {
+		line += " " + red("[STUCK]")
+	}
+	return line
+}
+
+// cmdTaskCheck lists the tasks that have been active too long and exits 1 if
+// there are any, for use from cron or CI.
+func cmdTaskCheck(args []string) {
+	fs := flag.NewFlagSet("task check", flag.ExitOnError)
+	maxDays := fs.Int("max-active-days", tasks.DefaultMaxActiveDays, "Days a task may stay active before it counts as stuck")
+	fs.Parse(args)
+	if *maxDays < 0 || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task check [--max-active-days N]")
+		os.Exit(1)
+	}
+
+	stuck, err := newTaskManager().StuckTasks(*maxDays)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(stuck) == 0 {
+		fmt.Printf("No tasks active for more than %d days.\n", *maxDays)
+		return
+	}
+
+	now := time.Now()
+	fmt.Printf("STUCK (%d)\n", len(stuck))
+	for _, t := range stuck {
+		fmt.Printf("  [%s] %s - active %d days, since %s\n", t.ID, t.Title, t.DaysActive(now), t.StartedAt.Local().Format("2006-01-02"))
+	}
+	os.Exit(1)
+}
+
+func cmdTaskStart(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task start <id>")
+		os.Exit(1)
+	}
+	if err := newTaskManager().StartTask(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s moved to active.\n", args[0])
+}
+
+func cmdTaskComplete(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task complete <id>")
+		os.Exit(1)
+	}
+	if err := newTaskManager().CompleteTask(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s completed.\n", args[0])
+}
+
+// cmdTaskBulk starts backlog tasks or completes active tasks that match the
+// --tag and --repo filters, one at a time, stopping at the first failure.
+func cmdTaskBulk(action string, args []string) {
+	fs := flag.NewFlagSet("task bulk-"+action, flag.ExitOnError)
+	tag := fs.String("tag", "", "Only tasks with this tag")
+	repo := fs.String("repo", "", "Only tasks for this repository")
+	all := fs.Bool("all", false, "Act on every task when no filter is given")
+	dryRun := fs.Bool("dry-run", false, "List matching tasks without changing them")
+	fs.Parse(args)
+
+	if *tag == "" && *repo == "" && !*all {
+		fmt.Fprintf(os.Stderr, "Error: give --tag or --repo, or --all to %s every task\n", action)
+		os.Exit(1)
+	}
+
+	mgr := newTaskManager()
+	list, apply, done := mgr.ListBacklog, mgr.StartTask, "Started"
+	if action == "complete" {
+		list, apply, done = mgr.ListActive, mgr.CompleteTask, "Completed"
+	}
+	candidates, err := list()
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error reading tasks: %v\n", err)
+		os.Exit(1)
+	}
+
+	count := 0
+	for _, t := range candidates {
+		if (*tag != "" && !t.HasTag(*tag)) || (*repo != "" && t.Repo != *repo) {
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("[DRY-RUN] would %s [%s] %s\n", action, t.ID, t.Title)
+			count++
+			continue
+		}
+		if err := apply(t.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s %s: %v\n", action, t.ID, err)
+			fmt.Fprintf(os.Stderr, "%s %d tasks before the failure\n", done, count)
+			os.Exit(1)
+		}
+		fmt.Printf("  [%s] %s\n", t.ID, t.Title)
+		count++
+	}
+
+	if *dryRun {
+		fmt.Printf("[DRY-RUN] %d tasks would be %s\n", count, strings.ToLower(done))
+		return
+	}
+	fmt.Printf("%s %d tasks\n", done, count)
+}
+
+// parseIDFlags parses flags that may appear before or after a positional
+// task ID and returns the ID, or "" if none was given.
+func parseIDFlags(fs *flag.FlagSet, args []string) string {
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return ""
+	}
+	id := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	return id
+}
+
+func cmdTaskPause(args []string) {
+	fs := flag.NewFlagSet("task pause", flag.ExitOnError)
+	reason := fs.String("reason", "", "Why the task is being paused")
+	id := parseIDFlags(fs, args)
+	if id == "" {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task pause <id> [--reason \"...\"]")
+		os.Exit(1)
+	}
+	if err := newTaskManager().PauseTask(id, *reason); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s paused.\n", id)
+}
+
+func cmdTaskResume(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task resume <id>")
+		os.Exit(1)
+	}
+	if err := newTaskManager().ResumeTask(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s resumed.\n", args[0])
+}
+
+func cmdTaskEdit(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task edit <id>")
+		os.Exit(1)
+	}
+	id := args[0]
+	mgr := newTaskManager()
+
+	filename, block, err := mgr.TaskBlock(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	tmp, err := os.CreateTemp("", "orchestrator-task-*.md")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating temp file: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.Remove(tmp.Name())
+	tmp.WriteString(block)
+	tmp.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], tmp.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Editor exited with error (%v); task not changed.\n", err)
+		os.Exit(1)
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading edited task: %v\n", err)
+		os.Exit(1)
+	}
+	if string(edited) == block {
+		fmt.Println("No changes.")
+		return
+	}
+
+	if err := mgr.ReplaceTaskBlock(filename, id, string(edited)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v; task not changed.\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s updated in %s.\n", id, filename)
+}
+
+func cmdTaskImportGitHub(args []string) {
+	fs := flag.NewFlagSet("task import-github", flag.ExitOnError)
+	owner := fs.String("owner", "", "GitHub organization or user (required)")
+	repo := fs.String("repo", "", "GitHub repository name (required)")
+	label := fs.String("label", "", "Only import issues with this label")
+	dryRun := fs.Bool("dry-run", false, "Print the tasks that would be added without writing")
+	fs.Parse(args)
+
+	if *owner == "" || *repo == "" {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task import-github --owner ORG --repo REPO [--label L] [--dry-run]")
+		fmt.Fprintln(os.Stderr, "Set GITHUB_TOKEN to authenticate.")
+		os.Exit(1)
+	}
+
+	issues, err := tasks.FetchGitHubIssues(*owner, *repo, *label, os.Getenv("GITHUB_TOKEN"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	mgr := newTaskManager()
+	added, skipped := 0, 0
+	for _, t := range issues {
+		if _, _, err := mgr.FindTask(t.ID); err == nil {
+			skipped++
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("  would add [%s] %s\n", t.ID, t.Title)
+			added++
+			continue
+		}
+		if _, err := mgr.AddToBacklog(t); err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding %s: %v\n", t.ID, err)
+			os.Exit(1)
+		}
+		fmt.Printf("  added [%s] %s\n", t.ID, t.Title)
+		added++
+	}
+
+	verb := "Added"
+	if *dryRun {
+		verb = "Would add"
+	}
+	fmt.Printf("%s %d tasks from %s/%s (%d already present)\n", verb, added, *owner, *repo, skipped)
+}
+
+func cmdTaskImportCSV(args []string) {
+	fs := flag.NewFlagSet("task import-csv", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the tasks that would be added without writing")
+	path := parseIDFlags(fs, args)
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task import-csv <file.csv> [--dry-run]")
+		os.Exit(1)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	rows, invalid, err := tasks.ReadTasksCSV(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	for _, msg := range invalid {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s, skipped\n", path, msg)
+	}
+
+	mgr := newTaskManager()
+	existing, err := mgr.ExportTasks([]string{"all"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading tasks: %v\n", err)
+		os.Exit(1)
+	}
+	type titleRepo struct{ title, repo string }
+	seen := make(map[titleRepo]bool)
+	for _, t := range existing {
+		seen[titleRepo{t.Title, t.Repo}] = true
+	}
+
+	imported, skipped := 0, len(invalid)
+	for _, t := range rows {
+		key := titleRepo{t.Title, t.Repo}
+		if seen[key] {
+			fmt.Printf("  [SKIP] %s (%s) already exists\n", t.Title, t.Repo)
+			skipped++
+			continue
+		}
+		seen[key] = true
+		if *dryRun {
+			fmt.Printf("  would add %s\n", t.Title)
+			imported++
+			continue
+		}
+		id, err := mgr.AddToBacklog(t)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding %q: %v\n", t.Title, err)
+			os.Exit(1)
+		}
+		fmt.Printf("  added [%s] %s\n", id, t.Title)
+		imported++
+	}
+
+	verb := "imported"
+	if *dryRun {
+		verb = "would be imported"
+	}
+	fmt.Printf("%d tasks %s, %d skipped\n", imported, verb, skipped)
+}
+
+func cmdTaskGantt(args []string) {
+	fs := flag.NewFlagSet("task gantt", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator task gantt - Chart task start and completion dates
+
+DESCRIPTION
+  Prints one row per task and one column per day, from the earliest
+  started_at or completed date to today. Completed tasks are drawn with █
+  from start to completion, active and paused tasks with ▒ from start to
+  today, and backlog tasks are left blank. Ranges wider than --width are
+  split into pages. --json prints the underlying dates instead.
+
+USAGE
+  orchestrator task gantt
+  orchestrator task gantt --width 120
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	width := fs.Int("width", 0, "Chart width in columns (default: $COLUMNS, or 80)")
+	asJSON := fs.Bool("json", false, "Print the timeline as JSON")
+	fs.Parse(args)
+
+	entries, err := newTaskManager().Timeline()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		data, _ := json.MarshalIndent(entries, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if *width <= 0 {
+		*width = 80
+		if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+			*width = n
+		}
+	}
+	tasks.WriteGantt(os.Stdout, entries, time.Now(), *width)
+}
+
+func cmdTaskStats(args []string) {
+	fs := flag.NewFlagSet("task stats", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print stats as JSON")
+	fs.Parse(args)
+
+	stats, err := newTaskManager().TaskStats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		data, _ := json.MarshalIndent(stats, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("%-24s %d\n", "Backlog", stats.Backlog)
+	fmt.Printf("%-24s %d\n", "Active", stats.Active)
+	fmt.Printf("%-24s %d\n", "Paused", stats.Paused)
+	fmt.Printf("%-24s %d\n", "Completed", stats.Completed)
+	if stats.CycleSamples > 0 {
+		fmt.Printf("%-24s %.1f days (%d tasks)\n", "Avg cycle time", stats.AvgCycleDays, stats.CycleSamples)
+	} else {
+		fmt.Printf("%-24s n/a\n", "Avg cycle time")
+	}
+	if stats.AgeSamples > 0 {
+		fmt.Printf("%-24s %.1f days (%d tasks)\n", "Median backlog age", stats.MedianBacklogAgeDays, stats.AgeSamples)
+	} else {
+		fmt.Printf("%-24s n/a\n", "Median backlog age")
+	}
+
+	fmt.Println("\nOPEN TASKS BY PRIORITY")
+	priorities := make([]string, 0, len(stats.ByPriority))
+	for p := range stats.ByPriority {
+		priorities = append(priorities, p)
+	}
+	sort.Strings(priorities)
+	for _, p := range priorities {
+		fmt.Printf("  %-22s %d\n", p, stats.ByPriority[p])
+	}
+}
+
+func cmdTaskMove(args []string) {
+	fs := flag.NewFlagSet("task move", flag.ExitOnError)
+	repo := fs.String("repo", "", "Repository to assign the task to (required)")
+	id := parseIDFlags(fs, args)
+	if id == "" || *repo == "" {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task move <id> --repo <name>")
+		os.Exit(1)
+	}
+
+	if _, ok := loadRepoConfig().GetRepo(*repo); !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", *repo)
+		os.Exit(1)
+	}
+	if err := newTaskManager().UpdateTaskField(id, "repo", *repo); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s moved to %s.\n", id, *repo)
+}
+
+func cmdTaskSplit(args []string) {
+	fs := flag.NewFlagSet("task split", flag.ExitOnError)
+	into := fs.Int("into", 0, "Number of subtasks to create (at least 2)")
+	suffix := fs.String("suffix", tasks.DefaultSplitSuffix, "Title suffix for each subtask; {n} is the part number")
+	id := parseIDFlags(fs, args)
+	if id == "" || *into == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task split <id> --into N [--suffix \"Part {n}\"]")
+		os.Exit(1)
+	}
+
+	ids, err := newTaskManager().SplitTask(id, *into, *suffix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s split into: %s\n", id, strings.Join(ids, ", "))
+}
+
+func cmdTaskMerge(args []string) {
+	fs := flag.NewFlagSet("task merge", flag.ExitOnError)
+	into := fs.String("into", "", "Task to keep, or \"new\" for a new task (default: the first ID)")
+	fs.Parse(args)
+	var ids []string
+	for fs.NArg() > 0 && len(ids) < 2 {
+		ids = append(ids, fs.Arg(0))
+		fs.Parse(fs.Args()[1:])
+	}
+	if len(ids) != 2 || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task merge <id1> <id2> [--into id1|id2|new]")
+		os.Exit(1)
+	}
+	if *into == "" {
+		*into = ids[0]
+	}
+
+	merged, err := newTaskManager().MergeTasks(ids[0], ids[1], *into)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Tasks %s and %s merged into %s.\n", ids[0], ids[1], merged)
+}
+
+func cmdTaskReorder(args []string) {
+	fs := flag.NewFlagSet("task reorder", flag.ExitOnError)
+	up := fs.Int("up", 0, "Move the task N places toward the top")
+	down := fs.Int("down", 0, "Move the task N places toward the bottom")
+	top := fs.Bool("top", false, "Move the task to the top")
+	bottom := fs.Bool("bottom", false, "Move the task to the bottom")
+	interactive := fs.Bool("interactive", false, "Reorder the backlog in a full-screen picker")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, `Usage: orchestrator task reorder <id> --up N | --down N | --top | --bottom
+       orchestrator task reorder --interactive
+
+A backlog task's priority is its position in the backlog file. Tasks trade
+places while headings stay put, so a task moved past "## Low Priority" ends
+up under it. With a sharded backlog, positions are within the task's shard.
+
+--interactive lists the backlog in the terminal. Select a task with the
+arrow keys or j/k, press space or Enter to grab it, move it with the arrow
+keys, and press space or Enter to drop it. Typing a number and pressing
+Enter moves the selected task to that rank. q or Esc quits.`)
+		fs.PrintDefaults()
+	}
+	id := parseIDFlags(fs, args)
+
+	mgr := newTaskManager()
+	if *interactive {
+		if id != "" {
+			fs.Usage()
+			os.Exit(1)
+		}
+		reorderInteractive(mgr)
+		return
+	}
+
+	moves := 0
+	for _, set := range []bool{*up != 0, *down != 0, *top, *bottom} {
+		if set {
+			moves++
+		}
+	}
+	if id == "" || moves != 1 || *up < 0 || *down < 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var (
+		rank int
+		err  error
+	)
+	switch {
+	case *up != 0:
+		rank, err = mgr.MoveTask(id, -*up)
+	case *down != 0:
+		rank, err = mgr.MoveTask(id, *down)
+	case *top:
+		rank, err = mgr.MoveTaskTo(id, 1)
+	case *bottom:
+		rank, err = mgr.MoveTask(id, math.MaxInt)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s is now #%d in the backlog.\n", id, rank)
+}
+
+func cmdTaskLink(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task link <id> <url>")
+		os.Exit(1)
+	}
+	if err := newTaskManager().AddLink(args[0], args[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s linked to %s.\n", args[0], args[1])
+}
+
+// cmdTaskOpen opens a task's first link with $BROWSER, falling back to open
+// on macOS and xdg-open elsewhere.
+func cmdTaskOpen(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task open <id>")
+		os.Exit(1)
+	}
+	t, err := newTaskManager().GetTask(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(t.Links) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: task %s has no links (add one with: orchestrator task link %s <url>)\n", t.ID, t.ID)
+		os.Exit(1)
+	}
+
+	opener := []string{"xdg-open"}
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		opener = strings.Fields(browser)
+	} else if runtime.GOOS == "darwin" {
+		opener = []string{"open"}
+	}
+	cmd := exec.Command(opener[0], append(opener[1:], t.Links[0])...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s %s: %v\n", opener[0], t.Links[0], err)
+		os.Exit(1)
+	}
+}
+
+func cmdTaskAssign(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task assign <id> <assignee>")
+		os.Exit(1)
+	}
+	if err := newTaskManager().AssignTask(args[0], args[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s assigned to %s.\n", args[0], args[1])
+}
+
+func cmdTaskUnassign(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task unassign <id>")
+		os.Exit(1)
+	}
+	if err := newTaskManager().UnassignTask(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Task %s unassigned.\n", args[0])
+}
+
+func cmdTaskShardBacklog(args []string) {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task shard-backlog")
+		os.Exit(1)
+	}
+	if err := newTaskManager().MigrateToSharded(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Backlog split into tasks/backlog/.")
+}
+
+// templateVars collects repeated --var key=value flags.
+type templateVars map[string]string
+
+func (v templateVars) String() string { return "" }
+
+func (v templateVars) Set(s string) error {
+	key, val, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	v[key] = val
+	return nil
+}
+
+func cmdTaskCreate(args []string) {
+	fs := flag.NewFlagSet("task create", flag.ExitOnError)
+	name := fs.String("template", "", "Template name from tasks/templates/ (required)")
+	id := fs.String("id", "", "Task ID (default: next T-NNN)")
+	allowCustom := fs.Bool("allow-custom-prefix", false, "Accept an --id without a GH-, LI-, or T- prefix")
+	vars := templateVars{}
+	fs.Var(vars, "var", "Template variable as key=value (repeatable)")
+	fs.Parse(args)
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task create --template NAME [--var key=value ...] [--id ID] [--allow-custom-prefix]")
+		os.Exit(1)
+	}
+
+	mgr := newTaskManager()
+	mgr.AllowCustomPrefix = *allowCustom
+	tmpl, err := mgr.LoadTemplate(*name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	t, err := tmpl.Expand(vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	t.ID = *id
+	newID, err := mgr.AddToBacklog(t)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Added [%s] %s\n", newID, t.Title)
+}
+
+func cmdTaskTemplates(args []string) {
+	if len(args) != 1 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, "Usage: orchestrator task templates list")
+		os.Exit(1)
+	}
+
+	list, err := newTaskManager().ListTemplates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(list) == 0 {
+		fmt.Println("No templates in tasks/templates/.")
+		return
+	}
+	for _, t := range list {
+		fmt.Printf("%-20s %s\n", t.Name, t.Title)
+	}
+}
+
+func cmdTaskExport(args []string) {
+	fs := flag.NewFlagSet("task export", flag.ExitOnError)
+	format := fs.String("format", "json", "Output format: csv, json, or markdown")
+	state := fs.String("state", "all", "Task state to export: backlog, active, paused, completed, or all")
+	fs.Parse(args)
+
+	list, err := newTaskManager().ExportTasks([]string{*state})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "json":
+		if list == nil {
+			list = []tasks.Task{}
+		}
+		data, _ := json.MarshalIndent(list, "", "  ")
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"ID", "Title", "Repo", "Type", "Priority", "Assigned", "DueDate", "StartedAt", "Completed"})
+		for _, t := range list {
+			startedAt := ""
+			if !t.StartedAt.IsZero() {
+				startedAt = t.StartedAt.UTC().Format(time.RFC3339)
+			}
+			w.Write([]string{t.ID, t.Title, t.Repo, t.Type, t.Priority, t.Assigned, t.DueDate, startedAt, t.Completed})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing CSV: %v\n", err)
+			os.Exit(1)
+		}
+	case "markdown":
+		fmt.Print(tasks.FormatMarkdown(list))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (use csv, json, or markdown)\n", *format)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulSnow/orchestrator/internal/runner"
+)
+
+func cmdTest(args []string) {
+	fs := flag.NewFlagSet("test", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`orchestrator test - Run tests for a managed repository
+
+DESCRIPTION
+  Runs the repository's tests the same way as test-all, logging to
+  /tmp/orchestrator-test-<repo>.log, and lists any failing tests.
+
+  With --race, Go repositories run "go test -race ./... -timeout 15m"
+  instead, logging to /tmp/orchestrator-race-<repo>.log, and each data race
+  the detector reports is listed with its location and goroutines.
+
+USAGE
+  orchestrator test <repo>
+  orchestrator test <repo> --race
+
+OPTIONS`)
+		fs.PrintDefaults()
+	}
+	race := fs.Bool("race", false, "Run Go tests with the race detector")
+	name := parseIDFlags(fs, args)
+	if name == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg := loadRepoConfig()
+	repo, ok := cfg.GetRepo(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", name)
+		os.Exit(1)
+	}
+
+	var result runner.Result
+	if *race {
+		result = runner.TestRepoRace(repo)
+	} else {
+		result = runner.TestRepo(repo)
+	}
+
+	status := "PASS"
+	if !result.Success {
+		status = "FAIL"
+	}
+	fmt.Printf("[%s] %s (%.1fs) -> %s\n", status, repo.Name, result.Duration, result.LogFile)
+	for _, r := range result.RaceConditions {
+		fmt.Printf("  DATA RACE at %s (%s vs %s)\n", r.Location, r.Goroutine1, r.Goroutine2)
+	}
+	for _, f := range result.FailedTests {
+		fmt.Printf("  FAIL %s %s\n", f.Package, f.TestName)
+	}
+	for _, pkg := range result.ExcludedPackages {
+		fmt.Printf("  SKIP %s\n", pkg)
+	}
+	if !result.Success {
+		os.Exit(1)
+	}
+}
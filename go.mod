@@ -1,3 +1,8 @@
 module github.com/PaulSnow/orchestrator
 
 go 1.25.0
+
+require (
+	golang.org/x/sys v0.35.0
+	golang.org/x/term v0.34.0
+)
@@ -3,8 +3,8 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
-	"path/filepath"
 )
 
 // RepoConfig represents a single managed repository.
@@ -18,6 +18,48 @@ type RepoConfig struct {
 	HasClaudeMD   bool     `json:"has_claude_md"`
 	Tags          []string `json:"tags"`
 	Description   string   `json:"description"`
+	Archived      bool     `json:"archived,omitempty"`
+	// BuildCmd and TestCmd override the build and test commands chosen from
+	// Language or a Makefile. The first element is the program to run.
+	BuildCmd []string `json:"build_cmd,omitempty"`
+	TestCmd  []string `json:"test_cmd,omitempty"`
+	CI       CIConfig `json:"ci,omitzero"`
+	// ExpectedHooks names the git hooks, such as "pre-commit", every clone
+	// should have installed. ScanRepo reports the ones that are not.
+	ExpectedHooks []string `json:"expected_hooks,omitempty"`
+}
+
+// CIConfig describes where a repository's continuous integration runs.
+type CIConfig struct {
+	// System is "github-actions", "gitlab-ci", "jenkins", or "none".
+	System      string `json:"system,omitempty"`
+	BadgeURL    string `json:"badge_url,omitempty"`
+	PipelineURL string `json:"pipeline_url,omitempty"`
+}
+
+// HasTag reports whether the repository carries tag.
+func (r RepoConfig) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactedRemote returns Remote with any credentials in an HTTPS URL replaced
+// by "REDACTED", for use in log and console output.
+func (r RepoConfig) RedactedRemote() string {
+	u, err := url.Parse(r.Remote)
+	if err != nil || u.User == nil || u.Scheme == "" {
+		return r.Remote
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), "REDACTED")
+	} else {
+		u.User = url.User("REDACTED")
+	}
+	return u.String()
 }
 
 // ReposFile is the top-level structure of repos.json.
@@ -30,16 +72,25 @@ type Config struct {
 	Repos    ReposFile
 	RepoMap  map[string]RepoConfig // keyed by name
 	RootPath string                // orchestrator repo root
+
+	// Warnings holds the problems Validate found at load time. They do not
+	// prevent the configuration from being used.
+	Warnings []ConfigError
 }
 
-// Load reads configuration from the orchestrator root directory.
+// LocalReposFile is the optional file beside repos.json whose repositories
+// Load merges over it with MergeRepos.
+const LocalReposFile = "repos.local.json"
+
+// Load reads configuration from the orchestrator root directory: repos.json
+// (see ReposPath), overlaid with repos.local.json when it exists.
 func Load(rootPath string) (*Config, error) {
 	c := &Config{
 		RootPath: rootPath,
 		RepoMap:  make(map[string]RepoConfig),
 	}
 
-	reposPath := filepath.Join(rootPath, "config", "repos.json")
+	reposPath := ReposPath(rootPath)
 	data, err := os.ReadFile(reposPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading repos.json: %w", err)
@@ -49,9 +100,32 @@ func Load(rootPath string) (*Config, error) {
 		return nil, fmt.Errorf("parsing repos.json: %w", err)
 	}
 
+	// repos.local.json holds per-developer overrides, such as Local paths,
+	// and is not checked in.
+	data, err = os.ReadFile(LocalReposPath(rootPath))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", LocalReposFile, err)
+	}
+	if err == nil {
+		var local ReposFile
+		if err := json.Unmarshal(data, &local); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", LocalReposFile, err)
+		}
+		c.Repos.Repositories = MergeRepos(c.Repos.Repositories, local.Repositories)
+	}
+
+	// Expand environment variables so paths and remotes can be written as
+	// ${HOME}/src/repo or https://${GITHUB_TOKEN}@github.com/org/repo.git.
+	for i := range c.Repos.Repositories {
+		r := &c.Repos.Repositories[i]
+		r.Local = os.Expand(r.Local, os.Getenv)
+		r.Remote = os.Expand(r.Remote, os.Getenv)
+	}
+
 	for _, r := range c.Repos.Repositories {
 		c.RepoMap[r.Name] = r
 	}
+	c.Warnings = c.Validate()
 
 	return c, nil
 }
@@ -62,7 +136,40 @@ func (c *Config) GetRepo(name string) (RepoConfig, bool) {
 	return r, ok
 }
 
-// AllRepos returns all configured repositories.
+// AllRepos returns all configured repositories that are not archived.
 func (c *Config) AllRepos() []RepoConfig {
+	var active []RepoConfig
+	for _, r := range c.Repos.Repositories {
+		if !r.Archived {
+			active = append(active, r)
+		}
+	}
+	return active
+}
+
+// AllReposIncludingArchived returns every configured repository.
+func (c *Config) AllReposIncludingArchived() []RepoConfig {
 	return c.Repos.Repositories
 }
+
+// ArchivedRepos returns only the archived repositories.
+func (c *Config) ArchivedRepos() []RepoConfig {
+	var archived []RepoConfig
+	for _, r := range c.Repos.Repositories {
+		if r.Archived {
+			archived = append(archived, r)
+		}
+	}
+	return archived
+}
+
+// ReposByTag returns the non-archived repositories carrying tag, in config order.
+func (c *Config) ReposByTag(tag string) []RepoConfig {
+	var matched []RepoConfig
+	for _, r := range c.AllRepos() {
+		if r.HasTag(tag) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
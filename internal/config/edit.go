@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigEnv names the environment variable that, when set, is the path of
+// repos.json in place of config/repos.json under the root.
+const ConfigEnv = "ORCHESTRATOR_CONFIG"
+
+// ReposPath returns the path of repos.json: $ORCHESTRATOR_CONFIG if set,
+// otherwise config/repos.json under rootPath.
+func ReposPath(rootPath string) string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path
+	}
+	return filepath.Join(rootPath, "config", "repos.json")
+}
+
+// LocalReposPath returns the path of repos.local.json, beside repos.json.
+func LocalReposPath(rootPath string) string {
+	return filepath.Join(filepath.Dir(ReposPath(rootPath)), LocalReposFile)
+}
+
+// ReadReposFile reads repos.json without expanding environment variables, so
+// it can be modified and written back without baking in expanded secrets.
+func ReadReposFile(rootPath string) (ReposFile, error) {
+	return readReposFileAt(ReposPath(rootPath), "repos.json")
+}
+
+func readReposFileAt(path, label string) (ReposFile, error) {
+	var rf ReposFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rf, fmt.Errorf("reading %s: %w", label, err)
+	}
+	if err := json.Unmarshal(data, &rf); err != nil {
+		return rf, fmt.Errorf("parsing %s: %w", label, err)
+	}
+	return rf, nil
+}
+
+// WriteReposFile replaces repos.json atomically.
+func WriteReposFile(rootPath string, rf ReposFile) error {
+	return writeReposFileAt(ReposPath(rootPath), rf)
+}
+
+func writeReposFileAt(path string, rf ReposFile) error {
+	data, err := json.MarshalIndent(rf, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// AddRepo appends repo to repos.json. The name must be unused, and the new
+// entry must pass Validate (with environment variables expanded, as Load
+// would). Problems with other entries do not block the addition.
+func AddRepo(rootPath string, repo RepoConfig) error {
+	rf, err := ReadReposFile(rootPath)
+	if err != nil {
+		return err
+	}
+	for _, r := range rf.Repositories {
+		if r.Name == repo.Name {
+			return fmt.Errorf("repository %s already exists", repo.Name)
+		}
+	}
+	if repo.Tags == nil {
+		repo.Tags = []string{}
+	}
+
+	expanded := repo
+	expanded.Local = os.Expand(repo.Local, os.Getenv)
+	expanded.Remote = os.Expand(repo.Remote, os.Getenv)
+	check := &Config{Repos: ReposFile{Repositories: []RepoConfig{expanded}}}
+	if errs := check.Validate(); len(errs) > 0 {
+		return errs[0]
+	}
+
+	rf.Repositories = append(rf.Repositories, repo)
+	return WriteReposFile(rootPath, rf)
+}
+
+// RemoveRepo deletes the named repository from repos.json and from
+// repos.local.json, so Load does not bring it back, and returns the removed
+// entry merged and with environment variables expanded as Load would. The
+// repository may be defined in either file.
+func RemoveRepo(rootPath, name string) (RepoConfig, error) {
+	rf, err := ReadReposFile(rootPath)
+	if err != nil {
+		return RepoConfig{}, err
+	}
+	localPath := LocalReposPath(rootPath)
+	local, err := readReposFileAt(localPath, LocalReposFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return RepoConfig{}, err
+	}
+
+	base, inBase := cutRepo(&rf, name)
+	override, inLocal := cutRepo(&local, name)
+	if !inBase && !inLocal {
+		return RepoConfig{}, fmt.Errorf("repository %s not found", name)
+	}
+	if inLocal {
+		if err := writeReposFileAt(localPath, local); err != nil {
+			return RepoConfig{}, err
+		}
+	}
+	if inBase {
+		if err := WriteReposFile(rootPath, rf); err != nil {
+			return RepoConfig{}, err
+		}
+	}
+
+	r := override
+	if inBase {
+		r = MergeRepos([]RepoConfig{base}, []RepoConfig{override})[0]
+	}
+	r.Local = os.Expand(r.Local, os.Getenv)
+	r.Remote = os.Expand(r.Remote, os.Getenv)
+	return r, nil
+}
+
+// cutRepo removes the named repository from rf, reporting whether it was
+// there.
+func cutRepo(rf *ReposFile, name string) (RepoConfig, bool) {
+	for i, r := range rf.Repositories {
+		if r.Name == name {
+			rf.Repositories = append(rf.Repositories[:i], rf.Repositories[i+1:]...)
+			return r, true
+		}
+	}
+	return RepoConfig{}, false
+}
@@ -0,0 +1,56 @@
+package config
+
+// MergeRepos applies overlay to base and returns the result. An overlay repo
+// whose Name matches a base repo replaces each field it sets; fields left at
+// their zero value keep the base value, so booleans can only be turned on. An
+// overlay repo with a new Name is appended. base is not modified.
+func MergeRepos(base, overlay []RepoConfig) []RepoConfig {
+	merged := append([]RepoConfig{}, base...)
+	index := make(map[string]int, len(merged))
+	for i, r := range merged {
+		index[r.Name] = i
+	}
+	for _, o := range overlay {
+		if i, ok := index[o.Name]; ok {
+			merged[i] = mergeRepo(merged[i], o)
+			continue
+		}
+		index[o.Name] = len(merged)
+		merged = append(merged, o)
+	}
+	return merged
+}
+
+// mergeRepo returns base with every non-zero field of o applied.
+func mergeRepo(base, o RepoConfig) RepoConfig {
+	for _, f := range []struct{ dst, src *string }{
+		{&base.Platform, &o.Platform},
+		{&base.Remote, &o.Remote},
+		{&base.Local, &o.Local},
+		{&base.DefaultBranch, &o.DefaultBranch},
+		{&base.Language, &o.Language},
+		{&base.Description, &o.Description},
+		{&base.CI.System, &o.CI.System},
+		{&base.CI.BadgeURL, &o.CI.BadgeURL},
+		{&base.CI.PipelineURL, &o.CI.PipelineURL},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	base.HasClaudeMD = base.HasClaudeMD || o.HasClaudeMD
+	base.Archived = base.Archived || o.Archived
+	if o.Tags != nil {
+		base.Tags = o.Tags
+	}
+	if o.BuildCmd != nil {
+		base.BuildCmd = o.BuildCmd
+	}
+	if o.TestCmd != nil {
+		base.TestCmd = o.TestCmd
+	}
+	if o.ExpectedHooks != nil {
+		base.ExpectedHooks = o.ExpectedHooks
+	}
+	return base
+}
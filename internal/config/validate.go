@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// knownLanguages are the Language values the scanner and runner understand.
+var knownLanguages = map[string]bool{
+	"go":         true,
+	"javascript": true,
+	"make":       true,
+	"rust":       true,
+	"python":     true,
+	"dotnet":     true,
+	"elixir":     true,
+	"java":       true,
+	"maven":      true,
+	"php":        true,
+	"swift":      true,
+	"unknown":    true,
+}
+
+// knownCISystems are the CIConfig.System values the scanner reports.
+var knownCISystems = map[string]bool{
+	"github-actions": true,
+	"gitlab-ci":      true,
+	"jenkins":        true,
+	"none":           true,
+}
+
+// remoteSchemes are the URL schemes accepted for RepoConfig.Remote.
+var remoteSchemes = map[string]bool{
+	"https": true,
+	"http":  true,
+	"ssh":   true,
+	"git":   true,
+	"file":  true,
+}
+
+// scpRemoteRe matches scp-style SSH remotes such as git@github.com:org/repo.git.
+var scpRemoteRe = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^/].*$`)
+
+// ConfigError describes one problem found by Validate.
+type ConfigError struct {
+	Repo    string `json:"repo"`
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+func (e ConfigError) Error() string {
+	return fmt.Sprintf("%s: %s %q: %s", e.Repo, e.Field, e.Value, e.Message)
+}
+
+// Validate checks the repository definitions for mistakes that JSON parsing
+// alone does not catch. It returns nil if the configuration is valid.
+func (c *Config) Validate() []ConfigError {
+	var errs []ConfigError
+	seen := make(map[string]bool)
+
+	for i, r := range c.Repos.Repositories {
+		repo := r.Name
+		if repo == "" {
+			repo = fmt.Sprintf("repositories[%d]", i)
+		}
+		add := func(field, value, msg string) {
+			errs = append(errs, ConfigError{Repo: repo, Field: field, Value: value, Message: msg})
+		}
+
+		switch {
+		case r.Name == "":
+			add("name", "", "name is required")
+		case seen[r.Name]:
+			add("name", r.Name, "duplicate repository name")
+		}
+		seen[r.Name] = true
+
+		if !filepath.IsAbs(r.Local) {
+			add("local", r.Local, "local path must be absolute")
+		}
+		if r.Remote != "" && !validRemote(r.Remote) {
+			add("remote", r.RedactedRemote(), "remote must be a URL or an SSH remote like git@host:org/repo.git")
+		}
+		if !knownLanguages[r.Language] {
+			add("language", r.Language, "unrecognized language")
+		}
+		if r.DefaultBranch == "" {
+			add("default_branch", "", "default branch is required")
+		}
+		if r.CI.System != "" && !knownCISystems[r.CI.System] {
+			add("ci.system", r.CI.System, "unrecognized CI system (use github-actions, gitlab-ci, jenkins, or none)")
+		}
+		for _, tag := range r.Tags {
+			if strings.ContainsAny(tag, " \t") {
+				add("tags", tag, "tags must not contain spaces")
+			}
+		}
+	}
+	return errs
+}
+
+func validRemote(remote string) bool {
+	if scpRemoteRe.MatchString(remote) {
+		return true
+	}
+	u, err := url.Parse(remote)
+	if err != nil || !remoteSchemes[u.Scheme] {
+		return false
+	}
+	return u.Host != "" || (u.Scheme == "file" && u.Path != "")
+}
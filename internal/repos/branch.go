@@ -0,0 +1,67 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// ErrBranchExists is returned by CreateBranch when the branch already exists.
+var ErrBranchExists = errors.New("branch already exists")
+
+// unsafeRefChars are rejected in branch names: whitespace, shell
+// metacharacters, and the characters git itself forbids in ref names.
+const unsafeRefChars = " \t\r\n$`\\\"';&|<>()*?[]{}!~^:#"
+
+// ValidateRefName reports an error if name is empty, starts with "-", or
+// contains whitespace or shell metacharacters.
+func ValidateRefName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("name is empty")
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("invalid name %q: must not start with -", name)
+	case strings.ContainsAny(name, unsafeRefChars):
+		return fmt.Errorf("invalid name %q: contains whitespace or a shell metacharacter", name)
+	}
+	return nil
+}
+
+// CreateBranch runs git checkout -b branch from in the repository and returns
+// the SHA of the commit the branch starts at. An empty from means the current
+// HEAD. It returns an error wrapping ErrBranchExists if branch already exists
+// and one wrapping ErrRepoMissing if the repository has not been cloned.
+func CreateBranch(repo config.RepoConfig, branch, from string) (string, error) {
+	if _, err := os.Stat(repo.Local); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s: %w", repo.Local, ErrRepoMissing)
+	}
+	if from == "" {
+		from = "HEAD"
+	}
+	if err := ValidateRefName(branch); err != nil {
+		return "", fmt.Errorf("branch: %w", err)
+	}
+	if err := ValidateRefName(from); err != nil {
+		return "", fmt.Errorf("from: %w", err)
+	}
+
+	if _, err := gitCmd(repo.Local, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		return "", fmt.Errorf("%s: %w", branch, ErrBranchExists)
+	}
+	out, err := gitCmd(repo.Local, "rev-parse", "--verify", "--quiet", from+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", from)
+	}
+	sha := strings.TrimSpace(out)
+
+	if _, err := gitCmd(repo.Local, "checkout", "-q", "-b", branch, sha); err != nil {
+		if msg := strings.TrimSpace(gitStderr(err)); msg != "" {
+			return "", fmt.Errorf("git checkout: %s", msg)
+		}
+		return "", fmt.Errorf("git checkout: %w", err)
+	}
+	return sha, nil
+}
@@ -0,0 +1,44 @@
+package repos
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+func TestCreateBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	repo := config.RepoConfig{Name: "test", Local: dir}
+	head := strings.TrimSpace(runGit(t, dir, "rev-parse", "HEAD"))
+
+	sha, err := CreateBranch(repo, "feature/x", "main")
+	if err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if sha != head {
+		t.Errorf("expected from sha %s, got %s", head, sha)
+	}
+	if got := strings.TrimSpace(runGit(t, dir, "branch", "--show-current")); got != "feature/x" {
+		t.Errorf("expected feature/x checked out, got %q", got)
+	}
+
+	if _, err := CreateBranch(repo, "feature/x", "main"); !errors.Is(err, ErrBranchExists) {
+		t.Errorf("expected ErrBranchExists, got %v", err)
+	}
+	if _, err := CreateBranch(repo, "other", "no-such-branch"); err == nil {
+		t.Error("expected error for unknown from")
+	}
+	for _, bad := range []string{"", "-f", "a b", "x$(id)", "x`id`", "a;b"} {
+		if _, err := CreateBranch(repo, bad, "main"); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+
+	_, err = CreateBranch(config.RepoConfig{Name: "gone", Local: filepath.Join(dir, "gone")}, "x", "")
+	if !errors.Is(err, ErrRepoMissing) {
+		t.Errorf("expected ErrRepoMissing, got %v", err)
+	}
+}
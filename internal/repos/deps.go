@@ -0,0 +1,149 @@
+package repos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// goModule is the subset of "go list -m -json" output used to find
+// dependencies between managed repositories.
+type goModule struct {
+	Path     string
+	Main     bool
+	Indirect bool
+}
+
+// ModulePathFromRemote returns the import path prefix a remote would be
+// fetched by, such as github.com/org/repo for git@github.com:org/repo.git or
+// https://github.com/org/repo.git. It returns "" for remotes without a host.
+func ModulePathFromRemote(remote string) string {
+	s := strings.TrimSpace(remote)
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	} else if host, path, ok := strings.Cut(s, ":"); ok && !strings.Contains(host, "/") {
+		// scp-style user@host:path
+		s = host + "/" + path
+	} else {
+		return ""
+	}
+	if i := strings.Index(s, "@"); i >= 0 && i < strings.Index(s+"/", "/") {
+		s = s[i+1:]
+	}
+	host, path, ok := strings.Cut(s, "/")
+	if !ok || host == "" || path == "" {
+		return ""
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i] // drop a port
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	return strings.ToLower(host) + "/" + path
+}
+
+// ResolveDependencies sets DependsOn for each Go repository in statuses to
+// the managed repositories it requires directly, according to
+// "go list -m -json all" run in its checkout. A module belongs to a managed
+// repository when its path is, or is under, the path derived from that
+// repository's Remote. Other modules are ignored, as are repositories whose
+// module graph cannot be listed.
+func ResolveDependencies(list []config.RepoConfig, statuses []RepoStatus) {
+	type owner struct{ prefix, name string }
+	var owners []owner
+	byName := make(map[string]config.RepoConfig, len(list))
+	for _, r := range list {
+		byName[r.Name] = r
+		if p := ModulePathFromRemote(r.Remote); p != "" {
+			owners = append(owners, owner{p, r.Name})
+		}
+	}
+	// Longest prefix first, so github.com/org/repo-extra is not claimed by
+	// github.com/org/repo.
+	sort.Slice(owners, func(i, j int) bool { return len(owners[i].prefix) > len(owners[j].prefix) })
+
+	for i := range statuses {
+		s := &statuses[i]
+		repo, ok := byName[s.Name]
+		if !ok || repo.Language != "go" || !s.Exists {
+			continue
+		}
+		modules, err := listModules(repo.Local)
+		if err != nil {
+			continue
+		}
+		seen := map[string]bool{}
+		var deps []string
+		for _, m := range modules {
+			if m.Main || m.Indirect {
+				continue
+			}
+			for _, o := range owners {
+				if o.name != s.Name && !seen[o.name] && (m.Path == o.prefix || strings.HasPrefix(m.Path, o.prefix+"/")) {
+					seen[o.name] = true
+					deps = append(deps, o.name)
+					break
+				}
+			}
+		}
+		sort.Strings(deps)
+		s.DependsOn = deps
+	}
+}
+
+// listModules runs "go list -m -json all" in dir and decodes the stream of
+// module objects it prints.
+func listModules(dir string) ([]goModule, error) {
+	cmd := exec.Command("go", "list", "-m", "-json", "all")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var modules []goModule
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var m goModule
+		if err := dec.Decode(&m); err == io.EOF {
+			return modules, nil
+		} else if err != nil {
+			return nil, err
+		}
+		modules = append(modules, m)
+	}
+}
+
+// WriteDotFile writes a Graphviz digraph to path with a node for each
+// repository in statuses and an edge from each repository to those in its
+// DependsOn, creating path's directory if needed. The file is replaced
+// atomically.
+func WriteDotFile(path string, statuses []RepoStatus) error {
+	var b strings.Builder
+	b.WriteString("digraph repos {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, s := range statuses {
+		fmt.Fprintf(&b, "  %q;\n", s.Name)
+	}
+	for _, s := range statuses {
+		for _, dep := range s.DependsOn {
+			fmt.Fprintf(&b, "  %q -> %q;\n", s.Name, dep)
+		}
+	}
+	b.WriteString("}\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,83 @@
+package repos
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+func TestModulePathFromRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"git@github.com:Org/repo.git", "github.com/Org/repo"},
+		{"https://github.com/org/repo.git", "github.com/org/repo"},
+		{"https://token@GitHub.com/org/repo", "github.com/org/repo"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/repo.git", "gitlab.example.com/group/sub/repo"},
+		{"/srv/git/repo.git", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ModulePathFromRemote(tt.remote); got != tt.want {
+			t.Errorf("ModulePathFromRemote(%q) = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestResolveDependenciesAndWriteDotFile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("app/go.mod", "module github.com/org/app\n\ngo 1.21\n\nrequire github.com/org/lib v0.0.0\n\nreplace github.com/org/lib => ../lib\n")
+	write("lib/go.mod", "module github.com/org/lib\n\ngo 1.21\n")
+	write("web/package.json", "{}\n")
+
+	list := []config.RepoConfig{
+		{Name: "app", Local: filepath.Join(root, "app"), Language: "go", Remote: "git@github.com:org/app.git"},
+		{Name: "lib", Local: filepath.Join(root, "lib"), Language: "go", Remote: "https://github.com/org/lib.git"},
+		{Name: "web", Local: filepath.Join(root, "web"), Language: "javascript", Remote: "git@github.com:org/web.git"},
+	}
+	statuses := []RepoStatus{
+		{Name: "app", Exists: true},
+		{Name: "lib", Exists: true},
+		{Name: "web", Exists: true},
+	}
+	ResolveDependencies(list, statuses)
+	if got := statuses[0].DependsOn; len(got) != 1 || got[0] != "lib" {
+		t.Errorf("expected app to depend on lib, got %v", got)
+	}
+	if len(statuses[1].DependsOn) != 0 || len(statuses[2].DependsOn) != 0 {
+		t.Errorf("expected no other dependencies, got %+v", statuses)
+	}
+
+	path := filepath.Join(root, "state", "deps.dot")
+	if err := WriteDotFile(path, statuses); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := string(data)
+	for _, want := range []string{"digraph repos {", `"web";`, `"app" -> "lib";`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected %q in DOT output:\n%s", want, dot)
+		}
+	}
+	if strings.Count(dot, "->") != 1 {
+		t.Errorf("expected exactly one edge:\n%s", dot)
+	}
+}
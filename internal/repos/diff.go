@@ -0,0 +1,115 @@
+package repos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/version"
+)
+
+// StatusChange describes a single field that differs between two scans of a repository.
+type StatusChange struct {
+	Name     string `json:"name"`
+	Field    string `json:"field"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// LoadStatusFile reads the scan results previously written by WriteStatusFile,
+// in either the version 1 bare array or the versioned object. A file from a
+// different StatusFileVersion, or written by a different major version of
+// the orchestrator, is still read, with a warning on stderr.
+func LoadStatusFile(rootPath string) ([]RepoStatus, error) {
+	data, err := os.ReadFile(filepath.Join(rootPath, "state", "repo-status.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var statuses []RepoStatus
+		if err := json.Unmarshal(data, &statuses); err != nil {
+			return nil, fmt.Errorf("parsing repo-status.json: %w", err)
+		}
+		return statuses, nil
+	}
+
+	var file statusFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing repo-status.json: %w", err)
+	}
+	if file.Version != StatusFileVersion {
+		fmt.Fprintf(os.Stderr, "Warning: repo-status.json has schema version %d, expected %d; rescan to update it\n", file.Version, StatusFileVersion)
+	}
+	version.WarnIfIncompatible("repo-status.json", file.OrchestratorVersion)
+	return file.Repos, nil
+}
+
+// DiffStatus compares two scans and returns the fields that changed, in the order
+// repositories appear in after. Repositories present in only one scan are reported
+// with Field "added" or "removed".
+func DiffStatus(before, after []RepoStatus) []StatusChange {
+	prev := make(map[string]RepoStatus, len(before))
+	for _, s := range before {
+		prev[s.Name] = s
+	}
+
+	var changes []StatusChange
+	seen := make(map[string]bool, len(after))
+
+	for _, cur := range after {
+		seen[cur.Name] = true
+		old, ok := prev[cur.Name]
+		if !ok {
+			changes = append(changes, StatusChange{Name: cur.Name, Field: "added", NewValue: cur.Path})
+			continue
+		}
+
+		for _, f := range diffFields(old, cur) {
+			changes = append(changes, StatusChange{Name: cur.Name, Field: f[0], OldValue: f[1], NewValue: f[2]})
+		}
+	}
+
+	for _, old := range before {
+		if !seen[old.Name] {
+			changes = append(changes, StatusChange{Name: old.Name, Field: "removed", OldValue: old.Path})
+		}
+	}
+
+	return changes
+}
+
+// diffFields returns {field, old, new} triples for each compared field that differs.
+func diffFields(old, cur RepoStatus) [][3]string {
+	pairs := [][3]string{
+		{"exists", strconv.FormatBool(old.Exists), strconv.FormatBool(cur.Exists)},
+		{"branch", old.Branch, cur.Branch},
+		{"clean", strconv.FormatBool(old.Clean), strconv.FormatBool(cur.Clean)},
+		{"modified_files", strconv.Itoa(old.ModifiedFiles), strconv.Itoa(cur.ModifiedFiles)},
+		{"untracked_files", strconv.Itoa(old.UntrackedFiles), strconv.Itoa(cur.UntrackedFiles)},
+		{"conflict_files", strconv.Itoa(old.ConflictFiles), strconv.Itoa(cur.ConflictFiles)},
+		{"stash_count", strconv.Itoa(old.StashCount), strconv.Itoa(cur.StashCount)},
+		{"ahead", strconv.Itoa(old.Ahead), strconv.Itoa(cur.Ahead)},
+		{"behind", strconv.Itoa(old.Behind), strconv.Itoa(cur.Behind)},
+		{"no_upstream", strconv.FormatBool(old.NoUpstream), strconv.FormatBool(cur.NoUpstream)},
+		{"last_commit", old.LastCommit, cur.LastCommit},
+		{"has_claude_md", strconv.FormatBool(old.HasClaudeMD), strconv.FormatBool(cur.HasClaudeMD)},
+		{"ci_system", old.CISystem, cur.CISystem},
+		{"broken_symlinks", strconv.Itoa(len(old.BrokenSymlinks)), strconv.Itoa(len(cur.BrokenSymlinks))},
+		{"large_staged_files", strconv.Itoa(len(old.LargeStagedFiles)), strconv.Itoa(len(cur.LargeStagedFiles))},
+		{"missing_hooks", strings.Join(old.MissingHooks, ","), strings.Join(cur.MissingHooks, ",")},
+		{"error", old.Error, cur.Error},
+	}
+
+	var diffs [][3]string
+	for _, p := range pairs {
+		if p[1] != p[2] {
+			diffs = append(diffs, p)
+		}
+	}
+	return diffs
+}
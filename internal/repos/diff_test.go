@@ -0,0 +1,126 @@
+package repos
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffStatus_FieldChanges(t *testing.T) {
+	before := []RepoStatus{
+		{Name: "alpha", Exists: true, Branch: "main", Clean: true},
+		{Name: "beta", Exists: true, Branch: "main", Clean: true, Ahead: 1},
+	}
+	after := []RepoStatus{
+		{Name: "alpha", Exists: true, Branch: "feature", Clean: false, ModifiedFiles: 2},
+		{Name: "beta", Exists: true, Branch: "main", Clean: true, Ahead: 1},
+	}
+
+	changes := DiffStatus(before, after)
+
+	want := map[string][2]string{
+		"branch":         {"main", "feature"},
+		"clean":          {"true", "false"},
+		"modified_files": {"0", "2"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changes), changes)
+	}
+	for _, c := range changes {
+		if c.Name != "alpha" {
+			t.Errorf("unexpected change for %s: %+v", c.Name, c)
+			continue
+		}
+		w, ok := want[c.Field]
+		if !ok {
+			t.Errorf("unexpected field %q", c.Field)
+			continue
+		}
+		if c.OldValue != w[0] || c.NewValue != w[1] {
+			t.Errorf("%s: expected %q -> %q, got %q -> %q", c.Field, w[0], w[1], c.OldValue, c.NewValue)
+		}
+	}
+}
+
+func TestDiffStatus_AddedAndRemoved(t *testing.T) {
+	before := []RepoStatus{{Name: "old", Path: "/repos/old"}}
+	after := []RepoStatus{{Name: "new", Path: "/repos/new"}}
+
+	changes := DiffStatus(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Name != "new" || changes[0].Field != "added" || changes[0].NewValue != "/repos/new" {
+		t.Errorf("unexpected added change: %+v", changes[0])
+	}
+	if changes[1].Name != "old" || changes[1].Field != "removed" || changes[1].OldValue != "/repos/old" {
+		t.Errorf("unexpected removed change: %+v", changes[1])
+	}
+}
+
+func TestDiffStatus_NoChanges(t *testing.T) {
+	s := []RepoStatus{{Name: "alpha", Exists: true, Branch: "main", Clean: true}}
+	if changes := DiffStatus(s, s); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestLoadStatusFile_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	statuses := []RepoStatus{{Name: "alpha", Exists: true, Branch: "main", Clean: true}}
+
+	if err := WriteStatusFile(root, statuses); err != nil {
+		t.Fatalf("WriteStatusFile: %v", err)
+	}
+
+	loaded, err := LoadStatusFile(root)
+	if err != nil {
+		t.Fatalf("LoadStatusFile: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Name != "alpha" || loaded[0].Branch != "main" {
+		t.Errorf("unexpected loaded statuses: %+v", loaded)
+	}
+}
+
+func TestLoadStatusFile_Missing(t *testing.T) {
+	_, err := LoadStatusFile(t.TempDir())
+	if !os.IsNotExist(err) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestLoadStatusFile_Invalid(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "state"), 0755)
+	os.WriteFile(filepath.Join(root, "state", "repo-status.json"), []byte("not json"), 0644)
+
+	if _, err := LoadStatusFile(root); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestLoadStatusFile_Versions(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "state"), 0755)
+	path := filepath.Join(root, "state", "repo-status.json")
+
+	if err := WriteStatusFile(root, []RepoStatus{{Name: "alpha"}}); err != nil {
+		t.Fatalf("WriteStatusFile: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"version": 2`) || !strings.Contains(string(data), `"scanned_at"`) || !strings.Contains(string(data), `"orchestrator_version"`) {
+		t.Errorf("expected a versioned object, got:\n%s", data)
+	}
+
+	for name, content := range map[string]string{
+		"legacy array":   `[{"name": "beta"}]`,
+		"future version": `{"version": 99, "repos": [{"name": "beta"}], "extra": true}`,
+	} {
+		os.WriteFile(path, []byte(content), 0644)
+		loaded, err := LoadStatusFile(root)
+		if err != nil || len(loaded) != 1 || loaded[0].Name != "beta" {
+			t.Errorf("%s: got %+v, %v", name, loaded, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package repos
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// languageMarkers maps a marker file (or glob) in a repo root to the language
+// it implies. Checked in order; the first match wins.
+var languageMarkers = []struct {
+	file     string
+	language string
+}{
+	{"go.mod", "go"},
+	{"package.json", "javascript"},
+	{"Cargo.toml", "rust"},
+	{"requirements.txt", "python"},
+	{"*.sln", "dotnet"},
+	{"*.csproj", "dotnet"},
+	{"mix.exs", "elixir"},
+	{"pom.xml", "java"},
+	{"composer.json", "php"},
+	{"Package.swift", "swift"},
+}
+
+// DetectLanguage guesses a repository's language from marker files in its root.
+func DetectLanguage(dir string) string {
+	for _, m := range languageMarkers {
+		if hasMarker(dir, m.file) {
+			return m.language
+		}
+	}
+	return "unknown"
+}
+
+// ciMarkers maps a marker file (or glob) in a repo root to the CI system it
+// implies. Checked in order; the first match wins.
+var ciMarkers = []struct {
+	file   string
+	system string
+}{
+	{filepath.Join(".github", "workflows", "*.yml"), "github-actions"},
+	{filepath.Join(".github", "workflows", "*.yaml"), "github-actions"},
+	{".gitlab-ci.yml", "gitlab-ci"},
+	{"Jenkinsfile", "jenkins"},
+}
+
+// DetectCISystem reports the CI system configured in a repository's working
+// tree, or "" if none is found.
+func DetectCISystem(dir string) string {
+	for _, m := range ciMarkers {
+		if hasMarker(dir, m.file) {
+			return m.system
+		}
+	}
+	return ""
+}
+
+// hasMarker reports whether dir contains a file matching marker, which may be
+// a filepath.Match pattern in its last element.
+func hasMarker(dir, marker string) bool {
+	if !strings.ContainsAny(marker, "*?[") {
+		_, err := os.Stat(filepath.Join(dir, marker))
+		return err == nil
+	}
+	sub, pattern := filepath.Split(marker)
+	dir = filepath.Join(dir, sub)
+	marker = pattern
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if ok, _ := filepath.Match(marker, e.Name()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscoverRepos walks scanDir looking for git repositories and returns a
+// starter RepoConfig for each one found. Nested repositories are not descended
+// into once their parent has been recorded.
+func DiscoverRepos(scanDir string) ([]config.RepoConfig, error) {
+	var found []config.RepoConfig
+
+	err := filepath.WalkDir(scanDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped rather than aborting the walk.
+			if d != nil && d.IsDir() && path != scanDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != scanDir && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor") {
+			return filepath.SkipDir
+		}
+
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			return nil
+		}
+
+		found = append(found, DescribeRepo(path))
+		return filepath.SkipDir
+	})
+
+	return found, err
+}
+
+// FindOrphans discovers the git repositories under parentDir, as
+// DiscoverRepos does, and returns those not already in managed. A repository
+// is managed when its origin remote matches a managed Remote, compared by
+// host and path so SSH and HTTPS URLs agree, or when its directory is a
+// managed Local.
+func FindOrphans(parentDir string, managed []config.RepoConfig) ([]config.RepoConfig, error) {
+	found, err := DiscoverRepos(parentDir)
+	if err != nil {
+		return nil, err
+	}
+
+	remotes := make(map[string]bool)
+	locals := make(map[string]bool)
+	for _, r := range managed {
+		if key := remoteKey(r.Remote); key != "" {
+			remotes[key] = true
+		}
+		if abs, err := filepath.Abs(r.Local); err == nil {
+			locals[abs] = true
+		}
+	}
+
+	var orphans []config.RepoConfig
+	for _, r := range found {
+		if key := remoteKey(r.Remote); (key != "" && remotes[key]) || locals[r.Local] {
+			continue
+		}
+		orphans = append(orphans, r)
+	}
+	return orphans, nil
+}
+
+// remoteKey normalizes a remote for comparison: its host and path when it
+// has them, otherwise the remote itself.
+func remoteKey(remote string) string {
+	if p := ModulePathFromRemote(remote); p != "" {
+		return p
+	}
+	return strings.TrimSpace(remote)
+}
+
+// DescribeRepo builds a RepoConfig for a local checkout, detecting its
+// language, origin remote, default branch, and CLAUDE.md.
+func DescribeRepo(dir string) config.RepoConfig {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+
+	repo := config.RepoConfig{
+		Name:     filepath.Base(abs),
+		Local:    abs,
+		Language: DetectLanguage(abs),
+		Tags:     []string{},
+	}
+
+	if out, err := gitCmd(abs, "remote", "get-url", "origin"); err == nil {
+		repo.Remote = strings.TrimSpace(out)
+		repo.Platform = PlatformFromRemote(repo.Remote)
+	}
+
+	// Prefer the remote's default branch; fall back to the current branch.
+	if out, err := gitCmd(abs, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		repo.DefaultBranch = strings.TrimPrefix(strings.TrimSpace(out), "origin/")
+	} else if out, err := gitCmd(abs, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		repo.DefaultBranch = strings.TrimSpace(out)
+	}
+
+	repo.HasClaudeMD = FindClaudeMD(abs) != ""
+	repo.CI.System = DetectCISystem(abs)
+	if repo.CI.System == "" {
+		repo.CI.System = "none"
+	}
+
+	return repo
+}
+
+// PlatformFromRemote returns "github" or "gitlab" for a remote hosted on
+// either, and "" otherwise.
+func PlatformFromRemote(remote string) string {
+	switch {
+	case strings.Contains(remote, "github.com"):
+		return "github"
+	case strings.Contains(remote, "gitlab"):
+		return "gitlab"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,131 @@
+package repos
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		marker string
+		want   string
+	}{
+		{"go.mod", "go"},
+		{"package.json", "javascript"},
+		{"Cargo.toml", "rust"},
+		{"requirements.txt", "python"},
+		{"App.sln", "dotnet"},
+		{"App.csproj", "dotnet"},
+		{"mix.exs", "elixir"},
+		{"pom.xml", "java"},
+		{"composer.json", "php"},
+		{"Package.swift", "swift"},
+		{"", "unknown"},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if tt.marker != "" {
+			os.WriteFile(filepath.Join(dir, tt.marker), []byte{}, 0644)
+		}
+		if got := DetectLanguage(dir); got != tt.want {
+			t.Errorf("DetectLanguage with %q: expected %q, got %q", tt.marker, tt.want, got)
+		}
+	}
+}
+
+func TestDiscoverRepos(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	repoDir := filepath.Join(root, "group", "myrepo")
+	os.MkdirAll(repoDir, 0755)
+	os.WriteFile(filepath.Join(repoDir, "go.mod"), []byte("module example.com/myrepo\n"), 0644)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "git@github.com:acme/myrepo.git"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	// A plain directory must not be reported.
+	os.MkdirAll(filepath.Join(root, "notarepo"), 0755)
+
+	found, err := DiscoverRepos(root)
+	if err != nil {
+		t.Fatalf("DiscoverRepos: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 repo, got %d: %+v", len(found), found)
+	}
+
+	r := found[0]
+	if r.Name != "myrepo" {
+		t.Errorf("expected name myrepo, got %q", r.Name)
+	}
+	if r.Language != "go" {
+		t.Errorf("expected language go, got %q", r.Language)
+	}
+	if r.Remote != "git@github.com:acme/myrepo.git" {
+		t.Errorf("unexpected remote %q", r.Remote)
+	}
+	if r.Platform != "github" {
+		t.Errorf("expected platform github, got %q", r.Platform)
+	}
+}
+
+func TestFindOrphans(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	for name, remote := range map[string]string{
+		"managed":   "git@github.com:acme/managed.git",
+		"orphan":    "git@github.com:acme/orphan.git",
+		"by-path":   "",
+		"no-origin": "",
+	} {
+		dir := filepath.Join(root, name)
+		os.MkdirAll(dir, 0755)
+		args := [][]string{{"init", "-q"}}
+		if remote != "" {
+			args = append(args, []string{"remote", "add", "origin", remote})
+		}
+		for _, a := range args {
+			cmd := exec.Command("git", a...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", a, err, out)
+			}
+		}
+	}
+
+	managed := []config.RepoConfig{
+		{Name: "managed", Local: "/elsewhere/managed", Remote: "https://github.com/acme/managed"},
+		{Name: "by-path", Local: filepath.Join(root, "by-path")},
+	}
+	orphans, err := FindOrphans(root, managed)
+	if err != nil {
+		t.Fatalf("FindOrphans: %v", err)
+	}
+	var names []string
+	for _, o := range orphans {
+		names = append(names, o.Name)
+	}
+	sort.Strings(names)
+	if want := []string{"no-origin", "orphan"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("orphans = %v, want %v", names, want)
+	}
+}
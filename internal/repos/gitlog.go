@@ -0,0 +1,98 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// ErrRepoMissing is returned when a repository's local directory does not exist.
+var ErrRepoMissing = errors.New("repository directory does not exist")
+
+// CommitSummary is one commit from GitLog.
+type CommitSummary struct {
+	SHA     string    `json:"sha"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+	Subject string    `json:"subject"`
+}
+
+// gitLogFormat emits one field per line and a --- separator after each commit.
+const gitLogFormat = "%H%n%ae%n%ci%n%s%n---"
+
+// GitLog returns the most recent limit commits on branch, newest first. An
+// empty branch means the current HEAD. It returns an error wrapping
+// ErrRepoMissing if the repository has not been cloned.
+func GitLog(repo config.RepoConfig, branch string, limit int) ([]CommitSummary, error) {
+	if _, err := os.Stat(repo.Local); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s: %w", repo.Local, ErrRepoMissing)
+	}
+	if branch == "" {
+		branch = "HEAD"
+	}
+	if strings.HasPrefix(branch, "-") {
+		return nil, fmt.Errorf("invalid branch %q", branch)
+	}
+
+	out, err := gitCmd(repo.Local, "log", "--format="+gitLogFormat, "-n", fmt.Sprint(limit), branch, "--")
+	if err != nil {
+		if msg := strings.TrimSpace(gitStderr(err)); msg != "" {
+			return nil, fmt.Errorf("git log: %s", msg)
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return parseGitLog(out), nil
+}
+
+// GitDiff returns the unified diff of the working tree against the index, or
+// of the index against HEAD when staged is set, with contextLines lines of
+// context. Output beyond maxBytes is cut at the last line that fits and
+// truncated is set. No changes yield an empty diff. It returns an error
+// wrapping ErrRepoMissing if the repository has not been cloned.
+func GitDiff(repo config.RepoConfig, staged bool, contextLines, maxBytes int) (diff string, truncated bool, err error) {
+	if _, err := os.Stat(repo.Local); os.IsNotExist(err) {
+		return "", false, fmt.Errorf("%s: %w", repo.Local, ErrRepoMissing)
+	}
+
+	args := []string{"diff", "--no-color", "--no-ext-diff", fmt.Sprintf("-U%d", contextLines)}
+	if staged {
+		args = append(args, "--cached")
+	}
+	out, err := gitCmd(repo.Local, args...)
+	if err != nil {
+		if msg := strings.TrimSpace(gitStderr(err)); msg != "" {
+			return "", false, fmt.Errorf("git diff: %s", msg)
+		}
+		return "", false, fmt.Errorf("git diff: %w", err)
+	}
+	if len(out) <= maxBytes {
+		return out, false, nil
+	}
+	out = out[:maxBytes]
+	if i := strings.LastIndexByte(out, '\n'); i >= 0 {
+		out = out[:i+1]
+	}
+	return out, true, nil
+}
+
+func parseGitLog(out string) []CommitSummary {
+	commits := []CommitSummary{}
+	var fields []string
+	for _, line := range strings.Split(out, "\n") {
+		if line != "---" {
+			fields = append(fields, line)
+			continue
+		}
+		if len(fields) == 4 {
+			c := CommitSummary{SHA: fields[0], Author: fields[1], Subject: fields[3]}
+			c.Time, _ = time.Parse("2006-01-02 15:04:05 -0700", fields[2])
+			commits = append(commits, c)
+		}
+		fields = nil
+	}
+	return commits
+}
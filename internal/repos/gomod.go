@@ -0,0 +1,39 @@
+package repos
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readGoMod returns the module path and go directive version declared in
+// dir/go.mod, scanning lines rather than running the go command. Either is
+// empty if go.mod is missing or does not declare it.
+func readGoMod(dir string) (module, goVersion string) {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			module = fields[1]
+			if unquoted, err := strconv.Unquote(module); err == nil {
+				module = unquoted
+			}
+		case "go":
+			goVersion = fields[1]
+		}
+	}
+	return module, goVersion
+}
@@ -0,0 +1,29 @@
+package repos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadGoMod(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte(`// Deprecated: use example.com/v2.
+module "github.com/acme/myrepo" // the module
+
+go 1.22
+
+require (
+	example.com/dep v1.0.0
+)
+`), 0644)
+
+	module, goVersion := readGoMod(dir)
+	if module != "github.com/acme/myrepo" || goVersion != "1.22" {
+		t.Errorf("readGoMod = %q, %q", module, goVersion)
+	}
+
+	if module, goVersion := readGoMod(t.TempDir()); module != "" || goVersion != "" {
+		t.Errorf("expected nothing without go.mod, got %q, %q", module, goVersion)
+	}
+}
@@ -0,0 +1,143 @@
+package repos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/version"
+)
+
+// RepoHealth is a repository's health score and the issues that lowered it.
+type RepoHealth struct {
+	Name   string   `json:"name"`
+	Score  int      `json:"score"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// HealthReport is the content of state/health.json. Overall is the average
+// score, rounded, or zero when there are no repositories.
+type HealthReport struct {
+	Overall             int          `json:"overall"`
+	Repos               []RepoHealth `json:"repos"`
+	ScannedAt           time.Time    `json:"scanned_at"`
+	OrchestratorVersion string       `json:"orchestrator_version,omitempty"`
+}
+
+// healthDeduction is one issue and the points it costs.
+type healthDeduction struct {
+	issue  string
+	points int
+}
+
+// maxUntrackedDeduction caps the points lost to untracked files.
+const maxUntrackedDeduction = 20
+
+// healthDeductions lists what lowers a repository's health score.
+func healthDeductions(s RepoStatus) []healthDeduction {
+	var d []healthDeduction
+	if !s.Exists {
+		d = append(d, healthDeduction{"missing", 20})
+	}
+	// Untracked files and conflicts have their own deductions, so only
+	// modified files and stashes make a repo dirty here.
+	if s.Exists && (s.ModifiedFiles > 0 || s.StashCount > 0) {
+		d = append(d, healthDeduction{"dirty", 10})
+	}
+	if s.UntrackedFiles > 0 {
+		d = append(d, healthDeduction{fmt.Sprintf("%d untracked", s.UntrackedFiles), min(5*s.UntrackedFiles, maxUntrackedDeduction)})
+	}
+	if s.Behind > 0 {
+		d = append(d, healthDeduction{fmt.Sprintf("%d behind", s.Behind), 10})
+	}
+	if s.NoUpstream {
+		d = append(d, healthDeduction{"no upstream", 5})
+	}
+	if s.ConflictFiles > 0 {
+		d = append(d, healthDeduction{"conflicts", 15})
+	}
+	if s.HasBrokenSymlinks {
+		d = append(d, healthDeduction{"broken symlinks", 10})
+	}
+	return d
+}
+
+// ComputeHealthScore scores a repository from 0 to 100: it starts at 100 and
+// loses 20 if missing, 10 with modified files or stashes, 5 per untracked
+// file (at most 20), 10 if behind its upstream, 5 without an upstream, 15
+// with conflicted files, and 10 with broken symlinks.
+func ComputeHealthScore(status RepoStatus) int {
+	score := 100
+	for _, d := range healthDeductions(status) {
+		score -= d.points
+	}
+	return max(score, 0)
+}
+
+// ComputeHealth scores each repository, in the order given.
+func ComputeHealth(statuses []RepoStatus) HealthReport {
+	report := HealthReport{Repos: []RepoHealth{}, ScannedAt: time.Now(), OrchestratorVersion: version.Version}
+	total := 0
+	for _, s := range statuses {
+		h := RepoHealth{Name: s.Name, Score: ComputeHealthScore(s)}
+		for _, d := range healthDeductions(s) {
+			h.Issues = append(h.Issues, d.issue)
+		}
+		total += h.Score
+		report.Repos = append(report.Repos, h)
+	}
+	if n := len(statuses); n > 0 {
+		report.Overall = (total + n/2) / n
+	}
+	return report
+}
+
+// WriteHealthFile scores statuses and writes the report to
+// state/health.json, replacing it atomically.
+func WriteHealthFile(rootPath string, statuses []RepoStatus) error {
+	data, err := json.MarshalIndent(ComputeHealth(statuses), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStateFile(rootPath, "health.json", data)
+}
+
+// LoadHealthFile reads the report previously written by WriteHealthFile.
+func LoadHealthFile(rootPath string) (HealthReport, error) {
+	data, err := os.ReadFile(filepath.Join(rootPath, "state", "health.json"))
+	if err != nil {
+		return HealthReport{}, err
+	}
+	var report HealthReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return HealthReport{}, fmt.Errorf("parsing health.json: %w", err)
+	}
+	version.WarnIfIncompatible("health.json", report.OrchestratorVersion)
+	return report, nil
+}
+
+// WriteHealthTable writes the SCORE/REPO/ISSUES table, lowest score first
+// with ties by name, followed by the overall score.
+func WriteHealthTable(w io.Writer, report HealthReport) {
+	sorted := append([]RepoHealth{}, report.Repos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Score != sorted[j].Score {
+			return sorted[i].Score < sorted[j].Score
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	fmt.Fprintf(w, "%-5s %-20s %s\n", "SCORE", "REPO", "ISSUES")
+	for _, h := range sorted {
+		issues := strings.Join(h.Issues, ", ")
+		if issues == "" {
+			issues = "-"
+		}
+		fmt.Fprintf(w, "%5d %-20s %s\n", h.Score, h.Name, issues)
+	}
+	fmt.Fprintf(w, "\nOverall: %d (%d repositories)\n", report.Overall, len(report.Repos))
+}
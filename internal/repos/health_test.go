@@ -0,0 +1,89 @@
+package repos
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestComputeHealthScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		status RepoStatus
+		want   int
+	}{
+		{"clean", RepoStatus{Exists: true, Clean: true}, 100},
+		{"missing", RepoStatus{}, 80},
+		{"dirty and behind", RepoStatus{Exists: true, ModifiedFiles: 1, Behind: 3}, 80},
+		{"stashed", RepoStatus{Exists: true, StashCount: 1}, 90},
+		{"single untracked", RepoStatus{Exists: true, UntrackedFiles: 1}, 95},
+		{"untracked", RepoStatus{Exists: true, Clean: true, UntrackedFiles: 2}, 90},
+		{"untracked capped", RepoStatus{Exists: true, Clean: true, UntrackedFiles: 9}, 80},
+		{"everything", RepoStatus{Exists: true, ModifiedFiles: 3, UntrackedFiles: 10, Behind: 1, NoUpstream: true, ConflictFiles: 2, HasBrokenSymlinks: true}, 30},
+	}
+	for _, tt := range tests {
+		if got := ComputeHealthScore(tt.status); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestComputeHealth_Overall(t *testing.T) {
+	report := ComputeHealth([]RepoStatus{
+		{Name: "alpha", Exists: true, Clean: true},
+		{Name: "beta", Exists: true, ModifiedFiles: 2},
+		{Name: "gamma", Exists: true, Clean: true, NoUpstream: true},
+	})
+	if report.Overall != 95 {
+		t.Errorf("overall = %d, want 95", report.Overall)
+	}
+	if len(report.Repos) != 3 || report.Repos[1].Name != "beta" || strings.Join(report.Repos[1].Issues, ",") != "dirty" {
+		t.Errorf("unexpected repos: %+v", report.Repos)
+	}
+	if empty := ComputeHealth(nil); empty.Overall != 0 || len(empty.Repos) != 0 {
+		t.Errorf("unexpected empty report: %+v", empty)
+	}
+}
+
+func TestHealthFile_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	statuses := []RepoStatus{
+		{Name: "alpha", Exists: true, Clean: true},
+		{Name: "beta", Exists: true, UntrackedFiles: 1},
+	}
+	if err := WriteHealthFile(root, statuses); err != nil {
+		t.Fatalf("WriteHealthFile: %v", err)
+	}
+	report, err := LoadHealthFile(root)
+	if err != nil {
+		t.Fatalf("LoadHealthFile: %v", err)
+	}
+	if report.Overall != 98 || len(report.Repos) != 2 || report.Repos[1].Score != 95 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestWriteHealthTable_WorstFirst(t *testing.T) {
+	report := HealthReport{Overall: 90, Repos: []RepoHealth{
+		{Name: "gamma", Score: 100},
+		{Name: "beta", Score: 80, Issues: []string{"missing"}},
+		{Name: "alpha", Score: 100},
+	}}
+	var buf bytes.Buffer
+	WriteHealthTable(&buf, report)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var names []string
+	for _, line := range lines[1:4] {
+		names = append(names, strings.Fields(line)[1])
+	}
+	if got := strings.Join(names, ","); got != "beta,alpha,gamma" {
+		t.Errorf("order = %s, want beta,alpha,gamma", got)
+	}
+	if !strings.Contains(lines[1], "missing") || !strings.Contains(lines[2], " -") {
+		t.Errorf("unexpected issues column:\n%s", buf.String())
+	}
+	if lines[len(lines)-1] != "Overall: 90 (3 repositories)" {
+		t.Errorf("unexpected summary %q", lines[len(lines)-1])
+	}
+}
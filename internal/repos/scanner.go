@@ -1,39 +1,127 @@
 package repos
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/version"
 )
 
 // RepoStatus captures the git status of a repository.
 type RepoStatus struct {
-	Name          string    `json:"name"`
-	Path          string    `json:"path"`
-	Exists        bool      `json:"exists"`
-	Branch        string    `json:"branch,omitempty"`
-	Clean         bool      `json:"clean"`
-	ModifiedFiles int       `json:"modified_files"`
-	UntrackedFiles int      `json:"untracked_files"`
-	Ahead         int       `json:"ahead"`
-	Behind        int       `json:"behind"`
-	LastCommit    string    `json:"last_commit,omitempty"`
-	Error         string    `json:"error,omitempty"`
-	ScannedAt     time.Time `json:"scanned_at"`
-}
-
-// ScanRepo checks the git status of a single repository.
+	Name           string    `json:"name"`
+	Path           string    `json:"path"`
+	Exists         bool      `json:"exists"`
+	Branch         string    `json:"branch,omitempty"`
+	Clean          bool      `json:"clean"`
+	ModifiedFiles  int       `json:"modified_files"`
+	UntrackedFiles int       `json:"untracked_files"`
+	ConflictFiles  int       `json:"conflict_files"`
+	StashCount     int       `json:"stash_count"`
+	NoUpstream     bool      `json:"no_upstream,omitempty"`
+	Ahead          int       `json:"ahead"`
+	Behind         int       `json:"behind"`
+	LastCommit     string    `json:"last_commit,omitempty"`
+	LastCommitAt   time.Time `json:"last_commit_at,omitzero"`
+	HasClaudeMD    bool      `json:"has_claude_md"`
+	ClaudeMDPath   string    `json:"claude_md_path,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	ScannedAt      time.Time `json:"scanned_at"`
+	// RemoteAhead and RemoteBehind hold the same counts as Ahead and Behind:
+	// commits on HEAD that the remote lacks, and commits on the remote that
+	// HEAD lacks. The remote is the upstream branch, or origin/<default
+	// branch> when no upstream is configured.
+	RemoteAhead  int `json:"remote_ahead"`
+	RemoteBehind int `json:"remote_behind"`
+	// DependsOn lists the managed repositories this one requires. It is only
+	// filled in by ResolveDependencies.
+	DependsOn []string `json:"depends_on,omitempty"`
+	// CISystem is the CI system found in the working tree, falling back to
+	// the configured CI.System, then "none".
+	CISystem string `json:"ci_system,omitempty"`
+	// BrokenSymlinks lists tracked symlinks whose targets do not exist,
+	// relative to the repository root. Any broken symlink makes the repo
+	// unclean.
+	BrokenSymlinks    []string `json:"broken_symlinks,omitempty"`
+	HasBrokenSymlinks bool     `json:"has_broken_symlinks,omitempty"`
+	// InstalledHooks lists the executable git hooks in the repository's hooks
+	// directory, and MissingHooks the configured ExpectedHooks not among them.
+	InstalledHooks []string `json:"installed_hooks,omitempty"`
+	MissingHooks   []string `json:"missing_hooks,omitempty"`
+	// LargeStagedFiles lists staged files larger than LargeFileThreshold.
+	// Any of them makes the repo unclean.
+	LargeStagedFiles []StagedFile `json:"large_staged_files,omitempty"`
+	// GoModule and GoVersion are the module path and go directive from
+	// go.mod, for repositories that have one.
+	GoModule  string `json:"go_module,omitempty"`
+	GoVersion string `json:"go_version,omitempty"`
+}
+
+// StagedFile is a staged path and its size in the working tree.
+type StagedFile struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// LargeFileThresholdEnv overrides DefaultLargeFileThreshold with a size in
+// bytes.
+const LargeFileThresholdEnv = "ORCHESTRATOR_LARGE_FILE_THRESHOLD"
+
+// DefaultLargeFileThreshold is the size above which a staged file is
+// reported in LargeStagedFiles.
+const DefaultLargeFileThreshold = 1 << 20
+
+// LargeFileThreshold returns the staged file size limit: the value of
+// LargeFileThresholdEnv if it is a positive integer, else
+// DefaultLargeFileThreshold.
+func LargeFileThreshold() int64 {
+	if n, err := strconv.ParseInt(os.Getenv(LargeFileThresholdEnv), 10, 64); err == nil && n > 0 {
+		return n
+	}
+	return DefaultLargeFileThreshold
+}
+
+// conflictCodes are the porcelain XY codes for unmerged paths.
+var conflictCodes = map[string]bool{
+	"UU": true, "AA": true, "DD": true,
+	"AU": true, "UA": true, "DU": true, "UD": true,
+}
+
+// claudeMDPaths are the locations checked for AI instructions, in order.
+var claudeMDPaths = []string{"CLAUDE.md", filepath.Join(".claude", "CLAUDE.md")}
+
+// FindClaudeMD returns the path of the first CLAUDE.md found in dir, or "".
+func FindClaudeMD(dir string) string {
+	for _, rel := range claudeMDPaths {
+		path := filepath.Join(dir, rel)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// ScanRepo checks the git status of a single repository. HasClaudeMD and
+// CISystem are detected from the working tree, falling back to the configured
+// values when the directory does not exist or, for CISystem, holds no CI
+// configuration. Branches without an upstream are compared
+// against origin/<DefaultBranch> for Ahead and Behind.
 func ScanRepo(repo config.RepoConfig) RepoStatus {
 	status := RepoStatus{
-		Name:      repo.Name,
-		Path:      repo.Local,
-		ScannedAt: time.Now(),
+		Name:        repo.Name,
+		Path:        repo.Local,
+		HasClaudeMD: repo.HasClaudeMD,
+		CISystem:    ciSystem("", repo),
+		ScannedAt:   time.Now(),
 	}
 
 	if _, err := os.Stat(repo.Local); os.IsNotExist(err) {
@@ -41,67 +129,293 @@ func ScanRepo(repo config.RepoConfig) RepoStatus {
 		return status
 	}
 	status.Exists = true
+	status.ClaudeMDPath = FindClaudeMD(repo.Local)
+	status.HasClaudeMD = status.ClaudeMDPath != ""
+	status.CISystem = ciSystem(DetectCISystem(repo.Local), repo)
 
 	// Current branch
 	if out, err := gitCmd(repo.Local, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
 		status.Branch = strings.TrimSpace(out)
 	}
 
-	// Porcelain status
+	// Porcelain status. stagedOnly counts paths whose changes are all in the
+	// index, for the default-branch check below.
+	stagedOnly := 0
 	if out, err := gitCmd(repo.Local, "status", "--porcelain"); err == nil {
-		lines := strings.Split(strings.TrimSpace(out), "\n")
+		// Trim only newlines: the leading space of " M file" is part of the XY code.
+		lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
 		if len(lines) == 1 && lines[0] == "" {
 			status.Clean = true
 		} else {
 			for _, line := range lines {
-				if strings.HasPrefix(line, "??") {
+				if len(line) >= 2 && line[0] != '?' && line[1] == ' ' {
+					stagedOnly++
+				}
+				switch {
+				case strings.HasPrefix(line, "??"):
 					status.UntrackedFiles++
-				} else {
+				case len(line) >= 2 && conflictCodes[line[:2]]:
+					status.ConflictFiles++
+				default:
 					status.ModifiedFiles++
 				}
 			}
 		}
 	}
 
+	// Stashed changes. A stash counts as unfinished work, so the repo is not clean.
+	if out, err := gitCmd(repo.Local, "stash", "list", "--format=%gd"); err == nil {
+		if trimmed := strings.TrimSpace(out); trimmed != "" {
+			status.StashCount = len(strings.Split(trimmed, "\n"))
+			status.Clean = false
+		}
+	}
+
+	status.BrokenSymlinks = brokenSymlinks(repo.Local)
+	if len(status.BrokenSymlinks) > 0 {
+		status.HasBrokenSymlinks = true
+		status.Clean = false
+	}
+
+	status.LargeStagedFiles = largeStagedFiles(repo.Local, LargeFileThreshold())
+	if len(status.LargeStagedFiles) > 0 {
+		status.Clean = false
+	}
+
+	status.GoModule, status.GoVersion = readGoMod(repo.Local)
+
+	status.InstalledHooks = installedHooks(repo.Local)
+	for _, hook := range repo.ExpectedHooks {
+		if !slices.Contains(status.InstalledHooks, hook) {
+			status.MissingHooks = append(status.MissingHooks, hook)
+		}
+	}
+
 	// Last commit
 	if out, err := gitCmd(repo.Local, "log", "--oneline", "-1"); err == nil {
 		status.LastCommit = strings.TrimSpace(out)
 	}
+	if out, err := gitCmd(repo.Local, "log", "-1", "--format=%cI"); err == nil {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(out)); err == nil {
+			status.LastCommitAt = t
+		}
+	}
 
-	// Ahead/behind tracking branch
-	if out, err := gitCmd(repo.Local, "rev-list", "--left-right", "--count", "HEAD...@{upstream}"); err == nil {
-		parts := strings.Fields(strings.TrimSpace(out))
+	// Ahead/behind tracking branch, falling back to origin/<default branch>
+	// for branches without one.
+	counts, err := gitCmd(repo.Local, "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil && strings.Contains(gitStderr(err), "no upstream configured") {
+		status.NoUpstream = true
+		if repo.DefaultBranch != "" {
+			counts, err = gitCmd(repo.Local, "rev-list", "--left-right", "--count", "HEAD...origin/"+repo.DefaultBranch)
+		}
+	}
+	if err == nil {
+		parts := strings.Fields(strings.TrimSpace(counts))
 		if len(parts) == 2 {
 			fmt.Sscanf(parts[0], "%d", &status.Ahead)
 			fmt.Sscanf(parts[1], "%d", &status.Behind)
 		}
 	}
+	status.RemoteAhead = status.Ahead
+	status.RemoteBehind = status.Behind
+
+	// A checkout sitting exactly on origin/<default branch> whose only changes
+	// are staged counts as clean: staged work there is about to be committed
+	// on top of the published branch, not drift from it. Unstaged, untracked,
+	// and conflicted files, stashes, broken symlinks, and large staged files
+	// still make the repo unclean.
+	if !status.Clean && status.StashCount == 0 && stagedOnly == status.ModifiedFiles &&
+		status.UntrackedFiles == 0 && status.ConflictFiles == 0 &&
+		!status.HasBrokenSymlinks && len(status.LargeStagedFiles) == 0 && atDefaultBranch(repo) {
+		status.Clean = true
+	}
 
 	return status
 }
 
+// brokenSymlinks returns the tracked symlinks (index mode 120000) in dir
+// whose targets cannot be resolved. Paths missing from the working tree are
+// reported as deletions by git status, not here.
+func brokenSymlinks(dir string) []string {
+	out, err := gitCmd(dir, "ls-files", "-s", "-z")
+	if err != nil {
+		return nil
+	}
+	var broken []string
+	for _, entry := range strings.Split(out, "\x00") {
+		// "<mode> <object> <stage>\t<path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		if !ok || !strings.HasPrefix(meta, "120000 ") {
+			continue
+		}
+		full := filepath.Join(dir, path)
+		if info, err := os.Lstat(full); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		if _, err := os.Stat(full); err != nil {
+			broken = append(broken, path)
+		}
+	}
+	return broken
+}
+
+// largeStagedFiles returns the files staged in dir, other than deletions,
+// whose working tree copy is larger than threshold bytes.
+func largeStagedFiles(dir string, threshold int64) []StagedFile {
+	out, err := gitCmd(dir, "diff", "--staged", "--name-only", "--diff-filter=d", "-z")
+	if err != nil {
+		return nil
+	}
+	var large []StagedFile
+	for _, path := range strings.Split(out, "\x00") {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, path))
+		if err != nil || !info.Mode().IsRegular() || info.Size() <= threshold {
+			continue
+		}
+		large = append(large, StagedFile{Path: path, SizeBytes: info.Size()})
+	}
+	return large
+}
+
+// installedHooks returns the names of the hooks git would run in dir, sorted:
+// executable files in its hooks directory (core.hooksPath if set), skipping
+// the *.sample files git init leaves behind.
+func installedHooks(dir string) []string {
+	out, err := gitCmd(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return nil
+	}
+	hooksDir := strings.TrimSpace(out)
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(dir, hooksDir)
+	}
+	entries, err := os.ReadDir(hooksDir)
+	if err != nil {
+		return nil
+	}
+	var hooks []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".sample") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(hooksDir, e.Name()))
+		if err != nil || !info.Mode().IsRegular() || info.Mode()&0111 == 0 {
+			continue
+		}
+		hooks = append(hooks, e.Name())
+	}
+	return hooks
+}
+
+// ciSystem returns detected if set, else the configured system, else "none".
+func ciSystem(detected string, repo config.RepoConfig) string {
+	switch {
+	case detected != "":
+		return detected
+	case repo.CI.System != "":
+		return repo.CI.System
+	default:
+		return "none"
+	}
+}
+
+// atDefaultBranch reports whether HEAD is the same commit as
+// origin/<default branch>.
+func atDefaultBranch(repo config.RepoConfig) bool {
+	if repo.DefaultBranch == "" {
+		return false
+	}
+	out, err := gitCmd(repo.Local, "rev-parse", "HEAD", "origin/"+repo.DefaultBranch)
+	if err != nil {
+		return false
+	}
+	shas := strings.Fields(out)
+	return len(shas) == 2 && shas[0] == shas[1]
+}
+
 // ScanAll scans all configured repositories and returns their statuses.
 func ScanAll(cfg *config.Config) []RepoStatus {
+	statuses, _ := ScanAllContext(context.Background(), cfg)
+	return statuses
+}
+
+// ScanAllContext is ScanAll that stops before the next repository once ctx
+// is done, returning the statuses collected so far and ctx.Err().
+func ScanAllContext(ctx context.Context, cfg *config.Config) ([]RepoStatus, error) {
+	return ScanReposContext(ctx, cfg.AllRepos(), nil)
+}
+
+// ScanAllWithProgress is ScanAll with a callback invoked with each status as
+// soon as it is computed. See ScanReposWithProgress.
+func ScanAllWithProgress(cfg *config.Config, callback func(RepoStatus)) []RepoStatus {
+	return ScanReposWithProgress(cfg.AllRepos(), callback)
+}
+
+// ScanReposWithProgress scans the given repositories, calling callback (if
+// non-nil) with each status in completion order. Calls are never concurrent,
+// so the callback may write to a terminal without extra locking. The returned
+// slice is in input order.
+func ScanReposWithProgress(list []config.RepoConfig, callback func(RepoStatus)) []RepoStatus {
+	results, _ := ScanReposContext(context.Background(), list, callback)
+	return results
+}
+
+// ScanReposContext is ScanReposWithProgress that checks ctx between
+// repositories. Once ctx is done it returns the statuses collected so far,
+// in input order, along with ctx.Err(); a scan already in progress is allowed
+// to finish.
+func ScanReposContext(ctx context.Context, list []config.RepoConfig, callback func(RepoStatus)) ([]RepoStatus, error) {
 	var results []RepoStatus
-	for _, repo := range cfg.AllRepos() {
-		results = append(results, ScanRepo(repo))
+	for _, repo := range list {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		status := ScanRepo(repo)
+		if callback != nil {
+			callback(status)
+		}
+		results = append(results, status)
 	}
-	return results
+	return results, nil
+}
+
+// StatusFileVersion is the schema version WriteStatusFile records in
+// state/repo-status.json. Version 1 files are a bare array of RepoStatus.
+const StatusFileVersion = 2
+
+// statusFile is the layout of state/repo-status.json from version 2 on.
+type statusFile struct {
+	Version   int          `json:"version"`
+	ScannedAt time.Time    `json:"scanned_at"`
+	Repos     []RepoStatus `json:"repos"`
+	// OrchestratorVersion is the version of the binary that wrote the file.
+	OrchestratorVersion string `json:"orchestrator_version,omitempty"`
 }
 
-// WriteStatusFile writes scan results to the state directory.
+// WriteStatusFile writes scan results to state/repo-status.json, along with
+// a human-readable state/repo-status.txt. Both files are replaced atomically.
 func WriteStatusFile(rootPath string, statuses []RepoStatus) error {
-	stateDir := filepath.Join(rootPath, "state")
-	if err := os.MkdirAll(stateDir, 0755); err != nil {
+	now := time.Now()
+	data, err := json.MarshalIndent(statusFile{Version: StatusFileVersion, ScannedAt: now, Repos: statuses, OrchestratorVersion: version.Version}, "", "  ")
+	if err != nil {
 		return err
 	}
-
-	data, err := json.MarshalIndent(statuses, "", "  ")
-	if err != nil {
+	if err := writeStateFile(rootPath, "repo-status.json", data); err != nil {
 		return err
 	}
+	return WriteStatusTextFile(rootPath, statuses, now)
+}
 
-	return os.WriteFile(filepath.Join(stateDir, "repo-status.json"), data, 0644)
+// gitStderr returns the stderr captured for a failed gitCmd, if any.
+func gitStderr(err error) string {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return string(exitErr.Stderr)
+	}
+	return ""
 }
 
 func gitCmd(dir string, args ...string) (string, error) {
@@ -0,0 +1,454 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// initTestRepo creates a git repository with a single committed file.
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test")
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello\n"), 0644)
+	runGit(t, dir, "add", "README.md")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+func TestScanRepo_Missing(t *testing.T) {
+	status := ScanRepo(config.RepoConfig{Name: "gone", Local: filepath.Join(t.TempDir(), "gone")})
+	if status.Exists || status.Error == "" {
+		t.Errorf("expected missing repo, got %+v", status)
+	}
+}
+
+func TestScanReposContext_Cancel(t *testing.T) {
+	list := []config.RepoConfig{
+		{Name: "one", Local: filepath.Join(t.TempDir(), "one")},
+		{Name: "two", Local: filepath.Join(t.TempDir(), "two")},
+		{Name: "three", Local: filepath.Join(t.TempDir(), "three")},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses, err := ScanReposContext(ctx, list, func(s RepoStatus) {
+		if s.Name == "two" {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(statuses) != 2 || statuses[1].Name != "two" {
+		t.Errorf("expected the two statuses scanned before cancelling, got %+v", statuses)
+	}
+
+	statuses, err = ScanReposContext(context.Background(), list, nil)
+	if err != nil || len(statuses) != 3 {
+		t.Errorf("expected a full scan, got %d statuses (%v)", len(statuses), err)
+	}
+}
+
+func TestScanRepo_CleanAndDirty(t *testing.T) {
+	dir := initTestRepo(t)
+	repo := config.RepoConfig{Name: "test", Local: dir}
+
+	status := ScanRepo(repo)
+	if !status.Exists || !status.Clean || status.Branch != "main" {
+		t.Fatalf("expected clean repo on main, got %+v", status)
+	}
+	if status.LastCommitAt.IsZero() || time.Since(status.LastCommitAt) > time.Hour {
+		t.Errorf("expected a recent last commit time, got %v", status.LastCommitAt)
+	}
+
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644)
+
+	status = ScanRepo(repo)
+	if status.Clean || status.ModifiedFiles != 1 || status.UntrackedFiles != 1 {
+		t.Errorf("expected 1 modified and 1 untracked, got %+v", status)
+	}
+}
+
+func TestScanRepo_StashMakesRepoUnclean(t *testing.T) {
+	dir := initTestRepo(t)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("stash me\n"), 0644)
+	runGit(t, dir, "stash", "-q")
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if status.StashCount != 1 {
+		t.Errorf("expected 1 stash, got %d", status.StashCount)
+	}
+	if status.Clean {
+		t.Error("expected repo with stash to not be clean")
+	}
+	if status.ModifiedFiles != 0 || status.UntrackedFiles != 0 {
+		t.Errorf("expected clean working tree, got %+v", status)
+	}
+}
+
+func TestScanRepo_NoUpstream(t *testing.T) {
+	dir := initTestRepo(t)
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if !status.NoUpstream {
+		t.Errorf("expected NoUpstream for branch without tracking, got %+v", status)
+	}
+
+	// A tracked branch clears the flag.
+	remote := t.TempDir()
+	runGit(t, remote, "init", "-q", "--bare")
+	runGit(t, dir, "remote", "add", "origin", remote)
+	runGit(t, dir, "push", "-q", "-u", "origin", "main")
+
+	status = ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if status.NoUpstream {
+		t.Errorf("expected upstream to be detected, got %+v", status)
+	}
+}
+
+// pushToBareRemote adds a bare origin and pushes main to it without setting
+// up tracking.
+func pushToBareRemote(t *testing.T, dir string) {
+	t.Helper()
+	remote := t.TempDir()
+	runGit(t, remote, "init", "-q", "--bare")
+	runGit(t, dir, "remote", "add", "origin", remote)
+	runGit(t, dir, "push", "-q", "origin", "main")
+}
+
+func TestScanRepo_DefaultBranchFallback(t *testing.T) {
+	dir := initTestRepo(t)
+	pushToBareRemote(t, dir)
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644)
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "commit", "-q", "-m", "local")
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir, DefaultBranch: "main"})
+	if !status.NoUpstream {
+		t.Errorf("expected NoUpstream, got %+v", status)
+	}
+	if status.Ahead != 1 || status.Behind != 0 {
+		t.Errorf("expected 1 ahead, 0 behind of origin/main, got %d/%d", status.Ahead, status.Behind)
+	}
+	if status.RemoteAhead != status.Ahead || status.RemoteBehind != status.Behind {
+		t.Errorf("expected Remote counts to match, got %+v", status)
+	}
+
+	// Without a default branch there is nothing to compare against.
+	status = ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if status.Ahead != 0 || status.Behind != 0 {
+		t.Errorf("expected no counts without a default branch, got %d/%d", status.Ahead, status.Behind)
+	}
+}
+
+func TestScanRepo_StagedAtDefaultBranchIsClean(t *testing.T) {
+	dir := initTestRepo(t)
+	pushToBareRemote(t, dir)
+	repo := config.RepoConfig{Name: "test", Local: dir, DefaultBranch: "main"}
+
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("staged\n"), 0644)
+	runGit(t, dir, "add", "README.md")
+	status := ScanRepo(repo)
+	if !status.Clean || status.ModifiedFiles != 1 {
+		t.Errorf("expected staged change on origin/main to count as clean, got %+v", status)
+	}
+
+	// Unstaged changes still make it unclean.
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("unstaged\n"), 0644)
+	if status := ScanRepo(repo); status.Clean {
+		t.Errorf("expected unstaged change to be unclean, got %+v", status)
+	}
+
+	// So does a staged change once HEAD has moved past origin/main.
+	runGit(t, dir, "add", "README.md")
+	runGit(t, dir, "commit", "-q", "-m", "local")
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("again\n"), 0644)
+	runGit(t, dir, "add", "README.md")
+	if status := ScanRepo(repo); status.Clean {
+		t.Errorf("expected staged change ahead of origin/main to be unclean, got %+v", status)
+	}
+}
+
+func TestScanRepo_MergeConflict(t *testing.T) {
+	dir := initTestRepo(t)
+	runGit(t, dir, "checkout", "-q", "-b", "other")
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("other\n"), 0644)
+	runGit(t, dir, "commit", "-q", "-am", "other")
+	runGit(t, dir, "checkout", "-q", "main")
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("main\n"), 0644)
+	runGit(t, dir, "commit", "-q", "-am", "main")
+
+	cmd := exec.Command("git", "merge", "other")
+	cmd.Dir = dir
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected merge to conflict")
+	}
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if status.ConflictFiles != 1 || status.ModifiedFiles != 0 {
+		t.Errorf("expected 1 conflicted file, got %+v", status)
+	}
+	if status.Clean {
+		t.Error("expected repo with conflicts to not be clean")
+	}
+}
+
+func TestScanRepo_LeadingSpaceStatus(t *testing.T) {
+	dir := initTestRepo(t)
+	// An unstaged modification is reported as " M README.md".
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed\n"), 0644)
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if status.ModifiedFiles != 1 || status.ConflictFiles != 0 {
+		t.Errorf("expected 1 modified file, got %+v", status)
+	}
+}
+
+func TestGitDiff(t *testing.T) {
+	dir := initTestRepo(t)
+	repo := config.RepoConfig{Name: "test", Local: dir}
+
+	if diff, truncated, err := GitDiff(repo, false, 3, 1024); err != nil || diff != "" || truncated {
+		t.Fatalf("expected no diff for a clean tree, got %q, %v, %v", diff, truncated, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello\nworld\n"), 0644)
+	diff, truncated, err := GitDiff(repo, false, 3, 1024)
+	if err != nil || truncated || !strings.Contains(diff, "+world") {
+		t.Fatalf("expected unstaged change, got %q, %v, %v", diff, truncated, err)
+	}
+	if staged, _, _ := GitDiff(repo, true, 3, 1024); staged != "" {
+		t.Errorf("expected nothing staged, got %q", staged)
+	}
+
+	runGit(t, dir, "add", "README.md")
+	if staged, _, _ := GitDiff(repo, true, 0, 1024); !strings.Contains(staged, "+world") || strings.Contains(staged, "\n hello") {
+		t.Errorf("expected staged change without context, got %q", staged)
+	}
+
+	cut, truncated, _ := GitDiff(repo, true, 3, 40)
+	if !truncated || len(cut) > 40 || !strings.HasSuffix(cut, "\n") {
+		t.Errorf("expected a truncated diff ending on a line, got %q (truncated %v)", cut, truncated)
+	}
+
+	_, _, err = GitDiff(config.RepoConfig{Name: "gone", Local: filepath.Join(dir, "gone")}, false, 3, 1024)
+	if !errors.Is(err, ErrRepoMissing) {
+		t.Errorf("expected ErrRepoMissing, got %v", err)
+	}
+}
+
+func TestGitLog(t *testing.T) {
+	dir := initTestRepo(t)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("second\n"), 0644)
+	runGit(t, dir, "commit", "-q", "-am", "second commit")
+	repo := config.RepoConfig{Name: "test", Local: dir}
+
+	commits, err := GitLog(repo, "", 10)
+	if err != nil {
+		t.Fatalf("GitLog: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %+v", commits)
+	}
+	c := commits[0]
+	if c.Subject != "second commit" || c.Author != "test@example.com" || len(c.SHA) != 40 || c.Time.IsZero() {
+		t.Errorf("unexpected commit: %+v", c)
+	}
+
+	if commits, _ := GitLog(repo, "main", 1); len(commits) != 1 {
+		t.Errorf("expected limit to be applied, got %d commits", len(commits))
+	}
+	if _, err := GitLog(repo, "no-such-branch", 10); err == nil {
+		t.Error("expected error for unknown branch")
+	}
+	if _, err := GitLog(repo, "--all", 10); err == nil {
+		t.Error("expected error for option-like branch")
+	}
+
+	_, err = GitLog(config.RepoConfig{Name: "gone", Local: filepath.Join(dir, "gone")}, "", 10)
+	if !errors.Is(err, ErrRepoMissing) {
+		t.Errorf("expected ErrRepoMissing, got %v", err)
+	}
+}
+
+func TestScanRepo_ClaudeMD(t *testing.T) {
+	dir := initTestRepo(t)
+	repo := config.RepoConfig{Name: "test", Local: dir, HasClaudeMD: true}
+
+	// Detection overrides the configured value.
+	if status := ScanRepo(repo); status.HasClaudeMD || status.ClaudeMDPath != "" {
+		t.Errorf("expected no CLAUDE.md detected, got %+v", status)
+	}
+
+	os.MkdirAll(filepath.Join(dir, ".claude"), 0755)
+	os.WriteFile(filepath.Join(dir, ".claude", "CLAUDE.md"), []byte("# notes\n"), 0644)
+	status := ScanRepo(repo)
+	if !status.HasClaudeMD || status.ClaudeMDPath != filepath.Join(dir, ".claude", "CLAUDE.md") {
+		t.Errorf("expected .claude/CLAUDE.md, got %+v", status)
+	}
+
+	os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("# notes\n"), 0644)
+	if status := ScanRepo(repo); status.ClaudeMDPath != filepath.Join(dir, "CLAUDE.md") {
+		t.Errorf("expected top-level CLAUDE.md to win, got %q", status.ClaudeMDPath)
+	}
+
+	// A missing checkout keeps the configured value.
+	missing := ScanRepo(config.RepoConfig{Name: "gone", Local: filepath.Join(dir, "gone"), HasClaudeMD: true})
+	if !missing.HasClaudeMD {
+		t.Error("expected configured HasClaudeMD for missing repo")
+	}
+}
+
+func TestScanRepo_CISystem(t *testing.T) {
+	dir := initTestRepo(t)
+	repo := config.RepoConfig{Name: "test", Local: dir}
+
+	if got := ScanRepo(repo).CISystem; got != "none" {
+		t.Errorf("expected none without CI files, got %q", got)
+	}
+	repo.CI.System = "jenkins"
+	if got := ScanRepo(repo).CISystem; got != "jenkins" {
+		t.Errorf("expected configured system as fallback, got %q", got)
+	}
+
+	os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0755)
+	os.WriteFile(filepath.Join(dir, ".github", "workflows", "ci.yml"), []byte("on: push\n"), 0644)
+	if got := ScanRepo(repo).CISystem; got != "github-actions" {
+		t.Errorf("expected detected github-actions to win, got %q", got)
+	}
+
+	missing := config.RepoConfig{Name: "gone", Local: filepath.Join(dir, "gone"), CI: config.CIConfig{System: "gitlab-ci"}}
+	if got := ScanRepo(missing).CISystem; got != "gitlab-ci" {
+		t.Errorf("expected configured system for missing repo, got %q", got)
+	}
+}
+
+func TestScanRepo_BrokenSymlinks(t *testing.T) {
+	dir := initTestRepo(t)
+	os.Symlink("README.md", filepath.Join(dir, "good"))
+	os.Symlink("nowhere", filepath.Join(dir, "bad"))
+	runGit(t, dir, "add", "good", "bad")
+	runGit(t, dir, "commit", "-q", "-m", "links")
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if len(status.BrokenSymlinks) != 1 || status.BrokenSymlinks[0] != "bad" {
+		t.Errorf("expected [bad], got %v", status.BrokenSymlinks)
+	}
+	if !status.HasBrokenSymlinks || status.Clean {
+		t.Errorf("expected broken symlink to make repo unclean, got %+v", status)
+	}
+	if status.ModifiedFiles != 0 || status.UntrackedFiles != 0 {
+		t.Errorf("expected clean working tree otherwise, got %+v", status)
+	}
+
+	// Staged-only work on origin/main does not hide the broken symlink.
+	pushToBareRemote(t, dir)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("staged\n"), 0644)
+	runGit(t, dir, "add", "README.md")
+	if status := ScanRepo(config.RepoConfig{Name: "test", Local: dir, DefaultBranch: "main"}); status.Clean {
+		t.Errorf("expected broken symlink to stay unclean on origin/main, got %+v", status)
+	}
+}
+
+func TestScanReposWithProgress(t *testing.T) {
+	dir := initTestRepo(t)
+	list := []config.RepoConfig{
+		{Name: "present", Local: dir},
+		{Name: "gone", Local: filepath.Join(dir, "gone")},
+	}
+
+	var seen []string
+	statuses := ScanReposWithProgress(list, func(s RepoStatus) { seen = append(seen, s.Name) })
+	if len(statuses) != 2 || statuses[0].Name != "present" || statuses[1].Name != "gone" {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected a callback per repo, got %v", seen)
+	}
+	if got := ScanReposWithProgress(list, nil); len(got) != 2 {
+		t.Errorf("nil callback: expected 2 statuses, got %d", len(got))
+	}
+}
+
+func TestScanRepo_Hooks(t *testing.T) {
+	dir := initTestRepo(t)
+	hooks := filepath.Join(dir, ".git", "hooks")
+	os.MkdirAll(hooks, 0755)
+	os.WriteFile(filepath.Join(hooks, "pre-commit"), []byte("#!/bin/sh\n"), 0755)
+	os.WriteFile(filepath.Join(hooks, "commit-msg"), []byte("#!/bin/sh\n"), 0644)
+	os.WriteFile(filepath.Join(hooks, "pre-push.sample"), []byte("#!/bin/sh\n"), 0755)
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir, ExpectedHooks: []string{"pre-commit", "commit-msg"}})
+	if len(status.InstalledHooks) != 1 || status.InstalledHooks[0] != "pre-commit" {
+		t.Errorf("expected only the executable pre-commit hook, got %v", status.InstalledHooks)
+	}
+	if len(status.MissingHooks) != 1 || status.MissingHooks[0] != "commit-msg" {
+		t.Errorf("expected commit-msg to be missing, got %v", status.MissingHooks)
+	}
+	if !status.Clean || !strings.Contains(StatusColumn(status), "[HOOKS]") {
+		t.Errorf("expected a clean repo flagged [HOOKS], got %q", StatusColumn(status))
+	}
+
+	// core.hooksPath moves the directory git runs hooks from.
+	runGit(t, dir, "config", "core.hooksPath", "githooks")
+	status = ScanRepo(config.RepoConfig{Name: "test", Local: dir, ExpectedHooks: []string{"pre-commit"}})
+	if len(status.InstalledHooks) != 0 || len(status.MissingHooks) != 1 {
+		t.Errorf("expected hooks from core.hooksPath only, got %+v", status)
+	}
+}
+
+func TestScanRepo_LargeStagedFiles(t *testing.T) {
+	t.Setenv(LargeFileThresholdEnv, "1000")
+	dir := initTestRepo(t)
+	os.WriteFile(filepath.Join(dir, "big.bin"), make([]byte, 2000), 0644)
+	os.WriteFile(filepath.Join(dir, "small.txt"), []byte("ok\n"), 0644)
+	runGit(t, dir, "add", "big.bin", "small.txt")
+
+	status := ScanRepo(config.RepoConfig{Name: "test", Local: dir})
+	if len(status.LargeStagedFiles) != 1 || status.LargeStagedFiles[0] != (StagedFile{Path: "big.bin", SizeBytes: 2000}) {
+		t.Errorf("expected only big.bin, got %+v", status.LargeStagedFiles)
+	}
+	if status.Clean || !strings.Contains(StatusColumn(status), "[LARGE]") {
+		t.Errorf("expected an unclean repo flagged [LARGE], got %q", StatusColumn(status))
+	}
+
+	// Staged-only work on origin/main does not hide the large file.
+	pushToBareRemote(t, dir)
+	if status := ScanRepo(config.RepoConfig{Name: "test", Local: dir, DefaultBranch: "main"}); status.Clean {
+		t.Errorf("expected large staged file to stay unclean on origin/main, got %+v", status)
+	}
+
+	t.Setenv(LargeFileThresholdEnv, "not-a-number")
+	if got := LargeFileThreshold(); got != DefaultLargeFileThreshold {
+		t.Errorf("invalid override: threshold = %d", got)
+	}
+	if status := ScanRepo(config.RepoConfig{Name: "test", Local: dir}); len(status.LargeStagedFiles) != 0 {
+		t.Errorf("expected nothing over the 1 MB default, got %+v", status.LargeStagedFiles)
+	}
+}
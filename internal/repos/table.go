@@ -0,0 +1,119 @@
+package repos
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StatusColumn renders the STATUS column for a repository: the working tree
+// state followed by any warning indicators.
+func StatusColumn(s RepoStatus) string {
+	if !s.Exists {
+		return "MISSING"
+	}
+
+	base := "clean"
+	if !s.Clean {
+		base = fmt.Sprintf("%dM/%dU", s.ModifiedFiles, s.UntrackedFiles)
+		if s.StashCount > 0 {
+			base = fmt.Sprintf("%dS/%s", s.StashCount, base)
+		}
+	}
+
+	indicators := []string{base}
+	if s.ConflictFiles > 0 {
+		indicators = append(indicators, "CONFLICT")
+	}
+	if s.NoUpstream {
+		indicators = append(indicators, "NO-UP")
+	}
+	if s.HasBrokenSymlinks {
+		indicators = append(indicators, "[SYMLINKS]")
+	}
+	if len(s.LargeStagedFiles) > 0 {
+		indicators = append(indicators, "[LARGE]")
+	}
+	if len(s.MissingHooks) > 0 {
+		indicators = append(indicators, "[HOOKS]")
+	}
+	return strings.Join(indicators, " ")
+}
+
+// ciIcons abbreviates RepoStatus.CISystem for the CI column.
+var ciIcons = map[string]string{
+	"github-actions": "GH",
+	"gitlab-ci":      "GL",
+	"jenkins":        "J",
+}
+
+// CIColumn renders the CI column for a repository: GH, GL, or J for GitHub
+// Actions, GitLab CI, or Jenkins, and "-" for none.
+func CIColumn(s RepoStatus) string {
+	if icon, ok := ciIcons[s.CISystem]; ok {
+		return icon
+	}
+	return "-"
+}
+
+// WriteStatusTable writes the REPO/BRANCH/STATUS/CI/CLAUDE/LAST COMMIT table,
+// with a ✓ in the CLAUDE column for repos that have a CLAUDE.md. If mark is
+// non-nil it is applied to the CONFLICT indicator after padding, so terminal
+// escape codes don't throw off the column widths. With showGo, a GO column
+// before LAST COMMIT shows the go.mod go version, or "-" for none.
+func WriteStatusTable(w io.Writer, statuses []RepoStatus, mark func(string) string, showGo bool) {
+	goHeader := ""
+	if showGo {
+		goHeader = fmt.Sprintf("%-7s ", "GO")
+	}
+	fmt.Fprintf(w, "%-20s %-24s %-12s %-3s %-6s %s%s\n", "REPO", "BRANCH", "STATUS", "CI", "CLAUDE", goHeader, "LAST COMMIT")
+	for _, s := range statuses {
+		commit := s.LastCommit
+		if len(commit) > 60 {
+			commit = commit[:60]
+		}
+		col := fmt.Sprintf("%-12s", StatusColumn(s))
+		if mark != nil {
+			col = strings.Replace(col, "CONFLICT", mark("CONFLICT"), 1)
+		}
+		claude := ""
+		if s.HasClaudeMD {
+			claude = "✓"
+		}
+		goCol := ""
+		if showGo {
+			version := s.GoVersion
+			if version == "" {
+				version = "-"
+			}
+			goCol = fmt.Sprintf("%-7s ", version)
+		}
+		fmt.Fprintf(w, "%-20s %-24s %s %-3s %-6s %s%s\n", s.Name, s.Branch, col, CIColumn(s), claude, goCol, commit)
+	}
+}
+
+// WriteStatusTextFile writes scan results to state/repo-status.txt as a
+// timestamped table, replacing the file atomically.
+func WriteStatusTextFile(rootPath string, statuses []RepoStatus, at time.Time) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Repository status at %s\n\n", at.Format(time.RFC3339))
+	WriteStatusTable(&b, statuses, nil, false)
+	return writeStateFile(rootPath, "repo-status.txt", []byte(b.String()))
+}
+
+// writeStateFile writes data to state/<name> via a temp file and rename.
+func writeStateFile(rootPath, name string, data []byte) error {
+	stateDir := filepath.Join(rootPath, "state")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(stateDir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,78 @@
+package repos
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusColumn(t *testing.T) {
+	tests := []struct {
+		status RepoStatus
+		want   string
+	}{
+		{RepoStatus{}, "MISSING"},
+		{RepoStatus{Exists: true, Clean: true}, "clean"},
+		{RepoStatus{Exists: true, ModifiedFiles: 2, UntrackedFiles: 1}, "2M/1U"},
+		{RepoStatus{Exists: true, StashCount: 1}, "1S/0M/0U"},
+		{RepoStatus{Exists: true, ConflictFiles: 1, NoUpstream: true}, "0M/0U CONFLICT NO-UP"},
+		{RepoStatus{Exists: true, HasBrokenSymlinks: true}, "0M/0U [SYMLINKS]"},
+	}
+	for _, tt := range tests {
+		if got := StatusColumn(tt.status); got != tt.want {
+			t.Errorf("StatusColumn(%+v): expected %q, got %q", tt.status, tt.want, got)
+		}
+	}
+}
+
+func TestCIColumn(t *testing.T) {
+	for system, want := range map[string]string{
+		"github-actions": "GH",
+		"gitlab-ci":      "GL",
+		"jenkins":        "J",
+		"none":           "-",
+		"":               "-",
+	} {
+		if got := CIColumn(RepoStatus{CISystem: system}); got != want {
+			t.Errorf("CIColumn(%q): expected %q, got %q", system, want, got)
+		}
+	}
+}
+
+func TestWriteStatusTable_MarkKeepsAlignment(t *testing.T) {
+	statuses := []RepoStatus{{Name: "alpha", Branch: "main", Exists: true, ConflictFiles: 1, LastCommit: "abc123 fix"}}
+
+	var plain, marked strings.Builder
+	WriteStatusTable(&plain, statuses, nil, false)
+	WriteStatusTable(&marked, statuses, func(s string) string { return "<" + s + ">" }, false)
+
+	want := strings.Replace(plain.String(), "CONFLICT", "<CONFLICT>", 1)
+	if marked.String() != want {
+		t.Errorf("marking changed padding:\n%s\nvs\n%s", marked.String(), want)
+	}
+}
+
+func TestWriteStatusFile_WritesTextSnapshot(t *testing.T) {
+	root := t.TempDir()
+	statuses := []RepoStatus{{Name: "alpha", Branch: "main", Exists: true, Clean: true, HasClaudeMD: true}}
+	if err := WriteStatusFile(root, statuses); err != nil {
+		t.Fatalf("WriteStatusFile: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "state", "repo-status.txt"))
+	if err != nil {
+		t.Fatalf("reading text snapshot: %v", err)
+	}
+	lines := strings.Split(string(data), "\n")
+	if !strings.HasPrefix(lines[0], "# Repository status at ") {
+		t.Errorf("expected timestamp header, got %q", lines[0])
+	}
+	if _, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[0], "# Repository status at ")); err != nil {
+		t.Errorf("header timestamp not RFC3339: %v", err)
+	}
+	if !strings.HasPrefix(lines[3], "alpha") || !strings.Contains(lines[3], "clean") || !strings.Contains(lines[3], "✓") {
+		t.Errorf("unexpected table row %q", lines[3])
+	}
+}
@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// BenchRegressionThreshold is the slowdown, in percent of ns/op, above which
+// CompareBenchToBaseline flags a benchmark as a regression.
+const BenchRegressionThreshold = 10.0
+
+// BenchResult is one benchmark line from go test -bench output.
+type BenchResult struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+}
+
+// BenchDelta compares a benchmark against its stored baseline.
+type BenchDelta struct {
+	Name            string  `json:"name"`
+	BaselineNsPerOp float64 `json:"baseline_ns_per_op"`
+	NsPerOp         float64 `json:"ns_per_op"`
+	DeltaPercent    float64 `json:"delta_percent"`
+	Regression      bool    `json:"regression"`
+}
+
+// procsSuffix matches the -GOMAXPROCS suffix go test appends to benchmark names.
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// BenchmarkRepo runs Go benchmarks matching benchRegex, writing output to
+// /tmp/orchestrator-bench-<repo>.log. An empty benchRegex runs all benchmarks.
+func BenchmarkRepo(repo config.RepoConfig, benchRegex string) Result {
+	if repo.Language != "go" {
+		return Result{
+			Repo:     repo.Name,
+			Command:  "benchmarks not supported for language: " + repo.Language,
+			ExitCode: 1,
+			RunAt:    time.Now(),
+		}
+	}
+	if benchRegex == "" {
+		benchRegex = "."
+	}
+	args := []string{"test", "./...", "-run=^$", "-bench=" + benchRegex, "-benchmem", "-benchtime=3s"}
+	return RunInRepo(repo, "go", args, "bench")
+}
+
+// ParseBenchOutput extracts benchmark results from a go test -bench log.
+// The -GOMAXPROCS suffix is stripped from names so results compare across
+// machines.
+func ParseBenchOutput(logFile string) ([]BenchResult, error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var results []BenchResult
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+			continue
+		}
+
+		r := BenchResult{Name: procsSuffix.ReplaceAllString(fields[0], "")}
+		// After the iteration count, fields come in value/unit pairs.
+		for i := 2; i+1 < len(fields); i += 2 {
+			switch fields[i+1] {
+			case "ns/op":
+				r.NsPerOp, _ = strconv.ParseFloat(fields[i], 64)
+			case "B/op":
+				r.BytesPerOp, _ = strconv.ParseInt(fields[i], 10, 64)
+			case "allocs/op":
+				r.AllocsPerOp, _ = strconv.ParseInt(fields[i], 10, 64)
+			}
+		}
+		results = append(results, r)
+	}
+	return results, scanner.Err()
+}
+
+func benchBaselinePath(rootPath, repoName string) string {
+	return filepath.Join(rootPath, "state", "bench-"+repoName+".json")
+}
+
+// WriteBenchBaseline stores results as the baseline in state/bench-<repo>.json.
+func WriteBenchBaseline(rootPath, repoName string, results []BenchResult) error {
+	stateDir := filepath.Join(rootPath, "state")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+
+	if results == nil {
+		results = []BenchResult{}
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(benchBaselinePath(rootPath, repoName), data, 0644)
+}
+
+// CompareBenchToBaseline compares results against the stored baseline for a
+// repository. Benchmarks without a baseline entry are skipped. It returns an
+// error wrapping os.ErrNotExist if no baseline has been written.
+func CompareBenchToBaseline(rootPath, repoName string, results []BenchResult) ([]BenchDelta, error) {
+	data, err := os.ReadFile(benchBaselinePath(rootPath, repoName))
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline []BenchResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parsing bench-%s.json: %w", repoName, err)
+	}
+	byName := make(map[string]BenchResult, len(baseline))
+	for _, b := range baseline {
+		byName[b.Name] = b
+	}
+
+	var deltas []BenchDelta
+	for _, r := range results {
+		b, ok := byName[r.Name]
+		if !ok || b.NsPerOp == 0 {
+			continue
+		}
+		pct := (r.NsPerOp - b.NsPerOp) / b.NsPerOp * 100
+		deltas = append(deltas, BenchDelta{
+			Name:            r.Name,
+			BaselineNsPerOp: b.NsPerOp,
+			NsPerOp:         r.NsPerOp,
+			DeltaPercent:    pct,
+			Regression:      pct > BenchRegressionThreshold,
+		})
+	}
+	return deltas, nil
+}
@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// fileChecksum returns the hex-encoded SHA-256 of a file's contents.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyLogChecksum re-reads a result's log file and reports whether it
+// still matches the LogChecksum recorded when the command exited, so a log
+// edited or replaced since then is detected.
+func VerifyLogChecksum(result Result) (bool, error) {
+	if result.LogChecksum == "" {
+		return false, fmt.Errorf("result for %s has no log checksum", result.Repo)
+	}
+	sum, err := fileChecksum(result.LogFile)
+	if err != nil {
+		return false, err
+	}
+	return sum == result.LogChecksum, nil
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// TRXPath returns the path dotnet test writes its TRX report to for a repository.
+func TRXPath(repoName string) string {
+	return filepath.Join(LogDir, fmt.Sprintf("orchestrator-test-%s.trx", repoName))
+}
+
+// testDotnet runs dotnet test with a TRX logger. A report left by an earlier
+// run is removed first, so ArtifactFile is only set for a fresh report.
+func testDotnet(repo config.RepoConfig) Result {
+	trx := TRXPath(repo.Name)
+	os.Remove(trx)
+	result := RunInRepo(repo, "dotnet", []string{"test", "--nologo", "--logger", "trx;LogFileName=" + trx}, "test")
+	if _, err := os.Stat(trx); err == nil {
+		result.ArtifactFile = trx
+	}
+	return result
+}
+
+// trxRun is the subset of a Visual Studio TRX report needed to list failures.
+type trxRun struct {
+	Results []struct {
+		TestID   string `xml:"testId,attr"`
+		TestName string `xml:"testName,attr"`
+		Outcome  string `xml:"outcome,attr"`
+		Message  string `xml:"Output>ErrorInfo>Message"`
+		Trace    string `xml:"Output>ErrorInfo>StackTrace"`
+		StdOut   string `xml:"Output>StdOut"`
+	} `xml:"Results>UnitTestResult"`
+	Definitions []struct {
+		ID     string `xml:"id,attr"`
+		Method struct {
+			ClassName string `xml:"className,attr"`
+		} `xml:"TestMethod"`
+	} `xml:"TestDefinitions>UnitTest"`
+}
+
+// ParseTRXResults extracts failed tests from a dotnet test TRX report. The
+// test's class name is reported as its package, and its output is the error
+// message and stack trace followed by anything it wrote to stdout.
+func ParseTRXResults(trxFile string) ([]TestFailure, error) {
+	data, err := os.ReadFile(trxFile)
+	if err != nil {
+		return nil, err
+	}
+	var run trxRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", trxFile, err)
+	}
+
+	classes := make(map[string]string, len(run.Definitions))
+	for _, d := range run.Definitions {
+		classes[d.ID] = d.Method.ClassName
+	}
+
+	var failures []TestFailure
+	for _, r := range run.Results {
+		if r.Outcome != "Failed" {
+			continue
+		}
+		var output []string
+		for _, s := range []string{r.Message, r.Trace, r.StdOut} {
+			if s = strings.TrimSpace(s); s != "" {
+				output = append(output, s)
+			}
+		}
+		f := TestFailure{Package: classes[r.TestID], TestName: r.TestName}
+		if len(output) > 0 {
+			f.Output = strings.Join(output, "\n") + "\n"
+		}
+		failures = append(failures, f)
+	}
+	return failures, nil
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// mixSummaryRe matches ExUnit's summary line, such as "12 tests, 1 failure"
+// or "1 doctest, 5 tests, 0 failures (2 excluded)".
+var mixSummaryRe = regexp.MustCompile(`(\d+) tests?, (\d+) failures?`)
+
+// runMix runs a mix task, or fails with exit code 127 and a hint in the log
+// when mix is not installed.
+func runMix(repo config.RepoConfig, args []string, logPrefix string) Result {
+	if _, err := exec.LookPath("mix"); err != nil {
+		return missingTool(repo, "mix", args, logPrefix, "install Elixir (https://elixir-lang.org/install.html)")
+	}
+	return RunInRepo(repo, "mix", args, logPrefix)
+}
+
+// missingTool returns the Result for a command that could not start because
+// program is not in PATH, writing hint to its log file.
+func missingTool(repo config.RepoConfig, program string, args []string, logPrefix, hint string) Result {
+	logFile := LogPath(logPrefix, repo.Name)
+	os.WriteFile(logFile, []byte(fmt.Sprintf("ERROR: %s not found in PATH; %s to build and test %s\n", program, hint, repo.Name)), 0644)
+	checksum, _ := fileChecksum(logFile)
+	return Result{
+		Repo:        repo.Name,
+		Command:     fmt.Sprintf("%s %s", program, joinArgs(args)),
+		LogFile:     logFile,
+		ExitCode:    127,
+		FailureKind: FailureOther,
+		RunAt:       time.Now(),
+		LogChecksum: checksum,
+	}
+}
+
+// ParseMixTestSummary totals the "N tests, M failures" summary lines in a
+// mix test log. Umbrella projects print one per app.
+func ParseMixTestSummary(logFile string) (tests, failures int, err error) {
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, m := range mixSummaryRe.FindAllStringSubmatch(string(data), -1) {
+		n, _ := strconv.Atoi(m[1])
+		f, _ := strconv.Atoi(m[2])
+		tests += n
+		failures += f
+	}
+	return tests, failures, nil
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"os"
+	"strings"
+)
+
+// FailureKind classifies why a command failed.
+type FailureKind string
+
+const (
+	// FailureFFOnlyConflict means git pull --ff-only refused because the
+	// local branch has diverged from its upstream.
+	FailureFFOnlyConflict FailureKind = "ff-only-conflict"
+	// FailureNetwork means the remote could not be reached.
+	FailureNetwork FailureKind = "network"
+	// FailureMissing means the repository directory does not exist.
+	FailureMissing FailureKind = "missing"
+	// FailureTimeout means the command was killed after RunOptions.Timeout.
+	FailureTimeout FailureKind = "timeout"
+	// FailureOther covers every other failure.
+	FailureOther FailureKind = "other"
+)
+
+// failurePatterns maps log output to a failure kind. Checked in order.
+var failurePatterns = []struct {
+	kind     FailureKind
+	patterns []string
+}{
+	{FailureFFOnlyConflict, []string{
+		"Not possible to fast-forward",
+		"You have divergent branches",
+	}},
+	{FailureNetwork, []string{
+		"Could not resolve host",
+		"Could not read from remote repository",
+		"Connection timed out",
+		"Connection refused",
+		"Network is unreachable",
+		"unable to access",
+	}},
+}
+
+// ClassifyFailure inspects a failed result's log files and returns the kind
+// of failure. It returns "" for successful results.
+func ClassifyFailure(result Result) FailureKind {
+	if result.Success {
+		return ""
+	}
+
+	var output string
+	for _, path := range []string{result.LogFile, result.StderrFile} {
+		if path == "" {
+			continue
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			output += string(data)
+		}
+	}
+
+	for _, fp := range failurePatterns {
+		for _, p := range fp.patterns {
+			if strings.Contains(output, p) {
+				return fp.kind
+			}
+		}
+	}
+	return FailureOther
+}
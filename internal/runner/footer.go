@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logFooterPrefix starts the footer line RunInRepo appends to each log.
+const logFooterPrefix = "[orchestrator] "
+
+// LogFooter is the outcome line RunInRepo appends to a log after the command exits.
+type LogFooter struct {
+	ExitCode   int       `json:"exit_code"`
+	Duration   float64   `json:"duration_seconds"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// writeLogFooter appends the footer line to f, starting a new line first if
+// the command's output did not end with one.
+func writeLogFooter(f *os.File, footer LogFooter) error {
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			f.WriteString("\n")
+		}
+	}
+	_, err := f.WriteString(formatLogFooter(footer) + "\n")
+	return err
+}
+
+func formatLogFooter(footer LogFooter) string {
+	return fmt.Sprintf("%sexit_code=%d duration=%.2fs finished_at=%s",
+		logFooterPrefix, footer.ExitCode, footer.Duration, footer.FinishedAt.UTC().Format(time.RFC3339))
+}
+
+// isLogFooter reports whether line is a footer written by RunInRepo.
+func isLogFooter(line string) bool {
+	_, err := parseLogFooter(line)
+	return err == nil
+}
+
+// ReadLogFooter returns the footer from the last line of a log file. Logs
+// from runs that were interrupted, or written before footers were
+// introduced, return an error.
+func ReadLogFooter(logFile string) (LogFooter, error) {
+	lines, err := TailLines(logFile, 1)
+	if err != nil {
+		return LogFooter{}, err
+	}
+	if len(lines) == 0 {
+		return LogFooter{}, fmt.Errorf("%s has no log footer", logFile)
+	}
+	footer, err := parseLogFooter(lines[0])
+	if err != nil {
+		return LogFooter{}, fmt.Errorf("%s has no log footer", logFile)
+	}
+	return footer, nil
+}
+
+func parseLogFooter(line string) (LogFooter, error) {
+	var footer LogFooter
+	rest, ok := strings.CutPrefix(line, logFooterPrefix)
+	if !ok {
+		return footer, fmt.Errorf("not a footer line")
+	}
+
+	seen := 0
+	for _, field := range strings.Fields(rest) {
+		key, val, _ := strings.Cut(field, "=")
+		var err error
+		switch key {
+		case "exit_code":
+			footer.ExitCode, err = strconv.Atoi(val)
+		case "duration":
+			footer.Duration, err = strconv.ParseFloat(strings.TrimSuffix(val, "s"), 64)
+		case "finished_at":
+			footer.FinishedAt, err = time.Parse(time.RFC3339, val)
+		default:
+			continue
+		}
+		if err != nil {
+			return footer, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		seen++
+	}
+	if seen != 3 {
+		return footer, fmt.Errorf("incomplete footer")
+	}
+	return footer, nil
+}
@@ -0,0 +1,158 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// TestEvent is one event from go test -json (see "go doc test2json").
+type TestEvent struct {
+	Time    time.Time `json:"Time,omitzero"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package,omitempty"`
+	Test    string    `json:"Test,omitempty"`
+	Output  string    `json:"Output,omitempty"`
+	Elapsed float64   `json:"Elapsed,omitempty"`
+}
+
+// TestFailure is a failed test and the output it produced.
+type TestFailure struct {
+	Package  string `json:"package"`
+	TestName string `json:"test_name"`
+	Output   string `json:"output"`
+}
+
+// ParseGoTestOutput extracts failed tests from plain (non -json) go test
+// output. Each "--- FAIL: TestName (0.12s)" line starts a failure whose
+// output runs until the next "---" or FAIL line; the package is taken from
+// the "FAIL\t<pkg>" summary line that follows. The log header is skipped.
+func ParseGoTestOutput(logFile string) ([]TestFailure, error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var failures []TestFailure
+	pending := 0 // failures at the end of the slice still awaiting a package
+	current := -1
+	var header headerSkipper
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if header.skip(line) || isLogFooter(line) {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if name, ok := strings.CutPrefix(trimmed, "--- FAIL: "); ok {
+			if i := strings.LastIndex(name, " ("); i >= 0 {
+				name = name[:i]
+			}
+			failures = append(failures, TestFailure{TestName: name})
+			current = len(failures) - 1
+			pending++
+			continue
+		}
+		if strings.HasPrefix(trimmed, "---") || trimmed == "FAIL" || strings.HasPrefix(line, "FAIL\t") || strings.HasPrefix(line, "ok  \t") {
+			current = -1
+			if fields := strings.Fields(line); len(fields) >= 2 && (fields[0] == "FAIL" || fields[0] == "ok") {
+				for i := len(failures) - pending; i < len(failures); i++ {
+					failures[i].Package = fields[1]
+				}
+				pending = 0
+			}
+			continue
+		}
+		if current >= 0 {
+			failures[current].Output += line + "\n"
+		}
+	}
+	return failures, scanner.Err()
+}
+
+// TestRepoJSON runs Go tests with -json, logging the event stream to
+// /tmp/orchestrator-test-<repo>.log, and returns the parsed events. The error
+// is non-nil only if the tests could not be run or the log could not be read;
+// test failures are reported through Result.Success and the events.
+func TestRepoJSON(repo config.RepoConfig) (Result, []TestEvent, error) {
+	if repo.Language != "go" {
+		return Result{Repo: repo.Name, ExitCode: 1}, nil, fmt.Errorf("go test -json requires a go repo, %s is %s", repo.Name, repo.Language)
+	}
+
+	result := RunInRepo(repo, "go", []string{"test", "-json", "./...", "-short", "-timeout", "10m"}, "test")
+	if result.FailureKind == FailureMissing {
+		return result, nil, fmt.Errorf("directory %s does not exist", repo.Local)
+	}
+
+	events, err := ParseTestEvents(result.LogFile)
+	return result, events, err
+}
+
+// ParseTestEvents reads go test -json events from a log file. The log header
+// and any non-JSON lines, such as build errors, are skipped.
+func ParseTestEvents(logFile string) ([]TestEvent, error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []TestEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+		var ev TestEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events, scanner.Err()
+}
+
+// FailedTests aggregates the output of each test that has a "fail" event, in
+// the order the failures were reported.
+func FailedTests(events []TestEvent) []TestFailure {
+	type key struct{ pkg, test string }
+	output := make(map[key]*bytes.Buffer)
+	var failed []key
+
+	for _, ev := range events {
+		if ev.Test == "" {
+			continue
+		}
+		k := key{ev.Package, ev.Test}
+		switch ev.Action {
+		case "output":
+			if output[k] == nil {
+				output[k] = &bytes.Buffer{}
+			}
+			output[k].WriteString(ev.Output)
+		case "fail":
+			failed = append(failed, k)
+		}
+	}
+
+	var failures []TestFailure
+	for _, k := range failed {
+		f := TestFailure{Package: k.pkg, TestName: k.test}
+		if buf := output[k]; buf != nil {
+			f.Output = buf.String()
+		}
+		failures = append(failures, f)
+	}
+	return failures
+}
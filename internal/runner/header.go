@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logHeaderDelim opens and closes the front-matter header in a log file.
+const logHeaderDelim = "---"
+
+// LogHeader is the front-matter block RunInRepo writes at the top of each log.
+type LogHeader struct {
+	Repo                string    `json:"repo"`
+	Command             string    `json:"command"`
+	Args                []string  `json:"args"`
+	Dir                 string    `json:"dir"`
+	StartedAt           time.Time `json:"started_at"`
+	OrchestratorVersion string    `json:"orchestrator_version"`
+}
+
+// headerSkipper recognizes the lines of the front-matter header at the top
+// of a log, so readers such as FirstErrorLine see only the command's output.
+type headerSkipper struct {
+	started, inHeader bool
+}
+
+// skip reports whether line, the next line of the log, is part of the
+// header.
+func (h *headerSkipper) skip(line string) bool {
+	if !h.started {
+		h.started = true
+		h.inHeader = line == logHeaderDelim
+		return h.inHeader
+	}
+	if h.inHeader {
+		h.inHeader = line != logHeaderDelim
+		return true
+	}
+	return false
+}
+
+// writeLogHeader writes h as a YAML front-matter block. Strings are written
+// double-quoted and args as a flow sequence, both of which are valid YAML.
+func writeLogHeader(w io.Writer, h LogHeader) error {
+	args, err := json.Marshal(h.Args)
+	if err != nil {
+		return err
+	}
+	if h.Args == nil {
+		args = []byte("[]")
+	}
+	_, err = fmt.Fprintf(w, "%s\nrepo: %q\ncommand: %q\nargs: %s\ndir: %q\nstarted_at: %s\norchestrator_version: %q\n%s\n",
+		logHeaderDelim, h.Repo, h.Command, args, h.Dir, h.StartedAt.Format(time.RFC3339), h.OrchestratorVersion, logHeaderDelim)
+	return err
+}
+
+// ParseLogHeader reads the front-matter header from the top of a log file.
+// Logs written before headers were introduced return an error.
+func ParseLogHeader(logFile string) (LogHeader, error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return LogHeader{}, err
+	}
+	defer f.Close()
+
+	var h LogHeader
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || scanner.Text() != logHeaderDelim {
+		return h, fmt.Errorf("%s has no log header", logFile)
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == logHeaderDelim {
+			return h, nil
+		}
+		key, val, ok := strings.Cut(line, ": ")
+		if !ok {
+			return h, fmt.Errorf("malformed header line %q", line)
+		}
+		switch key {
+		case "repo":
+			h.Repo = unquoteHeader(val)
+		case "command":
+			h.Command = unquoteHeader(val)
+		case "args":
+			if err := json.Unmarshal([]byte(val), &h.Args); err != nil {
+				return h, fmt.Errorf("parsing args: %w", err)
+			}
+		case "dir":
+			h.Dir = unquoteHeader(val)
+		case "started_at":
+			if h.StartedAt, err = time.Parse(time.RFC3339, val); err != nil {
+				return h, fmt.Errorf("parsing started_at: %w", err)
+			}
+		case "orchestrator_version":
+			h.OrchestratorVersion = unquoteHeader(val)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return h, err
+	}
+	return h, fmt.Errorf("%s: unterminated log header", logFile)
+}
+
+func unquoteHeader(val string) string {
+	if s, err := strconv.Unquote(val); err == nil {
+		return s
+	}
+	return val
+}
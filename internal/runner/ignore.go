@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// IgnoreFileName is the file in a repository root listing packages that
+// BuildRepo and TestRepo leave out, such as integration tests that need
+// external services.
+const IgnoreFileName = ".orchestrator-ignore"
+
+// ReadIgnoreFile returns the package paths listed in repoLocal's
+// .orchestrator-ignore, one per line. Blank lines and lines starting with #
+// are skipped. A missing file lists nothing.
+func ReadIgnoreFile(repoLocal string) ([]string, error) {
+	f, err := os.Open(filepath.Join(repoLocal, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	return paths, scanner.Err()
+}
+
+// runGo runs "go <verb> <packages> <flags>" over the repository's packages
+// minus those in its ignore file, recording the excluded import paths.
+func runGo(repo config.RepoConfig, verb string, flags []string, logPrefix string) Result {
+	targets, excluded := goTargets(repo)
+	if len(targets) == 0 {
+		return Result{
+			Repo:             repo.Name,
+			Command:          "go " + verb + " (every package is in " + IgnoreFileName + ")",
+			Success:          true,
+			ExcludedPackages: excluded,
+		}
+	}
+	args := append(append([]string{verb}, targets...), flags...)
+	result := RunInRepo(repo, "go", args, logPrefix)
+	result.ExcludedPackages = excluded
+	return result
+}
+
+// goTargets returns the package arguments for a Go repository: ./... when
+// nothing is ignored, otherwise the packages go list reports minus the
+// ignored ones, which are returned as excluded. An entry matches a package
+// by import path or by directory relative to the repository root, and one
+// ending in /... also matches the packages below it. If the ignore file or
+// go list cannot be read, every package is built and tested and the run
+// itself reports the problem.
+func goTargets(repo config.RepoConfig) (targets, excluded []string) {
+	all := []string{"./..."}
+	ignore, err := ReadIgnoreFile(repo.Local)
+	if err != nil || len(ignore) == 0 {
+		return all, nil
+	}
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}\t{{.Dir}}", "./...")
+	cmd.Dir = repo.Local
+	out, err := cmd.Output()
+	if err != nil {
+		return all, nil
+	}
+
+	targets = []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		importPath, dir, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		rel, err := filepath.Rel(repo.Local, dir)
+		if err != nil {
+			rel = dir
+		}
+		if ignoresPackage(ignore, importPath, filepath.ToSlash(rel)) {
+			excluded = append(excluded, importPath)
+		} else {
+			targets = append(targets, importPath)
+		}
+	}
+	return targets, excluded
+}
+
+// ignoresPackage reports whether any ignore entry matches the package.
+func ignoresPackage(ignore []string, importPath, rel string) bool {
+	for _, entry := range ignore {
+		entry = strings.TrimSuffix(strings.TrimPrefix(entry, "./"), "/")
+		tree := strings.HasSuffix(entry, "/...")
+		entry = strings.TrimSuffix(entry, "/...")
+		for _, p := range []string{importPath, rel} {
+			if p == entry || (tree && strings.HasPrefix(p, entry+"/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// npmTestArgs returns the npm test arguments, passing each ignore file
+// entry on to Jest as a --testPathIgnorePatterns pattern.
+func npmTestArgs(repo config.RepoConfig) (args, excluded []string) {
+	args = []string{"test"}
+	excluded, err := ReadIgnoreFile(repo.Local)
+	if err != nil || len(excluded) == 0 {
+		return args, nil
+	}
+	args = append(args, "--")
+	for _, pattern := range excluded {
+		args = append(args, "--testPathIgnorePatterns="+pattern)
+	}
+	return args, excluded
+}
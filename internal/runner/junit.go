@@ -0,0 +1,179 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// junitCase is the subset of a JUnit XML <testcase> needed to list failures,
+// and the cases WriteJUnitXML writes.
+type junitCase struct {
+	ClassName string      `xml:"classname,attr"`
+	Class     string      `xml:"class,attr,omitempty"`
+	Name      string      `xml:"name,attr"`
+	Time      string      `xml:"time,attr,omitempty"`
+	Failure   *junitIssue `xml:"failure"`
+	Error     *junitIssue `xml:"error"`
+	StdOut    string      `xml:"system-out,omitempty"`
+}
+
+type junitIssue struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Trace   string `xml:",chardata"`
+}
+
+// junitSuites is the <testsuites> root WriteJUnitXML writes.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+// WriteJUnitXML writes results to state/junit.xml; see WriteJUnitXMLFile.
+func WriteJUnitXML(rootPath string, results []Result) error {
+	return WriteJUnitXMLFile(filepath.Join(rootPath, "state", "junit.xml"), results)
+}
+
+// WriteJUnitXMLFile writes results as a JUnit XML report for CI systems to
+// path, creating its directory. Each result is a <testsuite> named for the
+// repository, with a failed <testcase> for each of its FailedTests; results
+// without them get one case for the whole run, named after its command, that
+// fails if the run did.
+func WriteJUnitXMLFile(path string, results []Result) error {
+	report := junitSuites{Name: "orchestrator"}
+	var total float64
+	for _, r := range results {
+		suite := junitSuite{Name: r.Repo, Time: junitSeconds(r.Duration)}
+		if !r.RunAt.IsZero() {
+			suite.Timestamp = r.RunAt.UTC().Format("2006-01-02T15:04:05")
+		}
+		for _, f := range r.FailedTests {
+			suite.Cases = append(suite.Cases, junitCase{
+				ClassName: f.Package,
+				Name:      f.TestName,
+				Failure:   &junitIssue{Message: "test failed", Trace: f.Output},
+			})
+		}
+		if len(r.FailedTests) == 0 {
+			c := junitCase{ClassName: r.Repo, Name: r.Command, Time: suite.Time}
+			if !r.Success {
+				msg := fmt.Sprintf("exit code %d", r.ExitCode)
+				if r.FailureKind != "" {
+					msg += fmt.Sprintf(" (%s)", r.FailureKind)
+				}
+				c.Failure = &junitIssue{Message: msg, Type: string(r.FailureKind), Trace: r.Output}
+				if r.LogFile != "" {
+					c.StdOut = "log: " + r.LogFile
+				}
+			}
+			suite.Cases = append(suite.Cases, c)
+		}
+		suite.Tests = len(suite.Cases)
+		for _, c := range suite.Cases {
+			if c.Failure != nil {
+				suite.Failures++
+			}
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		total += r.Duration
+		report.Suites = append(report.Suites, suite)
+	}
+	report.Time = junitSeconds(total)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), append(data, '\n')...), 0644)
+}
+
+func junitSeconds(d float64) string {
+	return strconv.FormatFloat(d, 'f', 3, 64)
+}
+
+// ParseJUnitXML extracts failed and errored tests from a JUnit-format XML
+// report, such as those written by Maven Surefire and phpunit --log-junit.
+// Test cases are found at any depth, so both a single <testsuite> and
+// nested <testsuites> work. The test's class name is reported as its
+// package, and its output is the failure message and stack trace followed by
+// anything it wrote to stdout.
+func ParseJUnitXML(xmlFile string) ([]TestFailure, error) {
+	f, err := os.Open(xmlFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var failures []TestFailure
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return failures, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", xmlFile, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		var c junitCase
+		if err := dec.DecodeElement(&c, &start); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", xmlFile, err)
+		}
+		if failure, failed := c.failure(); failed {
+			failures = append(failures, failure)
+		}
+	}
+}
+
+// failure returns the TestFailure for a failed or errored case.
+func (c junitCase) failure() (TestFailure, bool) {
+	issue := c.Failure
+	if issue == nil {
+		issue = c.Error
+	}
+	if issue == nil {
+		return TestFailure{}, false
+	}
+	var output []string
+	for _, s := range []string{issue.Message, issue.Trace, c.StdOut} {
+		if s = strings.TrimSpace(s); s != "" {
+			output = append(output, s)
+		}
+	}
+	// PHPUnit also writes the PHP class name, with backslashes, as "class";
+	// prefer it over its dotted "classname".
+	pkg := c.ClassName
+	if c.Class != "" {
+		pkg = c.Class
+	}
+	f := TestFailure{Package: pkg, TestName: c.Name}
+	if len(output) > 0 {
+		f.Output = strings.Join(output, "\n") + "\n"
+	}
+	return f, true
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// LintRepo runs static analysis for a repository based on its language,
+// logging to /tmp/orchestrator-build-lint-<repo>.log.
+//
+// Go repos run go vet and, when it is in PATH, staticcheck. JavaScript repos
+// run "npm run lint" if package.json defines a lint script.
+func LintRepo(repo config.RepoConfig) Result {
+	var steps [][]string
+	switch repo.Language {
+	case "go":
+		steps = append(steps, []string{"go", "vet", "./..."})
+		if _, err := exec.LookPath("staticcheck"); err == nil {
+			steps = append(steps, []string{"staticcheck", "./..."})
+		}
+	case "javascript":
+		if hasNpmScript(repo.Local, "lint") {
+			steps = append(steps, []string{"npm", "run", "lint"})
+		}
+	}
+
+	if len(steps) == 0 {
+		return Result{
+			Repo:    repo.Name,
+			Command: "no linter for language: " + repo.Language,
+			Success: true,
+			RunAt:   time.Now(),
+		}
+	}
+	return runSteps(repo, steps, "build-lint")
+}
+
+// BuildAndLintRepo builds a repository and, if the build succeeds, lints it.
+// A lint failure marks the build result as unsuccessful.
+func BuildAndLintRepo(repo config.RepoConfig) Result {
+	result := BuildRepo(repo)
+	if !result.Success {
+		return result
+	}
+
+	lint := LintRepo(repo)
+	result.LintOutput = lint.LogFile
+	result.Duration += lint.Duration
+	if !lint.Success {
+		result.Success = false
+		result.ExitCode = lint.ExitCode
+	}
+	return result
+}
+
+// runSteps runs each command in turn through runStepsWithOptions, so the
+// log has the same header, footer, and stderr file as other runs. All steps
+// run; the result fails if any step fails.
+func runSteps(repo config.RepoConfig, steps [][]string, logPrefix string) Result {
+	return runStepsWithOptions(repo, steps, logPrefix, RunOptions{})
+}
+
+// hasNpmScript reports whether package.json in dir defines the named script.
+func hasNpmScript(dir, name string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return false
+	}
+	_, ok := pkg.Scripts[name]
+	return ok
+}
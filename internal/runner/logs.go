@@ -0,0 +1,154 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogDir is the directory runner log files are written to.
+const LogDir = "/tmp"
+
+// LogPath returns the log file path for a command type and repository.
+func LogPath(logPrefix, repoName string) string {
+	return filepath.Join(LogDir, fmt.Sprintf("orchestrator-%s-%s.log", logPrefix, repoName))
+}
+
+// LogFile describes a runner log file on disk.
+type LogFile struct {
+	Path    string    `json:"path"`
+	Repo    string    `json:"repo"`
+	Type    string    `json:"type"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	// Stderr is set for the .stderr.log file written beside a run's log.
+	Stderr bool `json:"stderr,omitempty"`
+}
+
+// FindLogs returns runner log files for the given repositories, newest first.
+// Only files named orchestrator-<type>-<repo>.log or
+// orchestrator-<type>-<repo>.stderr.log for a known repo are considered, so
+// worker logs sharing the /tmp prefix are ignored. repo and
+// logType filter the results when non-empty; a logType of "sync" also matches
+// "sync-fetch" and "sync-pull". A nil repoNames returns every
+// orchestrator-*.log file, with Repo and Type left empty.
+func FindLogs(repoNames []string, repo, logType string) ([]LogFile, error) {
+	matches, err := filepath.Glob(filepath.Join(LogDir, "orchestrator-*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Match longest names first so "api-gateway" wins over "gateway".
+	names := append([]string(nil), repoNames...)
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	var logs []LogFile
+	for _, path := range matches {
+		var typ, name string
+		stderr := strings.HasSuffix(path, ".stderr.log")
+		if repoNames != nil {
+			var ok bool
+			if typ, name, ok = parseLogName(filepath.Base(path), names); !ok {
+				continue
+			}
+		}
+		if repo != "" && name != repo {
+			continue
+		}
+		if logType != "" && typ != logType && !strings.HasPrefix(typ, logType+"-") {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		logs = append(logs, LogFile{
+			Path:    path,
+			Repo:    name,
+			Type:    typ,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Stderr:  stderr,
+		})
+	}
+
+	sort.Slice(logs, func(i, j int) bool { return logs[i].ModTime.After(logs[j].ModTime) })
+	return logs, nil
+}
+
+// parseLogName splits orchestrator-<type>-<repo>.log, or the matching
+// .stderr.log, into type and repo.
+func parseLogName(base string, repoNames []string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(base, "orchestrator-")
+	if !ok {
+		return "", "", false
+	}
+	rest, ok = strings.CutSuffix(rest, ".log")
+	if !ok {
+		return "", "", false
+	}
+	rest = strings.TrimSuffix(rest, ".stderr")
+	for _, name := range repoNames {
+		if typ, ok := strings.CutSuffix(rest, "-"+name); ok && typ != "" {
+			return typ, name, true
+		}
+	}
+	return "", "", false
+}
+
+// compilerErrorRe matches a Go compiler diagnostic such as
+// "./main.go:3:2: undefined: foo", which go build writes to stderr.
+var compilerErrorRe = regexp.MustCompile(`^\S+\.go:\d+(:\d+)?: `)
+
+// FirstErrorLine returns the first line of a log that looks like an error,
+// or an empty string if none is found.
+func FirstErrorLine(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var header headerSkipper
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Skip the front-matter header so repo names or args can't match.
+		if header.skip(line) {
+			continue
+		}
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "error") || strings.Contains(lower, "fatal") ||
+			strings.HasPrefix(line, "FAIL") || strings.HasPrefix(line, "--- FAIL") ||
+			compilerErrorRe.MatchString(line) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+// TailLines returns the last n lines of a file. A non-positive n returns all lines.
+func TailLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if n > 0 && len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// runMaven runs mvn in batch mode, or fails with exit code 127 and a hint in
+// the log when Maven is not installed. Some Maven versions exit 0 after a
+// failed build, so the last "BUILD SUCCESS" or "BUILD FAILURE" line in the
+// log decides the outcome; the exit code is used only when neither appears.
+func runMaven(repo config.RepoConfig, args []string, logPrefix string) Result {
+	args = append([]string{"-B"}, args...)
+	if _, err := exec.LookPath("mvn"); err != nil {
+		return missingTool(repo, "mvn", args, logPrefix, "install Maven (https://maven.apache.org/install.html)")
+	}
+	result := RunInRepo(repo, "mvn", args, logPrefix)
+	if result.FailureKind == FailureMissing || result.FailureKind == FailureTimeout {
+		return result
+	}
+
+	success, found := mavenOutcome(result.LogFile)
+	switch {
+	case !found || success == result.Success:
+	case success:
+		result.Success, result.ExitCode, result.FailureKind = true, 0, ""
+	default:
+		result.Success = false
+		if result.ExitCode == 0 {
+			result.ExitCode = 1
+		}
+		result.FailureKind = ClassifyFailure(result)
+	}
+	return result
+}
+
+// mavenOutcome reports the result of the last BUILD SUCCESS or BUILD FAILURE
+// line in a Maven log, and whether there was one.
+func mavenOutcome(logFile string) (success, found bool) {
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		return false, false
+	}
+	ok := strings.LastIndex(string(data), "BUILD SUCCESS")
+	failed := strings.LastIndex(string(data), "BUILD FAILURE")
+	if ok < 0 && failed < 0 {
+		return false, false
+	}
+	return ok > failed, true
+}
+
+// surefireDirs returns the Surefire report directories of a repo's root
+// project and its top-level modules.
+func surefireDirs(repo config.RepoConfig) []string {
+	dirs, _ := filepath.Glob(filepath.Join(repo.Local, "*", "target", "surefire-reports"))
+	return append([]string{filepath.Join(repo.Local, "target", "surefire-reports")}, dirs...)
+}
+
+// ParseSurefireDir extracts failed and errored tests from the TEST-*.xml
+// reports in a Surefire report directory, in file name order, as
+// ParseJUnitXML does for each report. A missing directory yields no
+// failures.
+func ParseSurefireDir(dir string) ([]TestFailure, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "TEST-*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var failures []TestFailure
+	for _, file := range files {
+		found, err := ParseJUnitXML(file)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, found...)
+	}
+	return failures, nil
+}
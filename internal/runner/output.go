@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// DefaultOutputLines is how many trailing lines of output RunInRepo keeps in
+// Result.Output.
+const DefaultOutputLines = 50
+
+// maxOutputLineBytes caps each line kept in a lineRing, so a command that
+// never prints a newline cannot grow it without bound.
+const maxOutputLineBytes = 4096
+
+func outputLines(opts RunOptions) int {
+	if opts.MaxOutputLines == 0 {
+		return DefaultOutputLines
+	}
+	return opts.MaxOutputLines
+}
+
+// lineRing is an io.Writer that keeps only the last n lines written to it.
+// It is safe for concurrent writers, so stdout and stderr can share one.
+type lineRing struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial []byte
+}
+
+func newLineRing(n int) *lineRing {
+	return &lineRing{lines: make([]string, n)}
+}
+
+func (r *lineRing) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for rest := b; len(rest) > 0; {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			r.appendPartial(rest)
+			break
+		}
+		r.appendPartial(rest[:i])
+		r.push(string(r.partial))
+		r.partial = r.partial[:0]
+		rest = rest[i+1:]
+	}
+	return len(b), nil
+}
+
+func (r *lineRing) appendPartial(b []byte) {
+	if room := maxOutputLineBytes - len(r.partial); room < len(b) {
+		b = b[:max(room, 0)]
+	}
+	r.partial = append(r.partial, b...)
+}
+
+func (r *lineRing) push(line string) {
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// String returns the kept lines, oldest first, each ending in a newline. An
+// unterminated last line is included.
+func (r *lineRing) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var lines []string
+	if r.full {
+		lines = append(lines, r.lines[r.next:]...)
+	}
+	lines = append(lines, r.lines[:r.next]...)
+	if len(r.partial) > 0 {
+		lines = append(lines, string(r.partial))
+		if len(lines) > len(r.lines) {
+			lines = lines[1:]
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
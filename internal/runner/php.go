@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// PHPUnitXMLPath returns the path phpunit writes its JUnit report to for a
+// repository.
+func PHPUnitXMLPath(repoName string) string {
+	return filepath.Join(LogDir, fmt.Sprintf("orchestrator-phpunit-%s.xml", repoName))
+}
+
+// buildPHP installs a PHP repository's Composer dependencies, or fails with
+// exit code 127 and a hint in the log when Composer is not installed.
+func buildPHP(repo config.RepoConfig) Result {
+	args := []string{"install", "--no-interaction"}
+	if _, err := exec.LookPath("composer"); err != nil {
+		return missingTool(repo, "composer", args, "build", "install Composer (https://getcomposer.org/download/)")
+	}
+	return RunInRepo(repo, "composer", args, "build")
+}
+
+// testPHP runs the repository's own PHPUnit, installed by Composer into
+// vendor/bin, with a JUnit report. A report left by an earlier run is removed
+// first, so ArtifactFile is only set for a fresh report.
+func testPHP(repo config.RepoConfig) Result {
+	report := PHPUnitXMLPath(repo.Name)
+	os.Remove(report)
+	result := RunInRepo(repo, "./vendor/bin/phpunit", []string{"--colors=never", "--log-junit", report}, "test")
+	if _, err := os.Stat(report); err == nil {
+		result.ArtifactFile = report
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// RaceReport is one "WARNING: DATA RACE" report from the Go race detector.
+type RaceReport struct {
+	// Goroutine1 made the access the race was detected on and Goroutine2
+	// the earlier conflicting one, as the report names them: "goroutine 7"
+	// or "main goroutine".
+	Goroutine1 string `json:"goroutine1"`
+	Goroutine2 string `json:"goroutine2"`
+	// Location is the file:line of the innermost frame of Goroutine1's access.
+	Location string `json:"location"`
+}
+
+// raceAccess matches the access lines of a race report, such as
+// "Previous write at 0x00c00001c0f8 by goroutine 7:".
+var raceAccess = regexp.MustCompile(`^(?:Previous )?(?:[Rr]ead|[Ww]rite)(?: at 0x[0-9a-f]+)? by (.+):$`)
+
+// TestRepoRace runs Go tests with the race detector, logging to
+// /tmp/orchestrator-race-<repo>.log. RaceConditions lists the reported races
+// and, on failure, FailedTests the failing tests.
+func TestRepoRace(repo config.RepoConfig) Result {
+	if repo.Language != "go" {
+		return Result{
+			Repo:     repo.Name,
+			Command:  "race detector not supported for language: " + repo.Language,
+			ExitCode: 1,
+			RunAt:    time.Now(),
+		}
+	}
+
+	result := RunInRepo(repo, "go", []string{"test", "-race", "./...", "-timeout", "15m"}, "race")
+	if result.Success || result.LogFile == "" {
+		return result
+	}
+	result.RaceConditions, _ = ParseRaceOutput(result.LogFile)
+	result.FailedTests, _ = ParseGoTestOutput(result.LogFile)
+	return result
+}
+
+// ParseRaceOutput extracts the data race reports from go test -race output.
+func ParseRaceOutput(logFile string) ([]RaceReport, error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reports []RaceReport
+	var current *RaceReport
+	accesses := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "WARNING: DATA RACE":
+			reports = append(reports, RaceReport{})
+			current, accesses = &reports[len(reports)-1], 0
+		case current == nil:
+		case strings.HasPrefix(trimmed, "=================="):
+			current = nil
+		case raceAccess.MatchString(trimmed):
+			who := raceAccess.FindStringSubmatch(trimmed)[1]
+			if accesses++; accesses == 1 {
+				current.Goroutine1 = who
+			} else if accesses == 2 {
+				current.Goroutine2 = who
+			}
+		case accesses == 1 && current.Location == "" && strings.Contains(trimmed, ".go:"):
+			current.Location = strings.Fields(trimmed)[0]
+		}
+	}
+	return reports, scanner.Err()
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Orchestrator Test Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 4px 12px; border: 1px solid #ccc; text-align: left; }
+tr.pass { background: #e6ffed; }
+tr.fail { background: #ffeef0; }
+</style>
+</head>
+<body>
+<h1>Orchestrator Test Report</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}: {{.Passed}} passed, {{.Failed}} failed (total: {{len .Results}})</p>
+<table>
+<tr><th>Repo</th><th>Status</th><th>Duration</th><th>Log</th></tr>
+{{- range .Results}}
+<tr class="{{if .Success}}pass{{else}}fail{{end}}">
+<td>{{.Repo}}</td>
+<td>{{if .Success}}PASS{{else}}FAIL{{end}}</td>
+<td>{{printf "%.1fs" .Duration}}</td>
+<td>{{if .LogFile}}<a href="file://{{.LogFile}}">{{.LogFile}}</a>{{end}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTMLReport renders results as state/test-report.html.
+func WriteHTMLReport(rootPath string, results []Result) error {
+	stateDir := filepath.Join(rootPath, "state")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+
+	data := struct {
+		Generated      time.Time
+		Results        []Result
+		Passed, Failed int
+	}{Generated: time.Now(), Results: results}
+	for _, r := range results {
+		if r.Success {
+			data.Passed++
+		} else {
+			data.Failed++
+		}
+	}
+
+	f, err := os.Create(filepath.Join(stateDir, "test-report.html"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return reportTemplate.Execute(f, data)
+}
@@ -1,79 +1,368 @@
 package runner
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/version"
 )
 
 // Result captures the outcome of running a command in a repository.
 type Result struct {
-	Repo     string    `json:"repo"`
-	Command  string    `json:"command"`
-	LogFile  string    `json:"log_file"`
-	ExitCode int       `json:"exit_code"`
-	Success  bool      `json:"success"`
-	Duration float64   `json:"duration_seconds"`
-	RunAt    time.Time `json:"run_at"`
+	Repo        string        `json:"repo"`
+	Command     string        `json:"command"`
+	LogFile     string        `json:"log_file"`
+	StderrFile  string        `json:"stderr_file,omitempty"`
+	LintOutput  string        `json:"lint_output,omitempty"`
+	ExitCode    int           `json:"exit_code"`
+	FailureKind FailureKind   `json:"failure_kind,omitempty"`
+	FailedTests []TestFailure `json:"failed_tests,omitempty"`
+	Success     bool          `json:"success"`
+	Duration    float64       `json:"duration_seconds"`
+	RunAt       time.Time     `json:"run_at"`
+	// ArtifactFile is a report the command wrote beside its log, such as the
+	// TRX file from dotnet test.
+	ArtifactFile string `json:"artifact_file,omitempty"`
+	// FailedCount is the number of failing tests reported by runners that
+	// only print a summary, such as mix test.
+	FailedCount int `json:"failed_count,omitempty"`
+	// Output holds the last lines the command wrote to stdout and stderr,
+	// interleaved as they arrived; see RunOptions.MaxOutputLines.
+	Output string `json:"output,omitempty"`
+	// RaceConditions lists the data races reported by TestRepoRace.
+	RaceConditions []RaceReport `json:"race_conditions,omitempty"`
+	// TestsPassed and TestsFailed are the test counts for runners whose
+	// output reports them, such as swift test.
+	TestsPassed int `json:"tests_passed,omitempty"`
+	TestsFailed int `json:"tests_failed,omitempty"`
+	// ExcludedPackages lists what the repository's .orchestrator-ignore left
+	// out of a default Go build or test, or an npm test; see ReadIgnoreFile.
+	ExcludedPackages []string `json:"excluded_packages,omitempty"`
+	// OrchestratorVersion is the version of the binary that saved the result
+	// to a state file; see WriteResults.
+	OrchestratorVersion string `json:"orchestrator_version,omitempty"`
+	// LogChecksum is the hex-encoded SHA-256 of LogFile once the command
+	// exited and its footer was written; see VerifyLogChecksum.
+	LogChecksum string `json:"log_checksum,omitempty"`
+	// Error describes why the command could not be run, such as a log file
+	// that could not be created or written.
+	Error string `json:"error,omitempty"`
 }
 
-// RunInRepo executes a command in a repository directory, capturing output to a log file.
+// RunOptions customizes how RunInRepoWithOptions starts a command.
+type RunOptions struct {
+	// Env holds KEY=value entries merged over os.Environ(); later entries
+	// win. Values are not written to the log header.
+	Env []string
+	// Stdin is connected to the command's standard input. If nil, the
+	// command reads from os.DevNull and sees EOF at once, so a command that
+	// prompts fails instead of hanging.
+	Stdin io.Reader
+	// Timeout kills the command after the given duration if non-zero.
+	Timeout time.Duration
+	// Verbose also copies the command's stdout and stderr to os.Stderr, each
+	// line prefixed with "[repo] ". Setting ORCHESTRATOR_VERBOSE=1 turns it
+	// on for every run.
+	Verbose bool
+	// MaxOutputLines is how many trailing lines of output to keep in
+	// Result.Output. Zero means DefaultOutputLines; a negative value keeps
+	// none.
+	MaxOutputLines int
+}
+
+// RunInRepo executes a command in a repository directory. Stdout is captured
+// to LogFile, between a front-matter header describing the run (see
+// ParseLogHeader) and a footer line recording its outcome (see
+// ReadLogFooter), and stderr to a separate StderrFile alongside it. The tail
+// of both is also kept in Output. Stdin is os.DevNull; see RunWithStdin.
 func RunInRepo(repo config.RepoConfig, command string, args []string, logPrefix string) Result {
-	logFile := fmt.Sprintf("/tmp/orchestrator-%s-%s.log", logPrefix, repo.Name)
+	return RunInRepoWithOptions(repo, command, args, logPrefix, RunOptions{})
+}
+
+// RunWithStdin is RunInRepo with input supplied on the command's standard
+// input, for commands that prompt.
+func RunWithStdin(repo config.RepoConfig, command string, args []string, logPrefix string, input string) Result {
+	return RunInRepoWithOptions(repo, command, args, logPrefix, RunOptions{Stdin: strings.NewReader(input)})
+}
+
+// RunInRepoWithOptions is RunInRepo with a custom environment, stdin,
+// timeout, or verbose output. A command killed by the timeout fails with
+// FailureTimeout.
+func RunInRepoWithOptions(repo config.RepoConfig, command string, args []string, logPrefix string, opts RunOptions) Result {
+	return runStepsWithOptions(repo, [][]string{append([]string{command}, args...)}, logPrefix, opts)
+}
 
+// runStepsWithOptions runs each command in turn, sharing one log file and
+// one stderr file, as RunInRepoWithOptions does for a single command. All
+// steps run; the result fails with the exit code of the first step that
+// fails. The timeout covers all
+// steps together. With more than one step, the header records the joined
+// command line and each step's output starts with a "$ command" line.
+func runStepsWithOptions(repo config.RepoConfig, steps [][]string, logPrefix string, opts RunOptions) Result {
+	logFile := LogPath(logPrefix, repo.Name)
+
+	var commands []string
+	for _, s := range steps {
+		commands = append(commands, fmt.Sprintf("%s %s", s[0], joinArgs(s[1:])))
+	}
 	result := Result{
-		Repo:    repo.Name,
-		Command: fmt.Sprintf("%s %s", command, joinArgs(args)),
-		LogFile: logFile,
-		RunAt:   time.Now(),
+		Repo:       repo.Name,
+		Command:    strings.Join(commands, " && "),
+		LogFile:    logFile,
+		StderrFile: StderrPath(logFile),
+		RunAt:      time.Now(),
 	}
 
 	if _, err := os.Stat(repo.Local); os.IsNotExist(err) {
 		result.ExitCode = 1
+		result.FailureKind = FailureMissing
 		os.WriteFile(logFile, []byte(fmt.Sprintf("ERROR: directory %s does not exist\n", repo.Local)), 0644)
+		result.LogChecksum, _ = fileChecksum(logFile)
 		return result
 	}
 
 	f, err := os.Create(logFile)
 	if err != nil {
 		result.ExitCode = 1
+		result.Error = err.Error()
 		return result
 	}
 	defer f.Close()
 
-	cmd := exec.Command(command, args...)
-	cmd.Dir = repo.Local
-	cmd.Stdout = f
-	cmd.Stderr = f
+	ef, err := os.Create(result.StderrFile)
+	if err != nil {
+		result.ExitCode = 1
+		result.Error = err.Error()
+		return result
+	}
+	defer ef.Close()
+
+	header := LogHeader{
+		Repo:                repo.Name,
+		Command:             steps[0][0],
+		Args:                steps[0][1:],
+		Dir:                 repo.Local,
+		StartedAt:           result.RunAt,
+		OrchestratorVersion: version.Version,
+	}
+	if len(steps) > 1 {
+		header.Command, header.Args = result.Command, nil
+	}
+	if err := writeLogHeader(f, header); err != nil {
+		result.ExitCode = 1
+		result.Error = fmt.Sprintf("writing log header: %v", err)
+		return result
+	}
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	stdout, stderr := io.Writer(f), io.Writer(ef)
+	if verbose(opts) {
+		outTee, errTee := newPrefixWriter(os.Stderr, repo.Name), newPrefixWriter(os.Stderr, repo.Name)
+		defer outTee.Flush()
+		defer errTee.Flush()
+		stdout = io.MultiWriter(stdout, outTee)
+		stderr = io.MultiWriter(stderr, errTee)
+	}
+	var ring *lineRing
+	if n := outputLines(opts); n > 0 {
+		ring = newLineRing(n)
+		stdout = io.MultiWriter(stdout, ring)
+		stderr = io.MultiWriter(stderr, ring)
+	}
 
 	start := time.Now()
-	err = cmd.Run()
+	var firstErr error
+	for i, s := range steps {
+		if len(steps) > 1 {
+			fmt.Fprintf(f, "$ %s\n", commands[i])
+		}
+		err := runStep(ctx, repo.Local, s, stdout, stderr, opts)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
 	result.Duration = time.Since(start).Seconds()
+	if ring != nil {
+		result.Output = ring.String()
+	}
 
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
+	if firstErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(firstErr, &exitErr) {
 			result.ExitCode = exitErr.ExitCode()
 		} else {
 			result.ExitCode = 1
 		}
+		if ctx.Err() == context.DeadlineExceeded {
+			fmt.Fprintf(ef, "ERROR: killed after timeout of %s\n", opts.Timeout)
+			result.FailureKind = FailureTimeout
+		} else {
+			if exitErr == nil {
+				// The command never started, e.g. it is not in PATH.
+				fmt.Fprintf(ef, "ERROR: %v\n", firstErr)
+			}
+			result.FailureKind = ClassifyFailure(result)
+		}
 	} else {
 		result.Success = true
 	}
 
+	writeLogFooter(f, LogFooter{
+		ExitCode:   result.ExitCode,
+		Duration:   result.Duration,
+		FinishedAt: time.Now(),
+	})
+	result.LogChecksum, _ = fileChecksum(logFile)
+
 	return result
 }
 
-// BuildRepo builds a repository based on its language.
+// runStep runs one command in dir with the given output writers.
+func runStep(ctx context.Context, dir string, step []string, stdout, stderr io.Writer, opts RunOptions) error {
+	cmd := exec.CommandContext(ctx, step[0], step[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Output is copied through pipes, so don't let a background child that
+	// inherited them hold Run open past a timeout.
+	cmd.WaitDelay = time.Second
+	if len(opts.Env) > 0 {
+		cmd.Env = mergeEnv(os.Environ(), opts.Env)
+	}
+
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		// The command itself exited 0; only a lingering child was cut off.
+		err = nil
+	}
+	return err
+}
+
+// mergeEnv returns base with each KEY=value in overrides applied in order,
+// replacing any earlier entry for the same key.
+func mergeEnv(base, overrides []string) []string {
+	index := map[string]int{}
+	var env []string
+	for _, kv := range append(append([]string{}, base...), overrides...) {
+		key, _, _ := strings.Cut(kv, "=")
+		if i, ok := index[key]; ok {
+			env[i] = kv
+			continue
+		}
+		index[key] = len(env)
+		env = append(env, kv)
+	}
+	return env
+}
+
+// StderrPath returns the stderr log path paired with a stdout log file.
+func StderrPath(logFile string) string {
+	return strings.TrimSuffix(logFile, ".log") + ".stderr.log"
+}
+
+// LastStderrLines returns the last n lines written to a result's stderr file.
+func LastStderrLines(result Result, n int) ([]string, error) {
+	if result.StderrFile == "" {
+		return nil, fmt.Errorf("result for %s has no stderr file", result.Repo)
+	}
+	return TailLines(result.StderrFile, n)
+}
+
+// makeProbeGoal is the only goal hasMakeTarget asks make to build. Its
+// rule, read from stdin, has an empty recipe, so no recipe in the
+// repository's Makefile runs, not even "+" lines.
+const makeProbeGoal = ".orchestrator-probe"
+
+// makeProbeTimeout bounds how long reading a Makefile may take, since
+// $(shell ...) calls run while it is parsed.
+const makeProbeTimeout = 10 * time.Second
+
+// hasMakeTarget reports whether the repository has a Makefile with an
+// explicit rule for target. The rule is looked up in make's database
+// (make -p), so a file or directory named target, such as build/, does not
+// count.
+func hasMakeTarget(repo config.RepoConfig, target string) bool {
+	if _, err := os.Stat(filepath.Join(repo.Local, "Makefile")); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), makeProbeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "make", "-pq", "-f", "Makefile", "-f", "-", makeProbeGoal)
+	cmd.Dir = repo.Local
+	cmd.Stdin = strings.NewReader(makeProbeGoal + ": ;\n")
+	out, _ := cmd.Output()
+	if ctx.Err() != nil {
+		return false
+	}
+	return makeDatabaseHasRule(string(out), target)
+}
+
+// makeDatabaseHasRule reports whether the "# Files" section of make -p
+// output has a rule for target that is not marked "# Not a target:".
+func makeDatabaseHasRule(db, target string) bool {
+	inFiles, notTarget := false, false
+	for _, line := range strings.Split(db, "\n") {
+		switch {
+		case line == "# Files":
+			inFiles = true
+		case !inFiles:
+		case line == "# Not a target:":
+			notTarget = true
+		case line == "":
+			notTarget = false
+		case strings.HasPrefix(line, target+":") && !notTarget:
+			return true
+		}
+	}
+	return false
+}
+
+// BuildRepo builds a repository. BuildCmd is used if set; otherwise a
+// Makefile "build" target is preferred, and the language default is the
+// fallback. Repositories with Language "make" always run make build. The Go
+// default skips the packages listed in .orchestrator-ignore.
 func BuildRepo(repo config.RepoConfig) Result {
+	if len(repo.BuildCmd) > 0 {
+		return RunInRepo(repo, repo.BuildCmd[0], repo.BuildCmd[1:], "build")
+	}
+	if repo.Language == "make" || hasMakeTarget(repo, "build") {
+		return RunInRepo(repo, "make", []string{"build"}, "build")
+	}
+
 	switch repo.Language {
 	case "go":
-		return RunInRepo(repo, "go", []string{"build", "./..."}, "build")
+		return runGo(repo, "build", nil, "build")
 	case "javascript":
 		return RunInRepo(repo, "npm", []string{"run", "build"}, "build")
+	case "dotnet":
+		return RunInRepo(repo, "dotnet", []string{"build", "--nologo", "-c", "Release"}, "build")
+	case "elixir":
+		return runMix(repo, []string{"compile", "--warnings-as-errors"}, "build")
+	case "java", "maven":
+		return runMaven(repo, []string{"package", "-DskipTests"}, "build")
+	case "php":
+		return buildPHP(repo)
+	case "swift":
+		return runSwift(repo, []string{"build", "-c", "release"}, "build")
 	default:
 		return Result{
 			Repo:     repo.Name,
@@ -83,13 +372,72 @@ func BuildRepo(repo config.RepoConfig) Result {
 	}
 }
 
-// TestRepo runs tests for a repository based on its language.
+// TestRepo runs tests for a repository, choosing the command the same way as
+// BuildRepo: TestCmd, then a Makefile "test" target, then the language default.
+// The Go and npm defaults leave out what .orchestrator-ignore lists, and
+// report it in ExcludedPackages.
+// For failed Go repos, FailedTests lists the failing tests found in the log;
+// for failed dotnet repos, those found in the TRX report in ArtifactFile.
+// Elixir repos report only FailedCount, from the mix test summary. Java
+// repos list the failures in their Surefire reports, and PHP repos those in
+// the PHPUnit JUnit report in ArtifactFile. Swift repos, passing or not, set
+// TestsPassed and TestsFailed from the XCTest summary in the log or stderr.
 func TestRepo(repo config.RepoConfig) Result {
+	result := testRepo(repo)
+	if repo.Language == "swift" && result.ExitCode != 127 {
+		// XCTest reports to stderr on macOS and to stdout elsewhere.
+		for _, file := range []string{result.LogFile, result.StderrFile} {
+			if passed, failed, err := ParseSwiftTestOutput(file); err == nil {
+				result.TestsPassed += passed
+				result.TestsFailed += failed
+			}
+		}
+	}
+	switch {
+	case result.Success:
+	case repo.Language == "go" && result.LogFile != "":
+		result.FailedTests, _ = ParseGoTestOutput(result.LogFile)
+	case repo.Language == "dotnet" && result.ArtifactFile != "":
+		result.FailedTests, _ = ParseTRXResults(result.ArtifactFile)
+	case repo.Language == "elixir" && result.ExitCode != 127:
+		_, result.FailedCount, _ = ParseMixTestSummary(result.LogFile)
+	case (repo.Language == "java" || repo.Language == "maven") && result.ExitCode != 127:
+		for _, dir := range surefireDirs(repo) {
+			failures, _ := ParseSurefireDir(dir)
+			result.FailedTests = append(result.FailedTests, failures...)
+		}
+	case repo.Language == "php" && result.ArtifactFile != "":
+		result.FailedTests, _ = ParseJUnitXML(result.ArtifactFile)
+	}
+	return result
+}
+
+func testRepo(repo config.RepoConfig) Result {
+	if len(repo.TestCmd) > 0 {
+		return RunInRepo(repo, repo.TestCmd[0], repo.TestCmd[1:], "test")
+	}
+	if repo.Language == "make" || hasMakeTarget(repo, "test") {
+		return RunInRepo(repo, "make", []string{"test"}, "test")
+	}
+
 	switch repo.Language {
 	case "go":
-		return RunInRepo(repo, "go", []string{"test", "./...", "-short", "-timeout", "10m"}, "test")
+		return runGo(repo, "test", []string{"-short", "-timeout", "10m"}, "test")
 	case "javascript":
-		return RunInRepo(repo, "npm", []string{"test"}, "test")
+		args, excluded := npmTestArgs(repo)
+		result := RunInRepo(repo, "npm", args, "test")
+		result.ExcludedPackages = excluded
+		return result
+	case "dotnet":
+		return testDotnet(repo)
+	case "elixir":
+		return runMix(repo, []string{"test", "--formatter", "ExUnit.CLIFormatter"}, "test")
+	case "java", "maven":
+		return runMaven(repo, []string{"test"}, "test")
+	case "php":
+		return testPHP(repo)
+	case "swift":
+		return runSwift(repo, []string{"test", "--parallel"}, "test")
 	default:
 		return Result{
 			Repo:     repo.Name,
@@ -99,14 +447,38 @@ func TestRepo(repo config.RepoConfig) Result {
 	}
 }
 
-// WriteResults writes a results file to the state directory.
+// WriteResults writes results as JSON to a file in the state directory.
+// Results without an OrchestratorVersion are stamped with this binary's.
 func WriteResults(rootPath string, filename string, results []Result) error {
 	stateDir := filepath.Join(rootPath, "state")
 	if err := os.MkdirAll(stateDir, 0755); err != nil {
 		return err
 	}
 
-	// Simple text format for easy reading
+	stamped := make([]Result, len(results))
+	for i, r := range results {
+		if r.OrchestratorVersion == "" {
+			r.OrchestratorVersion = version.Version
+		}
+		stamped[i] = r
+	}
+	results = stamped
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(stateDir, filename), data, 0644)
+}
+
+// WriteResultsText writes a human-readable summary of results to the state
+// directory, one line per result.
+func WriteResultsText(rootPath string, filename string, results []Result) error {
+	stateDir := filepath.Join(rootPath, "state")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+
 	f, err := os.Create(filepath.Join(stateDir, filename))
 	if err != nil {
 		return err
@@ -124,6 +496,55 @@ func WriteResults(rootPath string, filename string, results []Result) error {
 	return nil
 }
 
+// ReadResults loads results previously written by WriteResults, warning on
+// stderr if any was written by a different major version of the
+// orchestrator.
+func ReadResults(rootPath string, filename string) ([]Result, error) {
+	data, err := os.ReadFile(filepath.Join(rootPath, "state", filename))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	for _, r := range results {
+		if version.WarnIfIncompatible(filename, r.OrchestratorVersion) {
+			break
+		}
+	}
+	return results, nil
+}
+
+// AppendResults merges results into a JSON file previously written by
+// WriteResults, creating it if it does not exist. Results are deduplicated
+// by Repo, keeping the one with the latest RunAt; existing entries keep
+// their position and new repositories are added at the end.
+func AppendResults(rootPath string, filename string, results []Result) error {
+	merged, err := ReadResults(rootPath, filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	index := make(map[string]int, len(merged))
+	for i, r := range merged {
+		index[r.Repo] = i
+	}
+	for _, r := range results {
+		i, ok := index[r.Repo]
+		if !ok {
+			index[r.Repo] = len(merged)
+			merged = append(merged, r)
+			continue
+		}
+		if !r.RunAt.Before(merged[i].RunAt) {
+			merged[i] = r
+		}
+	}
+	return WriteResults(rootPath, filename, merged)
+}
+
 func joinArgs(args []string) string {
 	s := ""
 	for i, a := range args {
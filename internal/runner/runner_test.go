@@ -0,0 +1,1109 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+func TestWriteResults_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	results := []Result{
+		{Repo: "alpha", Command: "go test ./...", Success: true, Duration: 1.5},
+		{Repo: "beta", Command: "npm test", ExitCode: 1, Duration: 2.0},
+	}
+
+	if err := WriteResults(root, "test-results.json", results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	loaded, err := ReadResults(root, "test-results.json")
+	if err != nil {
+		t.Fatalf("ReadResults: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(loaded))
+	}
+	if loaded[0].Repo != "alpha" || !loaded[0].Success {
+		t.Errorf("unexpected first result: %+v", loaded[0])
+	}
+	if loaded[1].Repo != "beta" || loaded[1].ExitCode != 1 {
+		t.Errorf("unexpected second result: %+v", loaded[1])
+	}
+}
+
+func TestAppendResults(t *testing.T) {
+	root := t.TempDir()
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// A missing file is created.
+	if err := AppendResults(root, "test-results.json", []Result{
+		{Repo: "alpha", RunAt: t0},
+		{Repo: "beta", RunAt: t0},
+	}); err != nil {
+		t.Fatalf("AppendResults: %v", err)
+	}
+
+	if err := AppendResults(root, "test-results.json", []Result{
+		{Repo: "gamma", RunAt: t0},
+		{Repo: "beta", Success: true, RunAt: t0.Add(time.Minute)},
+		{Repo: "alpha", Success: true, RunAt: t0.Add(-time.Minute)},
+	}); err != nil {
+		t.Fatalf("AppendResults: %v", err)
+	}
+
+	loaded, err := ReadResults(root, "test-results.json")
+	if err != nil {
+		t.Fatalf("ReadResults: %v", err)
+	}
+	var got []string
+	for _, r := range loaded {
+		got = append(got, fmt.Sprintf("%s:%v", r.Repo, r.Success))
+	}
+	// beta is replaced by its newer run; the older alpha run is dropped.
+	if want := "alpha:false beta:true gamma:false"; strings.Join(got, " ") != want {
+		t.Errorf("merged results = %v, want %s", got, want)
+	}
+
+	os.WriteFile(filepath.Join(root, "state", "bad.json"), []byte("not json"), 0644)
+	if err := AppendResults(root, "bad.json", []Result{{Repo: "alpha"}}); err == nil {
+		t.Error("expected an error for a corrupt results file")
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	root := t.TempDir()
+	results := []Result{
+		{Repo: "alpha", Success: true, LogFile: "/tmp/orchestrator-test-alpha.log"},
+		{Repo: "<beta>", Success: false},
+	}
+
+	if err := WriteHTMLReport(root, results); err != nil {
+		t.Fatalf("WriteHTMLReport: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "state", "test-report.html"))
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		`<tr class="pass">`,
+		`<tr class="fail">`,
+		"1 passed, 1 failed",
+		"/tmp/orchestrator-test-alpha.log",
+		"&lt;beta&gt;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
+
+func TestParseLogName(t *testing.T) {
+	names := []string{"api-gateway", "gateway", "staking"}
+
+	tests := []struct {
+		base     string
+		wantType string
+		wantRepo string
+		ok       bool
+	}{
+		{"orchestrator-build-staking.log", "build", "staking", true},
+		{"orchestrator-sync-fetch-staking.log", "sync-fetch", "staking", true},
+		{"orchestrator-test-api-gateway.log", "test", "api-gateway", true},
+		{"orchestrator-test-gateway.log", "test", "gateway", true},
+		{"orchestrator-build-staking.stderr.log", "build", "staking", true},
+		{"orchestrator-owner-repo-epic1-issue2-worker3.log", "", "", false},
+		{"orchestrator-staking.log", "", "", false},
+		{"other-build-staking.log", "", "", false},
+	}
+	for _, tt := range tests {
+		typ, repo, ok := parseLogName(tt.base, names)
+		if ok != tt.ok || typ != tt.wantType || repo != tt.wantRepo {
+			t.Errorf("parseLogName(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				tt.base, typ, repo, ok, tt.wantType, tt.wantRepo, tt.ok)
+		}
+	}
+}
+
+func TestFirstErrorLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.log")
+	os.WriteFile(path, []byte("ok line\n./main.go:3: undefined: foo (error)\nmore\n"), 0644)
+
+	if got := FirstErrorLine(path); got != "./main.go:3: undefined: foo (error)" {
+		t.Errorf("unexpected first error line %q", got)
+	}
+
+	os.WriteFile(path, []byte("# example.com/x\n./main.go:3:2: undefined: foo\n"), 0644)
+	if got := FirstErrorLine(path); got != "./main.go:3:2: undefined: foo" {
+		t.Errorf("expected the compiler diagnostic, got %q", got)
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	got := mergeEnv([]string{"A=1", "B=2"}, []string{"B=3", "C=4", "A=5"})
+	want := []string{"A=5", "B=3", "C=4"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("mergeEnv = %v, want %v", got, want)
+	}
+}
+
+func TestRunInRepoWithOptions(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-options", Local: t.TempDir()}
+	t.Setenv("RUNNER_TEST_INHERITED", "parent")
+	result := RunInRepoWithOptions(repo, "sh", []string{"-c", `read line; echo "$line $RUNNER_TEST_INHERITED $CGO_ENABLED"`}, "test", RunOptions{
+		Env:   []string{"CGO_ENABLED=1", "CGO_ENABLED=0"},
+		Stdin: strings.NewReader("input\n"),
+	})
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	if !result.Success {
+		t.Fatalf("expected success, got exit %d", result.ExitCode)
+	}
+	stdout, _ := os.ReadFile(result.LogFile)
+	if !strings.Contains(string(stdout), "---\ninput parent 0\n"+logFooterPrefix) {
+		t.Errorf("unexpected stdout log %q", stdout)
+	}
+
+	result = RunInRepoWithOptions(repo, "sleep", []string{"5"}, "test", RunOptions{Timeout: 50 * time.Millisecond})
+	if result.Success || result.FailureKind != FailureTimeout {
+		t.Errorf("expected timeout failure, got %+v", result)
+	}
+}
+
+func TestRunWithStdin(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-stdin", Local: t.TempDir()}
+	result := RunWithStdin(repo, "sh", []string{"-c", `read a; read b; echo "$b $a"`}, "test", "first\nsecond\n")
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+	if !result.Success || !strings.Contains(result.Output, "second first") {
+		t.Errorf("expected input on stdin, got %+v", result)
+	}
+
+	// Without input, a command waiting on stdin sees EOF instead of hanging.
+	result = RunInRepoWithOptions(repo, "sh", []string{"-c", `if read line; then echo got; else echo eof; fi`}, "test", RunOptions{Timeout: 5 * time.Second})
+	if !result.Success || !strings.Contains(result.Output, "eof") {
+		t.Errorf("expected EOF on stdin, got %+v", result)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out strings.Builder
+	w := newPrefixWriter(&out, "alpha")
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\nthree"))
+	if out.String() != "[alpha] one\n[alpha] two\n" {
+		t.Errorf("expected complete lines only, got %q", out.String())
+	}
+	w.Flush()
+	if out.String() != "[alpha] one\n[alpha] two\n[alpha] three\n" {
+		t.Errorf("expected flushed partial line, got %q", out.String())
+	}
+}
+
+func TestRunInRepo_SeparatesStderr(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-stderr", Local: t.TempDir()}
+	result := RunInRepo(repo, "sh", []string{"-c", "echo out; echo warn1 >&2; echo warn2 >&2"}, "test")
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	if !result.Success {
+		t.Fatalf("expected success, got exit %d", result.ExitCode)
+	}
+	if result.StderrFile != "/tmp/orchestrator-test-runner-test-stderr.stderr.log" {
+		t.Errorf("unexpected stderr file %q", result.StderrFile)
+	}
+
+	stdout, _ := os.ReadFile(result.LogFile)
+	if !strings.Contains(string(stdout), "---\nout\n"+logFooterPrefix) {
+		t.Errorf("unexpected stdout log %q", stdout)
+	}
+
+	lines, err := LastStderrLines(result, 1)
+	if err != nil {
+		t.Fatalf("LastStderrLines: %v", err)
+	}
+	if len(lines) != 1 || lines[0] != "warn2" {
+		t.Errorf("expected [warn2], got %v", lines)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	os.WriteFile(path, []byte("a\nb\nc\nd\n"), 0644)
+
+	lines, err := TailLines(path, 2)
+	if err != nil {
+		t.Fatalf("TailLines: %v", err)
+	}
+	if strings.Join(lines, ",") != "c,d" {
+		t.Errorf("expected c,d got %v", lines)
+	}
+
+	all, _ := TailLines(path, 0)
+	if len(all) != 4 {
+		t.Errorf("expected all 4 lines, got %v", all)
+	}
+}
+
+func TestWriteResultsText(t *testing.T) {
+	root := t.TempDir()
+	results := []Result{
+		{Repo: "alpha", Command: "go test ./...", Success: true, Duration: 1.5, LogFile: "/tmp/a.log"},
+		{Repo: "beta", Command: "npm test", Duration: 2.0, LogFile: "/tmp/b.log"},
+	}
+
+	if err := WriteResultsText(root, "test-results.txt", results); err != nil {
+		t.Fatalf("WriteResultsText: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(root, "state", "test-results.txt"))
+	want := "[PASS] alpha: go test ./... (1.5s) -> /tmp/a.log\n[FAIL] beta: npm test (2.0s) -> /tmp/b.log\n"
+	if string(data) != want {
+		t.Errorf("unexpected text output:\n%s", data)
+	}
+}
+
+func TestHasNpmScript(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"scripts": {"lint": "eslint .", "test": "jest"}}`), 0644)
+
+	if !hasNpmScript(dir, "lint") {
+		t.Error("expected lint script to be detected")
+	}
+	if hasNpmScript(dir, "format") {
+		t.Error("did not expect format script")
+	}
+	if hasNpmScript(t.TempDir(), "lint") {
+		t.Error("did not expect lint script without package.json")
+	}
+}
+
+func TestRunSteps_CombinesOutputAndFailsOnAnyStep(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-steps", Local: t.TempDir()}
+	result := runSteps(repo, [][]string{
+		{"sh", "-c", "echo vet-warning >&2; exit 2"},
+		{"sh", "-c", "echo second"},
+	}, "build-lint")
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	if result.Success || result.ExitCode != 2 {
+		t.Errorf("expected failure with exit 2, got %+v", result)
+	}
+	data, _ := os.ReadFile(result.LogFile)
+	if !strings.Contains(string(data), "$ sh -c echo second\nsecond\n") {
+		t.Errorf("expected output from both steps, got:\n%s", data)
+	}
+	stderr, _ := os.ReadFile(result.StderrFile)
+	if !strings.Contains(string(stderr), "vet-warning") {
+		t.Errorf("expected stderr in %s, got:\n%s", result.StderrFile, stderr)
+	}
+	if header, err := ParseLogHeader(result.LogFile); err != nil || header.Command != result.Command {
+		t.Errorf("ParseLogHeader = %+v, %v", header, err)
+	}
+	if footer, err := ReadLogFooter(result.LogFile); err != nil || footer.ExitCode != 2 {
+		t.Errorf("ReadLogFooter = %+v, %v", footer, err)
+	}
+	if !strings.Contains(result.Output, "vet-warning") || result.LogChecksum == "" {
+		t.Errorf("expected Output and LogChecksum, got %+v", result)
+	}
+}
+
+func TestRunSteps_MissingProgram(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-steps-missing", Local: t.TempDir()}
+	result := runSteps(repo, [][]string{{"orchestrator-no-such-linter", "./..."}}, "build-lint")
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	if result.Success || result.ExitCode != 1 {
+		t.Errorf("expected failure with exit 1, got %+v", result)
+	}
+	stderr, _ := os.ReadFile(result.StderrFile)
+	if !strings.Contains(string(stderr), "orchestrator-no-such-linter") {
+		t.Errorf("expected the start error in the stderr log, got:\n%s", stderr)
+	}
+
+	gone := config.RepoConfig{Name: repo.Name, Local: filepath.Join(repo.Local, "missing")}
+	if result := runSteps(gone, [][]string{{"true"}}, "build-lint"); result.FailureKind != FailureMissing {
+		t.Errorf("expected FailureMissing for a missing directory, got %+v", result)
+	}
+}
+
+func TestParseBenchOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.log")
+	os.WriteFile(path, []byte(`goos: linux
+pkg: example.com/alpha
+BenchmarkEncode-8   	 1000000	      1234 ns/op	     128 B/op	       2 allocs/op
+BenchmarkDecode-8   	  500000	      2500.5 ns/op
+PASS
+ok  	example.com/alpha	5.123s
+`), 0644)
+
+	results, err := ParseBenchOutput(path)
+	if err != nil {
+		t.Fatalf("ParseBenchOutput: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	want := BenchResult{Name: "BenchmarkEncode", NsPerOp: 1234, BytesPerOp: 128, AllocsPerOp: 2}
+	if results[0] != want {
+		t.Errorf("expected %+v, got %+v", want, results[0])
+	}
+	if results[1].Name != "BenchmarkDecode" || results[1].NsPerOp != 2500.5 {
+		t.Errorf("unexpected second result: %+v", results[1])
+	}
+}
+
+func TestCompareBenchToBaseline(t *testing.T) {
+	root := t.TempDir()
+	if _, err := CompareBenchToBaseline(root, "alpha", nil); !os.IsNotExist(err) {
+		t.Errorf("expected not-exist error without baseline, got %v", err)
+	}
+
+	baseline := []BenchResult{{Name: "BenchmarkA", NsPerOp: 100}, {Name: "BenchmarkB", NsPerOp: 200}}
+	if err := WriteBenchBaseline(root, "alpha", baseline); err != nil {
+		t.Fatalf("WriteBenchBaseline: %v", err)
+	}
+
+	deltas, err := CompareBenchToBaseline(root, "alpha", []BenchResult{
+		{Name: "BenchmarkA", NsPerOp: 150},
+		{Name: "BenchmarkB", NsPerOp: 190},
+		{Name: "BenchmarkNew", NsPerOp: 50},
+	})
+	if err != nil {
+		t.Fatalf("CompareBenchToBaseline: %v", err)
+	}
+	if len(deltas) != 2 {
+		t.Fatalf("expected 2 deltas, got %+v", deltas)
+	}
+	if !deltas[0].Regression || deltas[0].DeltaPercent != 50 {
+		t.Errorf("expected BenchmarkA regression of 50%%, got %+v", deltas[0])
+	}
+	if deltas[1].Regression {
+		t.Errorf("did not expect BenchmarkB regression: %+v", deltas[1])
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	tests := []struct {
+		name   string
+		result Result
+		want   FailureKind
+	}{
+		{"success", Result{Success: true}, ""},
+		{"diverged", Result{
+			LogFile:    write("pull.log", ""),
+			StderrFile: write("pull.stderr.log", "hint: You have divergent branches and need to specify how to reconcile them.\nfatal: Not possible to fast-forward, aborting.\n"),
+		}, FailureFFOnlyConflict},
+		{"network", Result{
+			StderrFile: write("fetch.stderr.log", "ssh: Could not resolve host: gitlab.com\nfatal: Could not read from remote repository.\n"),
+		}, FailureNetwork},
+		{"other", Result{LogFile: write("build.log", "undefined: foo\n")}, FailureOther},
+	}
+	for _, tt := range tests {
+		if got := ClassifyFailure(tt.result); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRunInRepo_MissingDirectory(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-missing", Local: filepath.Join(t.TempDir(), "nope")}
+	result := RunInRepo(repo, "true", nil, "sync-fetch")
+	defer os.Remove(result.LogFile)
+
+	if result.Success || result.FailureKind != FailureMissing {
+		t.Errorf("expected missing failure, got %+v", result)
+	}
+}
+
+func TestRunInRepo_LogHeader(t *testing.T) {
+	dir := t.TempDir()
+	repo := config.RepoConfig{Name: "runner-test-header", Local: dir}
+	result := RunInRepo(repo, "echo", []string{"hello", "two words"}, "test")
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	h, err := ParseLogHeader(result.LogFile)
+	if err != nil {
+		t.Fatalf("ParseLogHeader: %v", err)
+	}
+	if h.Repo != repo.Name || h.Command != "echo" || h.Dir != dir || h.OrchestratorVersion == "" {
+		t.Errorf("unexpected header: %+v", h)
+	}
+	if strings.Join(h.Args, "|") != "hello|two words" {
+		t.Errorf("unexpected args: %q", h.Args)
+	}
+	if !h.StartedAt.Equal(result.RunAt.Truncate(time.Second)) {
+		t.Errorf("expected started_at %v, got %v", result.RunAt, h.StartedAt)
+	}
+
+	lines, _ := TailLines(result.LogFile, 2)
+	if len(lines) != 2 || lines[0] != "hello two words" {
+		t.Errorf("expected command output after header, got %v", lines)
+	}
+}
+
+func TestReadLogFooter(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-footer", Local: t.TempDir()}
+	result := RunInRepo(repo, "sh", []string{"-c", "printf partial; exit 3"}, "test")
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	footer, err := ReadLogFooter(result.LogFile)
+	if err != nil {
+		t.Fatalf("ReadLogFooter: %v", err)
+	}
+	if footer.ExitCode != 3 || footer.FinishedAt.IsZero() || footer.Duration < 0 {
+		t.Errorf("unexpected footer: %+v", footer)
+	}
+	lines, _ := TailLines(result.LogFile, 2)
+	if len(lines) != 2 || lines[0] != "partial" {
+		t.Errorf("expected footer on its own line, got %q", lines)
+	}
+
+	path := filepath.Join(t.TempDir(), "old.log")
+	os.WriteFile(path, []byte("no footer here\n"), 0644)
+	if _, err := ReadLogFooter(path); err == nil {
+		t.Error("expected error for log without footer")
+	}
+}
+
+func TestParseLogHeader_Missing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.log")
+	os.WriteFile(path, []byte("plain output\n"), 0644)
+	if _, err := ParseLogHeader(path); err == nil {
+		t.Error("expected error for log without header")
+	}
+}
+
+func TestFirstErrorLine_SkipsHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.log")
+	os.WriteFile(path, []byte("---\nrepo: \"error-pages\"\n---\nok\nmain.go:3: undefined: x error\n"), 0644)
+	if got := FirstErrorLine(path); got != "main.go:3: undefined: x error" {
+		t.Errorf("unexpected first error line %q", got)
+	}
+}
+
+func TestParseTestEventsAndFailedTests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	os.WriteFile(path, []byte(`---
+repo: "alpha"
+---
+{"Action":"run","Package":"example.com/alpha","Test":"TestOK"}
+{"Action":"output","Package":"example.com/alpha","Test":"TestOK","Output":"=== RUN   TestOK\n"}
+{"Action":"pass","Package":"example.com/alpha","Test":"TestOK","Elapsed":0.01}
+{"Action":"run","Package":"example.com/alpha","Test":"TestBad"}
+{"Action":"output","Package":"example.com/alpha","Test":"TestBad","Output":"    bad_test.go:9: boom\n"}
+{"Action":"output","Package":"example.com/alpha","Test":"TestBad","Output":"--- FAIL: TestBad (0.00s)\n"}
+{"Action":"fail","Package":"example.com/alpha","Test":"TestBad","Elapsed":0}
+# example.com/beta
+beta.go:3:2: undefined: x
+{"Action":"fail","Package":"example.com/alpha","Elapsed":0.02}
+`), 0644)
+
+	events, err := ParseTestEvents(path)
+	if err != nil {
+		t.Fatalf("ParseTestEvents: %v", err)
+	}
+	if len(events) != 8 {
+		t.Fatalf("expected 8 events, got %d", len(events))
+	}
+	if events[2].Action != "pass" || events[2].Elapsed != 0.01 {
+		t.Errorf("unexpected event: %+v", events[2])
+	}
+
+	failures := FailedTests(events)
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %+v", failures)
+	}
+	f := failures[0]
+	if f.Package != "example.com/alpha" || f.TestName != "TestBad" || !strings.Contains(f.Output, "boom") {
+		t.Errorf("unexpected failure: %+v", f)
+	}
+}
+
+func TestSyncRepoDryRun(t *testing.T) {
+	result := SyncRepoDryRun(config.RepoConfig{Name: "runner-test-sync", Local: t.TempDir()})
+	if !result.Synced || result.FetchResult.LogFile != "" || result.PullResult.LogFile != "" {
+		t.Errorf("expected dry run to succeed without a log, got %+v", result)
+	}
+
+	result = SyncRepoDryRun(config.RepoConfig{Name: "runner-test-sync", Local: filepath.Join(t.TempDir(), "nope")})
+	if result.Synced || result.FailureKind != FailureMissing {
+		t.Errorf("expected missing failure, got %+v", result)
+	}
+}
+
+func TestSyncRepo_FetchFailureSkipsPull(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	repo := config.RepoConfig{Name: "runner-test-sync", Local: dir}
+	result := SyncRepo(repo)
+	defer os.Remove(result.FetchResult.LogFile)
+	defer os.Remove(result.FetchResult.StderrFile)
+
+	if result.Synced || result.FetchResult.Command != "git fetch origin" || result.PullResult.Command != "" {
+		t.Errorf("expected fetch failure without origin, got %+v", result)
+	}
+	if result.FailureKind == "" || result.Last().Command != "git fetch origin" {
+		t.Errorf("expected failure kind from the fetch, got %+v", result)
+	}
+}
+
+func TestSyncRepo_UpToDate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	upstream := t.TempDir()
+	clone := filepath.Join(t.TempDir(), "clone")
+	for _, args := range [][]string{
+		{"init", "-q", upstream},
+		{"-C", upstream, "-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"clone", "-q", upstream, clone},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	result := SyncRepo(config.RepoConfig{Name: "runner-test-sync", Local: clone})
+	defer os.Remove(result.FetchResult.LogFile)
+	defer os.Remove(result.FetchResult.StderrFile)
+	defer os.Remove(result.PullResult.LogFile)
+	defer os.Remove(result.PullResult.StderrFile)
+
+	if !result.Synced || !result.WasUpToDate || result.FailureKind != "" {
+		t.Errorf("expected an up-to-date sync, got %+v", result)
+	}
+}
+
+func TestBuildRepo_MakefileAndOverride(t *testing.T) {
+	if _, err := exec.LookPath("make"); err != nil {
+		t.Skip("make not available")
+	}
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Makefile"), []byte("build:\n\t@echo made-build\n"), 0644)
+	repo := config.RepoConfig{Name: "runner-test-make", Local: dir, Language: "go"}
+
+	result := BuildRepo(repo)
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+	if !result.Success || result.Command != "make build" {
+		t.Errorf("expected make build to be preferred, got %+v", result)
+	}
+
+	// No test target, so the Go default is used.
+	if !hasMakeTarget(repo, "build") || hasMakeTarget(repo, "test") {
+		t.Error("unexpected make target detection")
+	}
+
+	// A directory named after the target is not a rule, and probing runs
+	// no recipes, not even "+" lines.
+	probe := config.RepoConfig{Name: "runner-test-make", Local: t.TempDir()}
+	os.Mkdir(filepath.Join(probe.Local, "build"), 0755)
+	os.WriteFile(filepath.Join(probe.Local, "Makefile"), []byte("all: build\n\t+touch ran\n"), 0644)
+	if hasMakeTarget(probe, "build") || !hasMakeTarget(probe, "all") {
+		t.Error("expected only the all rule to be found")
+	}
+	if _, err := os.Stat(filepath.Join(probe.Local, "ran")); err == nil {
+		t.Error("expected no recipe to run while probing")
+	}
+
+	repo.BuildCmd = []string{"echo", "custom"}
+	if result := BuildRepo(repo); result.Command != "echo custom" {
+		t.Errorf("expected BuildCmd override, got %q", result.Command)
+	}
+
+	repo = config.RepoConfig{Name: "runner-test-make", Local: t.TempDir(), Language: "make"}
+	if result := TestRepo(repo); result.Command != "make test" || result.Success {
+		t.Errorf("expected failing make test without a Makefile, got %+v", result)
+	}
+}
+
+func TestParseGoTestOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	os.WriteFile(path, []byte(`---
+repo: "demo"
+---
+--- FAIL: TestFoo (0.12s)
+    foo_test.go:10: expected 1, got 2
+--- FAIL: TestBar (0.00s)
+    --- FAIL: TestBar/sub (0.00s)
+        bar_test.go:5: boom
+FAIL
+FAIL	example.com/demo/pkg	0.015s
+ok  	example.com/demo/other	0.002s
+--- FAIL: TestBaz (0.01s)
+    baz_test.go:3: nope
+FAIL
+FAIL	example.com/demo/baz	0.003s
+FAIL
+`), 0644)
+
+	failures, err := ParseGoTestOutput(path)
+	if err != nil {
+		t.Fatalf("ParseGoTestOutput: %v", err)
+	}
+	want := []TestFailure{
+		{Package: "example.com/demo/pkg", TestName: "TestFoo", Output: "    foo_test.go:10: expected 1, got 2\n"},
+		{Package: "example.com/demo/pkg", TestName: "TestBar"},
+		{Package: "example.com/demo/pkg", TestName: "TestBar/sub", Output: "        bar_test.go:5: boom\n"},
+		{Package: "example.com/demo/baz", TestName: "TestBaz", Output: "    baz_test.go:3: nope\n"},
+	}
+	if len(failures) != len(want) {
+		t.Fatalf("expected %d failures, got %+v", len(want), failures)
+	}
+	for i := range want {
+		if failures[i] != want[i] {
+			t.Errorf("failure %d: expected %+v, got %+v", i, want[i], failures[i])
+		}
+	}
+}
+
+func TestParseTRXResults(t *testing.T) {
+	trx := filepath.Join(t.TempDir(), "results.trx")
+	os.WriteFile(trx, []byte(`<?xml version="1.0" encoding="utf-8"?>
+<TestRun xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testId="a1" testName="Adds" outcome="Passed" />
+    <UnitTestResult testId="b2" testName="Divides" outcome="Failed">
+      <Output>
+        <StdOut>dividing</StdOut>
+        <ErrorInfo>
+          <Message>Assert.Equal() Failure</Message>
+          <StackTrace>at Calc.Tests.Divides()</StackTrace>
+        </ErrorInfo>
+      </Output>
+    </UnitTestResult>
+  </Results>
+  <TestDefinitions>
+    <UnitTest name="Adds" id="a1"><TestMethod className="Calc.Tests" name="Adds" /></UnitTest>
+    <UnitTest name="Divides" id="b2"><TestMethod className="Calc.Tests" name="Divides" /></UnitTest>
+  </TestDefinitions>
+</TestRun>
+`), 0644)
+
+	failures, err := ParseTRXResults(trx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %+v", failures)
+	}
+	f := failures[0]
+	if f.Package != "Calc.Tests" || f.TestName != "Divides" {
+		t.Errorf("unexpected failure %+v", f)
+	}
+	if f.Output != "Assert.Equal() Failure\nat Calc.Tests.Divides()\ndividing\n" {
+		t.Errorf("unexpected output %q", f.Output)
+	}
+
+	if _, err := ParseTRXResults(filepath.Join(t.TempDir(), "missing.trx")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestParseMixTestSummary(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "mix.log")
+	os.WriteFile(log, []byte("==> app_a\n...\nFinished in 0.1 seconds\n1 doctest, 4 tests, 1 failure\n==> app_b\n6 tests, 2 failures (1 excluded)\n"), 0644)
+
+	tests, failures, err := ParseMixTestSummary(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tests != 10 || failures != 3 {
+		t.Errorf("expected 10 tests and 3 failures, got %d and %d", tests, failures)
+	}
+}
+
+func TestBuildRepo_ElixirWithoutMix(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	repo := config.RepoConfig{Name: "runner-test-elixir", Local: t.TempDir(), Language: "elixir"}
+	result := BuildRepo(repo)
+	defer os.Remove(result.LogFile)
+
+	if result.ExitCode != 127 || result.Success {
+		t.Fatalf("expected exit 127, got %+v", result)
+	}
+	data, _ := os.ReadFile(result.LogFile)
+	if !strings.Contains(string(data), "mix not found in PATH") {
+		t.Errorf("expected a hint in the log, got %q", data)
+	}
+}
+
+func TestParseSwiftTestOutput(t *testing.T) {
+	dir := t.TempDir()
+	xctest := filepath.Join(dir, "xctest.log")
+	os.WriteFile(xctest, []byte(`Test Suite 'All tests' started at 2024-05-01 10:00:00.000
+Test Suite 'FooTests' started at 2024-05-01 10:00:00.001
+Test Case 'FooTests.testA' passed (0.001 seconds)
+Test Case 'FooTests.testB' failed (0.002 seconds)
+Test Suite 'FooTests' failed at 2024-05-01 10:00:00.004
+	 Executed 2 tests, with 1 failure (0 unexpected) in 0.003 (0.003) seconds
+Test Suite 'BarTests' passed at 2024-05-01 10:00:00.006
+	 Executed 3 tests, with 0 failures (0 unexpected) in 0.001 (0.001) seconds
+Test Suite 'All tests' failed at 2024-05-01 10:00:00.007
+	 Executed 5 tests, with 1 failure (0 unexpected) in 0.004 (0.006) seconds
+`), 0644)
+	if passed, failed, err := ParseSwiftTestOutput(xctest); err != nil || passed != 4 || failed != 1 {
+		t.Errorf("expected 4 passed and 1 failed, got %d, %d, %v", passed, failed, err)
+	}
+
+	// Without a summary, as when the run is cut short, the test cases are counted.
+	partial := filepath.Join(dir, "partial.log")
+	os.WriteFile(partial, []byte("[1/3] Testing FooTests.testA\nTest Case 'FooTests.testA' passed (0.001 seconds)\nTest Case 'FooTests.testB' failed (0.002 seconds)\n"), 0644)
+	if passed, failed, err := ParseSwiftTestOutput(partial); err != nil || passed != 1 || failed != 1 {
+		t.Errorf("expected 1 passed and 1 failed, got %d, %d, %v", passed, failed, err)
+	}
+}
+
+func TestBuildRepo_SwiftWithoutToolchain(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	repo := config.RepoConfig{Name: "runner-test-swift", Local: t.TempDir(), Language: "swift"}
+	result := BuildRepo(repo)
+	defer os.Remove(result.LogFile)
+
+	if result.ExitCode != 127 || result.Command != "swift build -c release" {
+		t.Fatalf("expected exit 127 from swift build -c release, got %+v", result)
+	}
+}
+
+func TestParseSurefireDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "TEST-com.acme.CalcTest.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="com.acme.CalcTest" tests="3" failures="1" errors="1">
+  <testcase name="adds" classname="com.acme.CalcTest" time="0.01"/>
+  <testcase name="divides" classname="com.acme.CalcTest" time="0.02">
+    <failure message="expected: &lt;2&gt; but was: &lt;3&gt;" type="org.opentest4j.AssertionFailedError">at com.acme.CalcTest.divides(CalcTest.java:20)</failure>
+    <system-out>dividing</system-out>
+  </testcase>
+  <testcase name="parses" classname="com.acme.CalcTest" time="0.00">
+    <error message="boom" type="java.lang.NullPointerException"/>
+  </testcase>
+</testsuite>`), 0644)
+	os.WriteFile(filepath.Join(dir, "com.acme.CalcTest.txt"), []byte("ignored"), 0644)
+
+	failures, err := ParseSurefireDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TestFailure{
+		{Package: "com.acme.CalcTest", TestName: "divides", Output: "expected: <2> but was: <3>\nat com.acme.CalcTest.divides(CalcTest.java:20)\ndividing\n"},
+		{Package: "com.acme.CalcTest", TestName: "parses", Output: "boom\n"},
+	}
+	if len(failures) != len(want) {
+		t.Fatalf("expected %d failures, got %+v", len(want), failures)
+	}
+	for i := range want {
+		if failures[i] != want[i] {
+			t.Errorf("failure %d = %+v, want %+v", i, failures[i], want[i])
+		}
+	}
+
+	if failures, err := ParseSurefireDir(filepath.Join(dir, "missing")); err != nil || len(failures) != 0 {
+		t.Errorf("missing dir: got %v, %v", failures, err)
+	}
+}
+
+func TestTestRepo_MavenBuildFailureWithZeroExit(t *testing.T) {
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "mvn"), []byte("#!/bin/sh\necho '[INFO] BUILD FAILURE'\nexit 0\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	local := t.TempDir()
+	reports := filepath.Join(local, "core", "target", "surefire-reports")
+	os.MkdirAll(reports, 0755)
+	os.WriteFile(filepath.Join(reports, "TEST-a.ATest.xml"), []byte(`<testsuite><testcase name="t" classname="a.ATest"><failure message="no"/></testcase></testsuite>`), 0644)
+
+	result := TestRepo(config.RepoConfig{Name: "runner-test-maven", Local: local, Language: "maven"})
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	if result.Success || result.ExitCode != 1 {
+		t.Fatalf("expected BUILD FAILURE to fail the run, got %+v", result)
+	}
+	if result.Command != "mvn -B test" {
+		t.Errorf("command = %q", result.Command)
+	}
+	if len(result.FailedTests) != 1 || result.FailedTests[0].TestName != "t" {
+		t.Errorf("expected the module's Surefire failure, got %+v", result.FailedTests)
+	}
+}
+
+func TestParseJUnitXML_PHPUnit(t *testing.T) {
+	report := filepath.Join(t.TempDir(), "junit.xml")
+	os.WriteFile(report, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="Unit" tests="2" failures="1">
+    <testsuite name="App\Tests\CartTest" file="tests/CartTest.php">
+      <testcase name="testTotal" class="App\Tests\CartTest" classname="App.Tests.CartTest" file="tests/CartTest.php" line="12"/>
+      <testcase name="testDiscount" class="App\Tests\CartTest" classname="App.Tests.CartTest" file="tests/CartTest.php" line="20">
+        <failure type="PHPUnit\Framework\ExpectationFailedException">Failed asserting that 90 matches expected 80.</failure>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`), 0644)
+
+	failures, err := ParseJUnitXML(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TestFailure{Package: "App\\Tests\\CartTest", TestName: "testDiscount", Output: "Failed asserting that 90 matches expected 80.\n"}
+	if len(failures) != 1 || failures[0] != want {
+		t.Errorf("failures = %+v, want %+v", failures, want)
+	}
+
+	if _, err := ParseJUnitXML(filepath.Join(t.TempDir(), "missing.xml")); err == nil {
+		t.Error("expected an error for a missing report")
+	}
+}
+
+func TestTestRepo_PHP(t *testing.T) {
+	local := t.TempDir()
+	os.MkdirAll(filepath.Join(local, "vendor", "bin"), 0755)
+	// A stand-in phpunit that writes a JUnit report to the --log-junit path.
+	os.WriteFile(filepath.Join(local, "vendor", "bin", "phpunit"), []byte(`#!/bin/sh
+echo '<testsuites><testsuite><testcase name="testA" class="ATest"><failure>no</failure></testcase></testsuite></testsuites>' > "$3"
+exit 1
+`), 0755)
+
+	result := TestRepo(config.RepoConfig{Name: "runner-test-php", Local: local, Language: "php"})
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+	defer os.Remove(result.ArtifactFile)
+
+	if result.Success || result.Command != "./vendor/bin/phpunit --colors=never --log-junit "+PHPUnitXMLPath("runner-test-php") {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.ArtifactFile != PHPUnitXMLPath("runner-test-php") {
+		t.Errorf("ArtifactFile = %q", result.ArtifactFile)
+	}
+	if len(result.FailedTests) != 1 || result.FailedTests[0].Package != "ATest" || result.FailedTests[0].TestName != "testA" {
+		t.Errorf("expected the JUnit failure, got %+v", result.FailedTests)
+	}
+}
+
+func TestLineRing(t *testing.T) {
+	r := newLineRing(3)
+	if r.String() != "" {
+		t.Errorf("empty ring = %q", r.String())
+	}
+	r.Write([]byte("one\ntwo\n"))
+	if got := r.String(); got != "one\ntwo\n" {
+		t.Errorf("partly full ring = %q", got)
+	}
+	r.Write([]byte("three\nfo"))
+	r.Write([]byte("ur\nfive"))
+	if got := r.String(); got != "three\nfour\nfive\n" {
+		t.Errorf("wrapped ring = %q", got)
+	}
+	r.Write([]byte(strings.Repeat("x", 2*maxOutputLineBytes) + "\n"))
+	want := "three\nfour\nfive" + strings.Repeat("x", maxOutputLineBytes-len("five")) + "\n"
+	if got := r.String(); got != want {
+		t.Errorf("expected long line to be capped at %d bytes, got %d bytes", maxOutputLineBytes, len(got))
+	}
+}
+
+func TestRunInRepo_Output(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-output", Local: t.TempDir()}
+	result := RunInRepoWithOptions(repo, "sh", []string{"-c", `for i in 1 2 3 4 5; do echo out$i; done`}, "test", RunOptions{MaxOutputLines: 3})
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	if result.Output != "out3\nout4\nout5\n" {
+		t.Errorf("Output = %q", result.Output)
+	}
+	if result = RunInRepo(repo, "sh", []string{"-c", "echo oops >&2; exit 1"}, "test"); result.Output != "oops\n" {
+		t.Errorf("expected stderr in Output, got %q", result.Output)
+	}
+
+	result = RunInRepoWithOptions(repo, "echo", []string{"hi"}, "test", RunOptions{MaxOutputLines: -1})
+	if result.Output != "" {
+		t.Errorf("expected no output with MaxOutputLines < 0, got %q", result.Output)
+	}
+	if result = RunInRepo(repo, "echo", []string{"hi"}, "test"); result.Output != "hi\n" {
+		t.Errorf("default Output = %q", result.Output)
+	}
+}
+
+func TestParseRaceOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "race.log")
+	os.WriteFile(path, []byte(`==================
+WARNING: DATA RACE
+Write at 0x00c0000182e8 by goroutine 8:
+  example.com/r.TestRace.func1()
+      /src/r/r_test.go:8 +0x33
+
+Previous write at 0x00c0000182e8 by goroutine 7:
+  example.com/r.TestRace()
+      /src/r/r_test.go:9 +0x104
+
+Goroutine 8 (running) created at:
+  example.com/r.TestRace()
+      /src/r/r_test.go:8 +0xf9
+==================
+==================
+WARNING: DATA RACE
+Read at 0x00c0000182f0 by main goroutine:
+  main.main()
+      /src/r/main.go:12 +0x44
+
+Previous write at 0x00c0000182f0 by goroutine 6:
+  main.main.func1()
+      /src/r/main.go:9 +0x3c
+==================
+--- FAIL: TestRace (0.00s)
+    testing.go:1865: race detected during execution of test
+FAIL
+FAIL	example.com/r	0.013s
+`), 0644)
+
+	reports, err := ParseRaceOutput(path)
+	if err != nil {
+		t.Fatalf("ParseRaceOutput: %v", err)
+	}
+	want := []RaceReport{
+		{Goroutine1: "goroutine 8", Goroutine2: "goroutine 7", Location: "/src/r/r_test.go:8"},
+		{Goroutine1: "main goroutine", Goroutine2: "goroutine 6", Location: "/src/r/main.go:12"},
+	}
+	if len(reports) != len(want) {
+		t.Fatalf("expected %d reports, got %+v", len(want), reports)
+	}
+	for i := range want {
+		if reports[i] != want[i] {
+			t.Errorf("report %d: expected %+v, got %+v", i, want[i], reports[i])
+		}
+	}
+}
+
+func TestReadIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	if got, err := ReadIgnoreFile(dir); err != nil || got != nil {
+		t.Fatalf("missing file: got %v, %v", got, err)
+	}
+	os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte("# needs a database\ninternal/integration\n\n  ./e2e/...  \n"), 0644)
+	got, err := ReadIgnoreFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"internal/integration", "./e2e/..."}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ReadIgnoreFile = %v, want %v", got, want)
+	}
+}
+
+func TestTestRepo_GoIgnoreFile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                      "module example.com/ign\n\ngo 1.21\n",
+		"ok/ok_test.go":               "package ok\n\nimport \"testing\"\n\nfunc TestOK(t *testing.T) {}\n",
+		"integration/db_test.go":      "package integration\n\nimport \"testing\"\n\nfunc TestDB(t *testing.T) { t.Fatal(\"needs a database\") }\n",
+		"e2e/browser/browser_test.go": "package browser\n\nimport \"testing\"\n\nfunc TestBrowser(t *testing.T) { t.Fatal(\"needs a browser\") }\n",
+		IgnoreFileName:                "integration\nexample.com/ign/e2e/...\n",
+	}
+	for name, content := range files {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)
+		os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	}
+	repo := config.RepoConfig{Name: "runner-test-ignore", Local: dir, Language: "go"}
+
+	result := TestRepo(repo)
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+	if !result.Success {
+		t.Fatalf("expected ignored packages to be skipped, got %+v", result)
+	}
+	want := []string{"example.com/ign/e2e/browser", "example.com/ign/integration"}
+	if strings.Join(result.ExcludedPackages, ",") != strings.Join(want, ",") {
+		t.Errorf("ExcludedPackages = %v, want %v", result.ExcludedPackages, want)
+	}
+	if result.Command != "go test example.com/ign/ok -short -timeout 10m" {
+		t.Errorf("unexpected command %q", result.Command)
+	}
+}
+
+func TestWriteJUnitXML(t *testing.T) {
+	root := t.TempDir()
+	results := []Result{
+		{Repo: "alpha", Command: "go test ./...", Success: true, Duration: 1.5},
+		{Repo: "beta", Command: "go test ./...", FailedTests: []TestFailure{
+			{Package: "example.com/beta", TestName: "TestOne", Output: "boom\n"},
+			{Package: "example.com/beta", TestName: "TestTwo"},
+		}},
+		{Repo: "gamma", Command: "npm test", ExitCode: 1, FailureKind: FailureOther},
+	}
+	if err := WriteJUnitXML(root, results); err != nil {
+		t.Fatalf("WriteJUnitXML: %v", err)
+	}
+	path := filepath.Join(root, "state", "junit.xml")
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{`<testsuites name="orchestrator" tests="4" failures="3"`, `<testsuite name="alpha" tests="1" failures="0" time="1.500">`, `<testsuite name="beta" tests="2" failures="2"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %q in:\n%s", want, data)
+		}
+	}
+
+	failures, err := ParseJUnitXML(path)
+	if err != nil {
+		t.Fatalf("ParseJUnitXML: %v", err)
+	}
+	var names []string
+	for _, f := range failures {
+		names = append(names, f.Package+"."+f.TestName)
+	}
+	if want := "example.com/beta.TestOne,example.com/beta.TestTwo,gamma.npm test"; strings.Join(names, ",") != want {
+		t.Errorf("failures = %v, want %s", names, want)
+	}
+}
+
+func TestVerifyLogChecksum(t *testing.T) {
+	repo := config.RepoConfig{Name: "runner-test-checksum", Local: t.TempDir()}
+	result := RunInRepo(repo, "echo", []string{"hello"}, "test")
+	defer os.Remove(result.LogFile)
+	defer os.Remove(result.StderrFile)
+
+	if len(result.LogChecksum) != 64 {
+		t.Fatalf("expected a SHA-256 hex checksum, got %q", result.LogChecksum)
+	}
+	if ok, err := VerifyLogChecksum(result); err != nil || !ok {
+		t.Fatalf("expected untouched log to verify, got %v, %v", ok, err)
+	}
+
+	f, _ := os.OpenFile(result.LogFile, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("tampered\n")
+	f.Close()
+	if ok, err := VerifyLogChecksum(result); err != nil || ok {
+		t.Errorf("expected edited log to fail verification, got %v, %v", ok, err)
+	}
+
+	if _, err := VerifyLogChecksum(Result{Repo: "none"}); err == nil {
+		t.Error("expected an error for a result without a checksum")
+	}
+}
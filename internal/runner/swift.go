@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// swiftExecutedRe matches the XCTest summary printed after each "Test Suite
+// '...' passed" or "failed" line, such as "Executed 12 tests, with 1
+// failure (0 unexpected) in 0.042 (0.043) seconds".
+var swiftExecutedRe = regexp.MustCompile(`Executed (\d+) tests?, with (\d+) failures?`)
+
+// runSwift runs a swift subcommand, or fails with exit code 127 and a hint in
+// the log when the Swift toolchain is not installed.
+func runSwift(repo config.RepoConfig, args []string, logPrefix string) Result {
+	if _, err := exec.LookPath("swift"); err != nil {
+		return missingTool(repo, "swift", args, logPrefix, "install Swift (https://www.swift.org/install/)")
+	}
+	return RunInRepo(repo, "swift", args, logPrefix)
+}
+
+// ParseSwiftTestOutput counts the passed and failed tests in a swift test log.
+// The last "Executed N tests, with M failures" summary is used, as the
+// outermost suite is reported last; without one, the "Test Case '...'
+// passed" and "failed" lines are counted instead.
+func ParseSwiftTestOutput(logFile string) (passed, failed int, err error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	summary := false
+	var casesPassed, casesFailed int
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := swiftExecutedRe.FindStringSubmatch(line); m != nil {
+			total, _ := strconv.Atoi(m[1])
+			failed, _ = strconv.Atoi(m[2])
+			passed, summary = total-failed, true
+			continue
+		}
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "Test Case '"); ok {
+			switch {
+			case strings.Contains(rest, "' passed ("):
+				casesPassed++
+			case strings.Contains(rest, "' failed ("):
+				casesFailed++
+			}
+		}
+	}
+	if !summary {
+		passed, failed = casesPassed, casesFailed
+	}
+	return passed, failed, scanner.Err()
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/config"
+)
+
+// SyncResult is the outcome of SyncRepo.
+type SyncResult struct {
+	Repo        string `json:"repo"`
+	FetchResult Result `json:"fetch"`
+	// PullResult is the zero Result when the fetch failed and the pull was
+	// skipped.
+	PullResult Result `json:"pull"`
+	// Synced reports whether both the fetch and the pull succeeded.
+	Synced bool `json:"synced"`
+	// WasUpToDate reports whether the pull found nothing to merge.
+	WasUpToDate bool `json:"was_up_to_date"`
+	// FailureKind classifies the failing step; it is empty when Synced.
+	FailureKind FailureKind `json:"failure_kind,omitempty"`
+}
+
+// Last returns the result of the last step that ran: the fetch if it failed,
+// otherwise the pull.
+func (s SyncResult) Last() Result {
+	if !s.FetchResult.Success {
+		return s.FetchResult
+	}
+	return s.PullResult
+}
+
+// Duration returns the combined fetch and pull time in seconds.
+func (s SyncResult) Duration() float64 {
+	return s.FetchResult.Duration + s.PullResult.Duration
+}
+
+// SyncRepo fetches origin and fast-forwards the current branch, logging to
+// /tmp/orchestrator-sync-fetch-<repo>.log and /tmp/orchestrator-sync-pull-<repo>.log.
+// If the fetch fails the pull is skipped.
+func SyncRepo(repo config.RepoConfig) SyncResult {
+	result := SyncResult{Repo: repo.Name}
+
+	result.FetchResult = RunInRepo(repo, "git", []string{"fetch", "origin"}, "sync-fetch")
+	if !result.FetchResult.Success {
+		result.FailureKind = result.FetchResult.FailureKind
+		return result
+	}
+
+	result.PullResult = RunInRepo(repo, "git", []string{"pull", "--ff-only"}, "sync-pull")
+	if !result.PullResult.Success {
+		result.FailureKind = result.PullResult.FailureKind
+		return result
+	}
+	result.Synced = true
+	if data, err := os.ReadFile(result.PullResult.LogFile); err == nil {
+		// Older git versions spell it "up-to-date".
+		out := string(data)
+		result.WasUpToDate = strings.Contains(out, "Already up to date") || strings.Contains(out, "Already up-to-date")
+	}
+	return result
+}
+
+// SyncRepoDryRun describes what SyncRepo would run without running git. It
+// succeeds unless the repository directory is missing.
+func SyncRepoDryRun(repo config.RepoConfig) SyncResult {
+	fetch := Result{
+		Repo:    repo.Name,
+		Command: "git fetch origin (dry run)",
+		Success: true,
+		RunAt:   time.Now(),
+	}
+	if _, err := os.Stat(repo.Local); os.IsNotExist(err) {
+		fetch.Success = false
+		fetch.ExitCode = 1
+		fetch.FailureKind = FailureMissing
+		return SyncResult{Repo: repo.Name, FetchResult: fetch, FailureKind: FailureMissing}
+	}
+	pull := fetch
+	pull.Command = "git pull --ff-only (dry run)"
+	return SyncResult{Repo: repo.Name, FetchResult: fetch, PullResult: pull, Synced: true}
+}
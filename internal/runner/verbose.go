@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// VerboseEnv enables verbose mode for every run when set to "1".
+const VerboseEnv = "ORCHESTRATOR_VERBOSE"
+
+// verboseMu serializes lines written to stderr by concurrent runs.
+var verboseMu sync.Mutex
+
+func verbose(opts RunOptions) bool {
+	return opts.Verbose || os.Getenv(VerboseEnv) == "1"
+}
+
+// prefixWriter writes complete lines to w, each prefixed, holding verboseMu
+// so that lines from parallel runs do not interleave. A trailing partial
+// line is held until the next newline or Flush.
+type prefixWriter struct {
+	w      io.Writer
+	prefix []byte
+	buf    []byte
+}
+
+func newPrefixWriter(w io.Writer, repo string) *prefixWriter {
+	return &prefixWriter{w: w, prefix: []byte("[" + repo + "] ")}
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	i := bytes.LastIndexByte(p.buf, '\n')
+	if i < 0 {
+		return len(b), nil
+	}
+	p.emit(p.buf[:i+1])
+	p.buf = append(p.buf[:0], p.buf[i+1:]...)
+	return len(b), nil
+}
+
+// Flush writes any buffered partial line, terminated with a newline.
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		p.emit(append(p.buf, '\n'))
+		p.buf = p.buf[:0]
+	}
+}
+
+// emit writes lines, which must end in a newline, with the prefix on each.
+// Write errors are ignored: the log file is the record of the run.
+func (p *prefixWriter) emit(lines []byte) {
+	var out bytes.Buffer
+	for len(lines) > 0 {
+		i := bytes.IndexByte(lines, '\n')
+		out.Write(p.prefix)
+		out.Write(lines[:i+1])
+		lines = lines[i+1:]
+	}
+	verboseMu.Lock()
+	p.w.Write(out.Bytes())
+	verboseMu.Unlock()
+}
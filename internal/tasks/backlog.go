@@ -0,0 +1,149 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// backlogDir is the directory of backlog shards, relative to tasks/. When it
+// exists the backlog is sharded: ListBacklog unions every *.md file in it and
+// AddToBacklog files new tasks into a shard chosen by priority or repo. When
+// it does not exist the backlog is the single legacy backlog.md.
+const backlogDir = "backlog"
+
+// legacyBacklog is the single-file backlog used before sharding.
+const legacyBacklog = "backlog.md"
+
+// Sharded reports whether the backlog is split across tasks/backlog/*.md.
+func (m *Manager) Sharded() bool {
+	info, err := os.Stat(filepath.Join(m.tasksDir, backlogDir))
+	return err == nil && info.IsDir()
+}
+
+// backlogFiles returns the backlog files relative to tasks/, in sorted order.
+// In sharded mode a leftover backlog.md is included so its tasks stay visible.
+func (m *Manager) backlogFiles() ([]string, error) {
+	if !m.Sharded() {
+		return []string{legacyBacklog}, nil
+	}
+
+	var files []string
+	if _, err := os.Stat(filepath.Join(m.tasksDir, legacyBacklog)); err == nil {
+		files = append(files, legacyBacklog)
+	}
+	matches, err := filepath.Glob(filepath.Join(m.tasksDir, backlogDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	for _, path := range matches {
+		files = append(files, filepath.Join(backlogDir, filepath.Base(path)))
+	}
+	return files, nil
+}
+
+// isBacklogFile reports whether a file returned by TaskBlock is part of the backlog.
+func isBacklogFile(filename string) bool {
+	return filename == legacyBacklog || filepath.Dir(filename) == backlogDir
+}
+
+// shardNameRe matches characters not allowed in a shard file name.
+var shardNameRe = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// backlogShard returns the shard file for a task: an existing shard for its
+// priority, then an existing shard for its repo, then a new shard named after
+// the priority, the repo, or "unsorted", in that order.
+func (m *Manager) backlogShard(t Task) string {
+	var candidates []string
+	if t.Priority != "" {
+		candidates = append(candidates, shardFile(t.Priority))
+	}
+	if t.Repo != "" {
+		candidates = append(candidates, shardFile("repo-"+t.Repo))
+	}
+	for _, name := range candidates {
+		if _, err := os.Stat(filepath.Join(m.tasksDir, name)); err == nil {
+			return name
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return shardFile("unsorted")
+}
+
+func shardFile(name string) string {
+	name = strings.Trim(shardNameRe.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	return filepath.Join(backlogDir, name+".md")
+}
+
+// MigrateToSharded splits backlog.md into tasks/backlog/ shards, routing each
+// task as AddToBacklog would, and removes backlog.md. Each task is copied as
+// written, including any notes under it; only section headings, which
+// organize backlog.md by priority, are dropped. The shards are written to a
+// temporary directory and renamed into place, so a failed migration leaves
+// backlog.md untouched.
+func (m *Manager) MigrateToSharded() error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if m.Sharded() {
+		return fmt.Errorf("backlog is already sharded")
+	}
+	backlog, err := m.ParseTasks(legacyBacklog)
+	if err != nil {
+		return fmt.Errorf("reading backlog: %w", err)
+	}
+
+	shards := map[string]string{}
+	var order []string
+	for _, t := range backlog {
+		name := m.backlogShard(t)
+		if _, ok := shards[name]; !ok {
+			shards[name] = fmt.Sprintf("# Backlog: %s\n", strings.TrimSuffix(filepath.Base(name), ".md"))
+			order = append(order, name)
+		}
+		shards[name] += "\n" + shardBlock(t)
+	}
+
+	tmpDir := filepath.Join(m.tasksDir, backlogDir+".tmp")
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+	if err := os.Mkdir(tmpDir, 0755); err != nil {
+		return err
+	}
+	for _, name := range order {
+		if err := os.WriteFile(filepath.Join(tmpDir, filepath.Base(name)), []byte(shards[name]), 0644); err != nil {
+			os.RemoveAll(tmpDir)
+			return err
+		}
+	}
+	if err := os.Rename(tmpDir, filepath.Join(m.tasksDir, backlogDir)); err != nil {
+		os.RemoveAll(tmpDir)
+		return err
+	}
+	return os.Remove(filepath.Join(m.tasksDir, legacyBacklog))
+}
+
+// shardBlock returns a task's block as MigrateToSharded writes it: the raw
+// text, less any section headings that followed it in backlog.md.
+func shardBlock(t Task) string {
+	if isFrontmatterStart(strings.Split(t.RawText, "\n"), 0) {
+		return strings.TrimRight(t.RawText, "\n") + "\n"
+	}
+	block := fmt.Sprintf("### [%s] %s\n", t.ID, t.Title)
+	for _, line := range strings.Split(t.RawText, "\n") {
+		if !strings.HasPrefix(line, "# ") && !strings.HasPrefix(line, "## ") {
+			block += line + "\n"
+		}
+	}
+	return strings.TrimRight(block, "\n") + "\n"
+}
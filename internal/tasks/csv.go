@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// csvColumns maps CSV header names (case-insensitive) to the task field they set.
+var csvColumns = map[string]func(*Task, string){
+	"title":       func(t *Task, v string) { t.Title = v },
+	"repo":        func(t *Task, v string) { t.Repo = v },
+	"type":        func(t *Task, v string) { t.Type = v },
+	"priority":    func(t *Task, v string) { t.Priority = v },
+	"description": func(t *Task, v string) { t.Description = v },
+	"due":         func(t *Task, v string) { t.DueDate = v },
+}
+
+// ReadTasksCSV parses tasks from CSV with a header row naming some of the
+// columns title, repo, type, priority, description, and due, in any order.
+// Other columns are ignored. Rows with an empty title or an unparseable due
+// date are left out and described in skipped, one message per row.
+func ReadTasksCSV(r io.Reader) (list []Task, skipped []string, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("CSV is empty")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	setters := make([]func(*Task, string), len(header))
+	hasTitle := false
+	for i, name := range header {
+		// Spreadsheet exports often start with a UTF-8 byte order mark.
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		setters[i] = csvColumns[name]
+		hasTitle = hasTitle || name == "title"
+	}
+	if !hasTitle {
+		return nil, nil, fmt.Errorf("CSV header has no title column")
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		var t Task
+		for i, val := range record {
+			if i < len(setters) && setters[i] != nil {
+				// Task fields are single-line in the markdown files.
+				setters[i](&t, strings.Join(strings.Fields(val), " "))
+			}
+		}
+		if t.Title == "" {
+			skipped = append(skipped, fmt.Sprintf("line %d: empty title", line))
+			continue
+		}
+		if t.DueDate != "" {
+			if _, err := time.Parse(dueDateLayout, t.DueDate); err != nil {
+				skipped = append(skipped, fmt.Sprintf("line %d: due date %q is not YYYY-MM-DD", line, t.DueDate))
+				continue
+			}
+		}
+		list = append(list, t)
+	}
+	return list, skipped, nil
+}
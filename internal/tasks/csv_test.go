@@ -0,0 +1,36 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadTasksCSV(t *testing.T) {
+	input := "Title,repo,priority,due,notes\n" +
+		"Fix login,auth,high,2025-07-01,ignored\n" +
+		",auth,low,,\n" +
+		"\"Multi\nline\",web,,,\n" +
+		"Bad due,web,,July,\n" +
+		"Short row\n"
+
+	list, skipped, err := ReadTasksCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadTasksCSV: %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("expected 3 tasks, got %+v", list)
+	}
+	if got := list[0]; got.Title != "Fix login" || got.Repo != "auth" || got.Priority != "high" || got.DueDate != "2025-07-01" {
+		t.Errorf("unexpected first task: %+v", got)
+	}
+	if list[1].Title != "Multi line" || list[2].Title != "Short row" {
+		t.Errorf("unexpected titles: %q, %q", list[1].Title, list[2].Title)
+	}
+	if len(skipped) != 2 || !strings.HasPrefix(skipped[0], "line 3:") || !strings.HasPrefix(skipped[1], "line 6:") {
+		t.Errorf("unexpected skipped rows: %v", skipped)
+	}
+
+	if _, _, err := ReadTasksCSV(strings.NewReader("repo,type\nx,y\n")); err == nil {
+		t.Error("expected error without a title column")
+	}
+}
@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// States are the task states, in lifecycle order. Each is stored in <state>.md.
+var States = []string{"backlog", "active", "paused", "completed"}
+
+// ExportTasks returns the tasks in the given states, in the order the states
+// are listed, with each task's State set. "all" expands to every state.
+// A missing state file contributes no tasks.
+func (m *Manager) ExportTasks(states []string) ([]Task, error) {
+	var expanded []string
+	for _, st := range states {
+		if st == "all" {
+			expanded = append(expanded, States...)
+			continue
+		}
+		if !isState(st) {
+			return nil, fmt.Errorf("unknown task state %q (use %s, or all)", st, strings.Join(States, ", "))
+		}
+		expanded = append(expanded, st)
+	}
+
+	var all []Task
+	for _, st := range expanded {
+		var list []Task
+		var err error
+		if st == "backlog" {
+			list, err = m.ListBacklog()
+		} else {
+			list, err = m.ParseTasks(st + ".md")
+		}
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", st, err)
+		}
+		for _, t := range list {
+			t.State = st
+			all = append(all, t)
+		}
+	}
+	return all, nil
+}
+
+func isState(s string) bool {
+	for _, st := range States {
+		if st == s {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatMarkdown renders tasks as normalized markdown: one "# <State>" section
+// per state, in order of first appearance, with fields in a fixed order.
+// Lines in the source files that are not recognized fields are dropped.
+func FormatMarkdown(list []Task) string {
+	var b strings.Builder
+	current := ""
+	for i, t := range list {
+		if i == 0 || t.State != current {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			current = t.State
+			title := "Tasks"
+			if current != "" {
+				title = strings.ToUpper(current[:1]) + current[1:]
+			}
+			fmt.Fprintf(&b, "# %s\n", title)
+		}
+		b.WriteString("\n")
+		b.WriteString(taskEntry(t))
+	}
+	return b.String()
+}
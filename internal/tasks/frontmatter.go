@@ -0,0 +1,273 @@
+package tasks
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A task can also be written as a YAML frontmatter block followed by a
+// free-form markdown description:
+//
+//	---
+//	id: T-001
+//	title: "My Task"
+//	repo: myrepo
+//	tags: [ui, docs]
+//	---
+//	Anything up to the next task or "## " heading is the description.
+//
+// Only the flat subset of YAML the task fields need is understood: one
+// "key: value" per line, optionally quoted, with lists written as [a, b],
+// as a comma-separated value, or as "- item" lines under the key.
+const frontmatterDelim = "---"
+
+var (
+	frontmatterKeyRe  = regexp.MustCompile(`^(\w+):\s*(.*)$`)
+	frontmatterItemRe = regexp.MustCompile(`^\s*-\s+(.*)$`)
+)
+
+// frontmatterListFields are written as [a, b] flow lists.
+var frontmatterListFields = map[string]bool{"tags": true, "links": true, "split_into": true}
+
+// taskStart reports whether a task begins at lines[i], in either format, and
+// returns its ID.
+func taskStart(lines []string, i int) (string, bool) {
+	if matches := taskHeaderRe.FindStringSubmatch(lines[i]); matches != nil {
+		return matches[1], true
+	}
+	if frontmatterClose(lines, i) < 0 {
+		return "", false
+	}
+	fields, _ := frontmatterFields(lines[i:])
+	for _, f := range fields {
+		if f[0] == "id" {
+			return f[1], true
+		}
+	}
+	return "", false
+}
+
+// isFrontmatterStart reports whether lines[i] opens a frontmatter task.
+func isFrontmatterStart(lines []string, i int) bool {
+	return frontmatterClose(lines, i) >= 0
+}
+
+// frontmatterClose returns the index of the "---" closing the frontmatter
+// opened at lines[i], or -1 if lines[i] does not open a frontmatter task:
+// every line up to the closing delimiter must be a key, a list item, or
+// blank, and one key must be a non-empty id.
+func frontmatterClose(lines []string, i int) int {
+	if strings.TrimRight(lines[i], " \t\r") != frontmatterDelim {
+		return -1
+	}
+	hasID := false
+	for j := i + 1; j < len(lines); j++ {
+		line := strings.TrimRight(lines[j], " \t\r")
+		if line == frontmatterDelim {
+			if !hasID {
+				return -1
+			}
+			return j
+		}
+		if matches := frontmatterKeyRe.FindStringSubmatch(line); matches != nil {
+			if strings.EqualFold(matches[1], "id") && unquoteYAML(matches[2]) != "" {
+				hasID = true
+			}
+			continue
+		}
+		if line != "" && !frontmatterItemRe.MatchString(line) {
+			return -1
+		}
+	}
+	return -1
+}
+
+// frontmatterBlockEnd returns the exclusive end of the frontmatter task at
+// lines[start], excluding trailing blank lines. The description body runs
+// until the next task or heading.
+func frontmatterBlockEnd(lines []string, start int) int {
+	end := frontmatterClose(lines, start) + 1
+	for i := end; i < len(lines); i++ {
+		if _, ok := taskStart(lines, i); ok || strings.HasPrefix(lines[i], "# ") || strings.HasPrefix(lines[i], "## ") {
+			break
+		}
+		if strings.TrimSpace(lines[i]) != "" {
+			end = i + 1
+		}
+	}
+	return end
+}
+
+// frontmatterFields returns the lowercased key/value pairs of the
+// frontmatter opening block, in order, and the markdown body after it. List
+// values are joined with ", " so setField can parse them.
+func frontmatterFields(block []string) (fields [][2]string, body string) {
+	closing := frontmatterClose(block, 0)
+	if closing < 0 {
+		return nil, ""
+	}
+	var items []string
+	flush := func() {
+		if len(items) > 0 && len(fields) > 0 {
+			fields[len(fields)-1][1] = strings.Join(items, ", ")
+		}
+		items = nil
+	}
+	for _, line := range block[1:closing] {
+		if matches := frontmatterKeyRe.FindStringSubmatch(strings.TrimRight(line, " \t\r")); matches != nil {
+			flush()
+			fields = append(fields, [2]string{strings.ToLower(matches[1]), frontmatterValue(matches[2])})
+			continue
+		}
+		if matches := frontmatterItemRe.FindStringSubmatch(line); matches != nil {
+			items = append(items, unquoteYAML(matches[1]))
+		}
+	}
+	flush()
+	return fields, strings.TrimSpace(strings.Join(block[closing+1:], "\n"))
+}
+
+// frontmatterValue decodes a scalar or [a, b] flow list value.
+func frontmatterValue(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		var items []string
+		for _, item := range strings.Split(raw[1:len(raw)-1], ",") {
+			if item = unquoteYAML(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ", ")
+	}
+	return unquoteYAML(raw)
+}
+
+// unquoteYAML is yamlScalar keeping a malformed value as written.
+func unquoteYAML(s string) string {
+	s = strings.TrimSpace(s)
+	if v, err := yamlScalar(s); err == nil {
+		return v
+	}
+	return s
+}
+
+// parseFrontmatterTask parses one frontmatter task block. A non-empty body
+// is the description, taking precedence over a description key.
+func parseFrontmatterTask(block []string) Task {
+	fields, body := frontmatterFields(block)
+	var t Task
+	for _, f := range fields {
+		switch f[0] {
+		case "id":
+			t.ID = f[1]
+		case "title":
+			t.Title = f[1]
+		default:
+			t.setField(f[0], f[1])
+		}
+	}
+	if body != "" {
+		t.Description = body
+	}
+	t.RawText = strings.Join(block, "\n") + "\n"
+	return t
+}
+
+// frontmatterFieldLines converts a frontmatter task's raw text to the
+// "- **field**: value" lines fieldLines returns, so tasks moved between
+// files keep their fields.
+func frontmatterFieldLines(raw string, drop []string) string {
+	fields, body := frontmatterFields(strings.Split(raw, "\n"))
+	if body != "" {
+		fields = append(fields, [2]string{"description", body})
+	}
+	var out string
+	for _, f := range fields {
+		if f[0] == "id" || f[0] == "title" || f[1] == "" || slices.Contains(drop, f[0]) {
+			continue
+		}
+		out += formatField(f[0], f[1])
+	}
+	return out
+}
+
+// frontmatterEntry formats a task as a frontmatter block, with the same
+// fields as taskEntry and the description as the body.
+func frontmatterEntry(t Task) string {
+	entry := frontmatterDelim + "\n"
+	entry += "id: " + quoteYAML(t.ID) + "\n"
+	entry += "title: " + strconv.Quote(t.Title) + "\n"
+	for _, f := range entryFields(t) {
+		if f[1] != "" && f[0] != "description" {
+			entry += frontmatterLine(f[0], f[1]) + "\n"
+		}
+	}
+	entry += frontmatterDelim + "\n"
+	if t.Description != "" {
+		entry += t.Description + "\n"
+	}
+	return entry
+}
+
+// frontmatterLine formats a "key: value" line.
+func frontmatterLine(key, value string) string {
+	if frontmatterListFields[key] {
+		var items []string
+		for _, item := range parseList(value) {
+			items = append(items, quoteYAML(item))
+		}
+		return key + ": [" + strings.Join(items, ", ") + "]"
+	}
+	return key + ": " + quoteYAML(value)
+}
+
+// plainScalarRe matches values that need no quoting.
+var plainScalarRe = regexp.MustCompile(`^[A-Za-z0-9_./@+-]+( [A-Za-z0-9_./@+-]+)*$`)
+
+// quoteYAML quotes value unless it is safe as a plain scalar.
+func quoteYAML(value string) string {
+	if plainScalarRe.MatchString(value) && !strings.HasPrefix(value, "-") {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// setFrontmatterField is setField for a frontmatter block: it replaces the
+// key's line, and any list items under it, or adds one before the closing
+// delimiter. An empty value removes it. The description is the body.
+func setFrontmatterField(block []string, field, value string) []string {
+	closing := frontmatterClose(block, 0)
+	field = strings.ToLower(field)
+	if field == "description" {
+		out := append([]string{}, block[:closing+1]...)
+		if value != "" {
+			out = append(out, strings.Split(value, "\n")...)
+		}
+		return out
+	}
+
+	var out []string
+	replaced, dropping := false, false
+	for i, line := range block[:closing] {
+		if i > 0 {
+			if dropping && frontmatterItemRe.MatchString(line) {
+				continue
+			}
+			dropping = false
+			if matches := frontmatterKeyRe.FindStringSubmatch(line); matches != nil && strings.EqualFold(matches[1], field) {
+				if value != "" && !replaced {
+					out = append(out, frontmatterLine(field, value))
+				}
+				replaced, dropping = true, true
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+	if !replaced && value != "" {
+		out = append(out, frontmatterLine(field, value))
+	}
+	return append(out, block[closing:]...)
+}
@@ -0,0 +1,143 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const frontmatterBacklog = `# Backlog
+
+---
+id: T-001
+title: "My Task"
+repo: myrepo
+tags: [ui, "docs"]
+links:
+  - https://example.com/a
+  - https://example.com/b
+---
+Free-form *markdown* description.
+
+- a list item
+- **not**: a field
+
+---
+id: T-002
+title: 'It''s second'
+priority: high # urgent
+---
+
+### [T-003] Bullet task
+- **repo**: other
+`
+
+func TestParseTasks_Frontmatter(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{"backlog.md": frontmatterBacklog})
+	list, err := mgr.ListBacklog()
+	if err != nil {
+		t.Fatalf("ListBacklog: %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("expected 3 tasks, got %+v", list)
+	}
+
+	first := list[0]
+	if first.ID != "T-001" || first.Title != "My Task" || first.Repo != "myrepo" {
+		t.Errorf("unexpected first task: %+v", first)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"ui", "docs"}) {
+		t.Errorf("tags = %v", first.Tags)
+	}
+	if !reflect.DeepEqual(first.Links, []string{"https://example.com/a", "https://example.com/b"}) {
+		t.Errorf("links = %v", first.Links)
+	}
+	wantDesc := "Free-form *markdown* description.\n\n- a list item\n- **not**: a field"
+	if first.Description != wantDesc {
+		t.Errorf("description = %q, want %q", first.Description, wantDesc)
+	}
+
+	if list[1].Title != "It's second" || list[1].Priority != "high" || list[1].Description != "" {
+		t.Errorf("unexpected second task: %+v", list[1])
+	}
+	if list[2].ID != "T-003" || list[2].Repo != "other" {
+		t.Errorf("unexpected bullet task: %+v", list[2])
+	}
+}
+
+func TestParseTasks_HorizontalRuleIsNotFrontmatter(t *testing.T) {
+	got := parseTasks("### [T-001] Notes\n- **repo**: alpha\n\n---\n\nSome prose.\n")
+	if len(got) != 1 || got[0].ID != "T-001" || got[0].Repo != "alpha" {
+		t.Fatalf("unexpected tasks: %+v", got)
+	}
+}
+
+func TestFrontmatterTaskLifecycle(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{"backlog.md": frontmatterBacklog})
+
+	if err := mgr.UpdateTask("T-001", map[string]string{"assigned": "alice", "links": "https://example.com/c"}); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	got, err := mgr.GetTask("T-001")
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.Assigned != "alice" || !reflect.DeepEqual(got.Links, []string{"https://example.com/c"}) {
+		t.Errorf("updates not applied: %+v", got)
+	}
+	if !strings.HasPrefix(got.Description, "Free-form") {
+		t.Errorf("description lost: %q", got.Description)
+	}
+
+	if _, err := mgr.MoveTaskTo("T-003", 1); err != nil {
+		t.Fatalf("MoveTaskTo: %v", err)
+	}
+	list, _ := mgr.ListBacklog()
+	if len(list) != 3 || list[0].ID != "T-003" || list[1].ID != "T-001" {
+		t.Fatalf("unexpected order after reorder: %+v", list)
+	}
+
+	if err := mgr.StartTask("T-001"); err != nil {
+		t.Fatalf("StartTask: %v", err)
+	}
+	if err := mgr.PauseTask("T-001", ""); err != nil {
+		t.Fatalf("PauseTask: %v", err)
+	}
+	paused, _ := mgr.ListPaused()
+	if len(paused) != 1 || paused[0].Repo != "myrepo" || paused[0].Assigned != "alice" {
+		t.Fatalf("unexpected paused tasks: %+v", paused)
+	}
+	list, _ = mgr.ListBacklog()
+	if len(list) != 2 || list[0].ID != "T-003" || list[1].ID != "T-002" {
+		t.Errorf("unexpected backlog after start: %+v", list)
+	}
+}
+
+func TestAddToBacklog_FollowsFirstTaskFormat(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{"backlog.md": frontmatterBacklog})
+	id, err := mgr.AddToBacklog(Task{Title: `Say "hi"`, Repo: "myrepo", Tags: []string{"a", "b"}, Description: "Body text."})
+	if err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(mgr.tasksDir, "backlog.md"))
+	want := "---\nid: " + id + "\ntitle: \"Say \\\"hi\\\"\"\nrepo: myrepo\ntags: [a, b]\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected frontmatter entry containing %q, got:\n%s", want, data)
+	}
+	got, err := mgr.GetTask(id)
+	if err != nil || got.Title != `Say "hi"` || got.Description != "Body text." {
+		t.Errorf("round trip failed: %+v, %v", got, err)
+	}
+
+	bullet := newTestManager(t, map[string]string{"backlog.md": "# Backlog\n\n### [T-001] First\n"})
+	id, err = bullet.AddToBacklog(Task{Title: "Second"})
+	if err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(bullet.tasksDir, "backlog.md"))
+	if !strings.Contains(string(data), "### ["+id+"] Second") {
+		t.Errorf("expected bullet entry, got:\n%s", data)
+	}
+}
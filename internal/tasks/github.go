@@ -0,0 +1,84 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// githubAPIURL is the GitHub REST API base, overridden in tests.
+var githubAPIURL = "https://api.github.com"
+
+// githubIssue is the subset of the GitHub issues API response that is used.
+type githubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	HTMLURL     string          `json:"html_url"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+// FetchGitHubIssues returns the open issues in owner/repo carrying label
+// (all open issues if label is empty) as tasks with IDs of the form GH-<n>.
+// Pull requests are skipped. token, if non-empty, is sent as a bearer token.
+func FetchGitHubIssues(owner, repo, label, token string) ([]Task, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	var tasks []Task
+	for page := 1; ; page++ {
+		q := url.Values{}
+		q.Set("state", "open")
+		q.Set("per_page", "100")
+		q.Set("page", fmt.Sprint(page))
+		if label != "" {
+			q.Set("labels", label)
+		}
+		endpoint := fmt.Sprintf("%s/repos/%s/%s/issues?%s", githubAPIURL, url.PathEscape(owner), url.PathEscape(repo), q.Encode())
+
+		req, err := http.NewRequest("GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetching issues: %w", err)
+		}
+		var issues []githubIssue
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching issues: GitHub returned %s", resp.Status)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&issues)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+				continue
+			}
+			tasks = append(tasks, Task{
+				ID:          fmt.Sprintf("GH-%d", issue.Number),
+				Title:       strings.TrimSpace(issue.Title),
+				Description: singleLine(issue.Body),
+			})
+		}
+		if len(issues) < 100 {
+			return tasks, nil
+		}
+	}
+}
+
+// singleLine collapses whitespace so text fits in a single task field line.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
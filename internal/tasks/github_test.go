@@ -0,0 +1,60 @@
+package tasks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFetchGitHubIssues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("labels"); got != "orchestrator" {
+			t.Errorf("expected labels=orchestrator, got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		fmt.Fprint(w, `[
+			{"number": 12, "title": "Fix login ", "body": "Steps:\n1. open\n2. fail"},
+			{"number": 13, "title": "A PR", "pull_request": {"url": "x"}}
+		]`)
+	}))
+	defer srv.Close()
+
+	old := githubAPIURL
+	githubAPIURL = srv.URL
+	defer func() { githubAPIURL = old }()
+
+	issues, err := FetchGitHubIssues("acme", "widgets", "orchestrator", "tok")
+	if err != nil {
+		t.Fatalf("FetchGitHubIssues: %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected pull requests to be skipped, got %+v", issues)
+	}
+	want := Task{ID: "GH-12", Title: "Fix login", Description: "Steps: 1. open 2. fail"}
+	if !reflect.DeepEqual(issues[0], want) {
+		t.Errorf("expected %+v, got %+v", want, issues[0])
+	}
+}
+
+func TestFetchGitHubIssues_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	old := githubAPIURL
+	githubAPIURL = srv.URL
+	defer func() { githubAPIURL = old }()
+
+	if _, err := FetchGitHubIssues("acme", "widgets", "", ""); err == nil {
+		t.Error("expected error for 401 response")
+	}
+}
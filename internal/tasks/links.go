@@ -0,0 +1,46 @@
+package tasks
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CheckLink reports whether s can be stored in a task's links field: an
+// absolute http or https URL without commas or whitespace, which would break
+// the comma-separated list.
+func CheckLink(s string) error {
+	if strings.ContainsAny(s, ", \t\r\n") {
+		return fmt.Errorf("link %q must not contain commas or whitespace", s)
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("link %q must be an http or https URL", s)
+	}
+	return nil
+}
+
+// AddLink appends link to a task's links field, unless the task already has
+// it. Completed tasks cannot be changed.
+func (m *Manager) AddLink(id, link string) error {
+	if err := CheckLink(link); err != nil {
+		return err
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	t, _, err := m.findTask(id)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(t.Links, link) {
+		return nil
+	}
+	links := strings.Join(append(t.Links, link), ", ")
+	return m.updateTask(id, []string{"links"}, map[string]string{"links": links})
+}
@@ -0,0 +1,52 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddLink(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+### [task-001] Fix login
+- **repo**: alpha
+- **links**: https://github.com/acme/alpha/issues/42
+`,
+	})
+
+	if err := mgr.AddLink("task-001", "https://github.com/acme/alpha/pull/43"); err != nil {
+		t.Fatalf("AddLink: %v", err)
+	}
+	if err := mgr.AddLink("task-001", "https://github.com/acme/alpha/pull/43"); err != nil {
+		t.Fatalf("AddLink duplicate: %v", err)
+	}
+	for _, bad := range []string{"not a url", "ftp://example.com/x", "https://a.example/x,y", "/relative"} {
+		if err := mgr.AddLink("task-001", bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+
+	want := []string{"https://github.com/acme/alpha/issues/42", "https://github.com/acme/alpha/pull/43"}
+	got, err := mgr.GetTask("task-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Links, want) {
+		t.Errorf("Links = %v, want %v", got.Links, want)
+	}
+
+	// Links move with the task.
+	if err := mgr.StartTask("task-001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.CompleteTask("task-001"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = mgr.GetTask("task-001"); !reflect.DeepEqual(got.Links, want) {
+		t.Errorf("after completing, Links = %v", got.Links)
+	}
+	if err := mgr.AddLink("task-001", "https://example.com/x"); err == nil {
+		t.Error("expected completed task to reject new links")
+	}
+}
@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFileName is the advisory lock file shared by all processes that modify
+// a tasks directory.
+const lockFileName = ".lock"
+
+// lock serializes a read-modify-write cycle on the task files. It takes the
+// Manager's mutex, which orders goroutines sharing this Manager, and then an
+// exclusive flock on tasks/.lock, which orders separate Managers and separate
+// orchestrator processes. The returned func releases both. flock(2) makes
+// locking, and so the tasks package, Unix-only.
+func (m *Manager) lock() (unlock func(), err error) {
+	m.mu.Lock()
+
+	f, err := os.OpenFile(filepath.Join(m.tasksDir, lockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("opening task lock: %w", err)
+	}
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX); err != nil {
+		f.Close()
+		m.mu.Unlock()
+		return nil, fmt.Errorf("locking tasks: %w", err)
+	}
+
+	return func() {
+		unix.Flock(int(f.Fd()), unix.LOCK_UN)
+		f.Close()
+		m.mu.Unlock()
+	}, nil
+}
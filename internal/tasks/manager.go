@@ -5,26 +5,89 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Task represents a parsed task from the markdown files.
 type Task struct {
-	ID          string
-	Title       string
-	Repo        string
-	Type        string
-	Priority    string
-	Assigned    string
-	Description string
-	Branch      string
-	RawText     string
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	State       string    `json:"state,omitempty"`
+	Repo        string    `json:"repo,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	Priority    string    `json:"priority,omitempty"`
+	Assigned    string    `json:"assigned,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Links       []string  `json:"links,omitempty"`
+	Parent      string    `json:"parent,omitempty"`
+	SplitInto   []string  `json:"split_into,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	DueDate     string    `json:"due_date,omitempty"`
+	Created     string    `json:"created,omitempty"`
+	Completed   string    `json:"completed,omitempty"`
+	StartedAt   time.Time `json:"started_at,omitzero"`
+	StartedBy   string    `json:"started_by,omitempty"`
+	RawText     string    `json:"-"`
+}
+
+// HasTag reports whether the task carries tag.
+func (t Task) HasTag(tag string) bool {
+	for _, tt := range t.Tags {
+		if tt == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseList splits a comma-separated field such as tags or links.
+func parseList(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// dueDateLayout is the format of the due field in task files.
+const dueDateLayout = "2006-01-02"
+
+// IsOverdue reports whether the task's due date has fully passed as of now.
+// Tasks without a parseable due date are never overdue.
+func (t Task) IsOverdue(now time.Time) bool {
+	if t.DueDate == "" {
+		return false
+	}
+	due, err := time.ParseInLocation(dueDateLayout, t.DueDate, now.Location())
+	if err != nil {
+		return false
+	}
+	return now.After(due.AddDate(0, 0, 1))
 }
 
 // Manager handles task lifecycle operations.
+//
+// Methods that modify task files (StartTask, CompleteTask, PauseTask,
+// ResumeTask, AddToBacklog, ReplaceTaskBlock, UpdateTaskField, AddLink,
+// MigrateToSharded) hold a lock for their whole read-modify-write cycle: a
+// mutex for goroutines sharing the Manager and an advisory flock on
+// tasks/.lock for other Managers and processes. Read-only methods do not
+// lock; files are replaced atomically, but a reader racing a move may
+// briefly see the task in both files.
 type Manager struct {
+	mu       sync.Mutex
 	tasksDir string
+	// AllowCustomPrefix lets AddToBacklog accept IDs without a recognized
+	// source prefix (GH-, LI-, T-).
+	AllowCustomPrefix bool
 }
 
 // NewManager creates a task manager for the given orchestrator root.
@@ -34,21 +97,67 @@ func NewManager(rootPath string) *Manager {
 	}
 }
 
-var taskHeaderRe = regexp.MustCompile(`###\s+\[([^\]]+)\]\s+(.+)`)
-var fieldRe = regexp.MustCompile(`-\s+\*\*(\w+)\*\*:\s+(.+)`)
+var taskHeaderRe = regexp.MustCompile(`^###\s+\[([^\]]+)\]\s+(.+)`)
+var fieldRe = regexp.MustCompile(`-\s+\*\*(\w+)\*\*:\s*(.*)`)
+
+// continuationIndent marks a line that continues the previous field's value,
+// so long descriptions can wrap onto indented lines below the field line. A
+// blank line between indented lines starts a new paragraph.
+const continuationIndent = "    "
+
+// isContinuation reports whether line continues the previous field's value.
+func isContinuation(line string) bool {
+	return strings.HasPrefix(line, continuationIndent) && strings.TrimSpace(line) != ""
+}
+
+// formatField formats a "- **key**: value" line, writing any further lines
+// of value as indented continuation lines.
+func formatField(key, value string) string {
+	lines := strings.Split(value, "\n")
+	out := fmt.Sprintf("- **%s**: %s\n", key, lines[0])
+	for _, line := range lines[1:] {
+		if line != "" {
+			line = continuationIndent + line
+		}
+		out += line + "\n"
+	}
+	return out
+}
 
-// ParseTasks reads a task markdown file and returns parsed tasks.
+// ParseTasks reads a task markdown file and returns parsed tasks. Each task
+// may use either the "### [ID] Title" format or YAML frontmatter (see
+// frontmatterDelim).
 func (m *Manager) ParseTasks(filename string) ([]Task, error) {
 	path := filepath.Join(m.tasksDir, filename)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return parseTasks(string(data)), nil
+}
 
+// parseTasks parses task markdown content. Indented lines after a field (see
+// continuationIndent) are joined onto its value with newlines, the
+// indentation stripped; blank lines between them become paragraph breaks.
+func parseTasks(content string) []Task {
 	var tasks []Task
 	var current *Task
+	var field, value string
+	blanks := 0
 
-	for _, line := range strings.Split(string(data), "\n") {
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if isFrontmatterStart(lines, i) {
+			if current != nil {
+				tasks = append(tasks, *current)
+				current = nil
+			}
+			end := frontmatterBlockEnd(lines, i)
+			tasks = append(tasks, parseFrontmatterTask(lines[i:end]))
+			i = end - 1
+			continue
+		}
 		if matches := taskHeaderRe.FindStringSubmatch(line); matches != nil {
 			if current != nil {
 				tasks = append(tasks, *current)
@@ -57,26 +166,28 @@ func (m *Manager) ParseTasks(filename string) ([]Task, error) {
 				ID:    matches[1],
 				Title: strings.TrimSpace(matches[2]),
 			}
+			field, blanks = "", 0
 			continue
 		}
 
 		if current != nil {
-			if matches := fieldRe.FindStringSubmatch(line); matches != nil {
-				key := strings.ToLower(matches[1])
-				val := strings.TrimSpace(matches[2])
-				switch key {
-				case "repo":
-					current.Repo = val
-				case "type":
-					current.Type = val
-				case "priority":
-					current.Priority = val
-				case "assigned":
-					current.Assigned = val
-				case "description":
-					current.Description = val
-				case "branch":
-					current.Branch = val
+			switch {
+			case field != "" && isContinuation(line):
+				text := strings.TrimSpace(line)
+				if value == "" {
+					value = text
+				} else {
+					value += strings.Repeat("\n", blanks+1) + text
+				}
+				blanks = 0
+				current.setField(field, value)
+			case strings.TrimSpace(line) == "":
+				blanks++
+			default:
+				field, blanks = "", 0
+				if matches := fieldRe.FindStringSubmatch(line); matches != nil {
+					field, value = strings.ToLower(matches[1]), strings.TrimSpace(matches[2])
+					current.setField(field, value)
 				}
 			}
 			current.RawText += line + "\n"
@@ -87,12 +198,64 @@ func (m *Manager) ParseTasks(filename string) ([]Task, error) {
 		tasks = append(tasks, *current)
 	}
 
-	return tasks, nil
+	return tasks
 }
 
-// ListBacklog returns all tasks in the backlog.
+// setField stores a parsed field value on the task. Unknown keys are ignored;
+// they remain in RawText.
+func (t *Task) setField(key, val string) {
+	switch key {
+	case "repo":
+		t.Repo = val
+	case "type":
+		t.Type = val
+	case "priority":
+		t.Priority = val
+	case "assigned":
+		t.Assigned = val
+	case "tags":
+		t.Tags = parseList(val)
+	case "links":
+		t.Links = parseList(val)
+	case "parent":
+		t.Parent = val
+	case "split_into":
+		t.SplitInto = parseList(val)
+	case "description":
+		t.Description = val
+	case "branch":
+		t.Branch = val
+	case "due":
+		t.DueDate = val
+	case "created":
+		t.Created = val
+	case "completed":
+		t.Completed = val
+	case "started_at":
+		if ts, err := time.Parse(time.RFC3339, val); err == nil {
+			t.StartedAt = ts
+		}
+	case "started_by":
+		t.StartedBy = val
+	}
+}
+
+// ListBacklog returns all tasks in the backlog, across every shard when the
+// backlog is sharded.
 func (m *Manager) ListBacklog() ([]Task, error) {
-	return m.ParseTasks("backlog.md")
+	files, err := m.backlogFiles()
+	if err != nil {
+		return nil, err
+	}
+	var all []Task
+	for _, name := range files {
+		list, err := m.ParseTasks(name)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list...)
+	}
+	return all, nil
 }
 
 // ListActive returns all active tasks.
@@ -100,22 +263,48 @@ func (m *Manager) ListActive() ([]Task, error) {
 	return m.ParseTasks("active.md")
 }
 
-// StartTask moves a task from backlog to active by ID.
-func (m *Manager) StartTask(id string) error {
-	backlogTasks, err := m.ListBacklog()
-	if err != nil {
-		return fmt.Errorf("reading backlog: %w", err)
+// ListPaused returns all paused tasks. A missing paused.md means no tasks are paused.
+func (m *Manager) ListPaused() ([]Task, error) {
+	tasks, err := m.ParseTasks("paused.md")
+	if os.IsNotExist(err) {
+		return nil, nil
 	}
+	return tasks, err
+}
 
-	var found *Task
-	for i := range backlogTasks {
-		if backlogTasks[i].ID == id {
-			found = &backlogTasks[i]
-			break
+// ListCompleted returns all completed tasks.
+func (m *Manager) ListCompleted() ([]Task, error) {
+	return m.ParseTasks("completed.md")
+}
+
+// OverdueTasks returns backlog and active tasks whose due date has passed.
+func (m *Manager) OverdueTasks() ([]Task, error) {
+	var overdue []Task
+	now := time.Now()
+	for _, list := range []func() ([]Task, error){m.ListActive, m.ListBacklog} {
+		tasks, err := list()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			if t.IsOverdue(now) {
+				overdue = append(overdue, t)
+			}
 		}
 	}
+	return overdue, nil
+}
 
-	if found == nil {
+// StartTask moves a task from backlog to active by ID.
+func (m *Manager) StartTask(id string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	found, filename, err := m.findTask(id)
+	if err != nil || !isBacklogFile(filename) {
 		return fmt.Errorf("task %s not found in backlog", id)
 	}
 
@@ -127,18 +316,18 @@ func (m *Manager) StartTask(id string) error {
 	}
 	defer f.Close()
 
+	// Carry every field over; the start fields are replaced below.
 	entry := fmt.Sprintf("\n### [%s] %s\n", found.ID, found.Title)
-	if found.Repo != "" {
-		entry += fmt.Sprintf("- **repo**: %s\n", found.Repo)
+	entry += fieldLines(found.RawText, "started", "started_at", "started_by")
+	if found.Assigned == "" {
+		entry += "- **assigned**: in-progress\n"
 	}
-	if found.Type != "" {
-		entry += fmt.Sprintf("- **type**: %s\n", found.Type)
+	now := time.Now()
+	entry += fmt.Sprintf("- **started**: %s\n", now.Format("2006-01-02"))
+	entry += fmt.Sprintf("- **started_at**: %s\n", now.UTC().Format(time.RFC3339))
+	if host, err := os.Hostname(); err == nil {
+		entry += fmt.Sprintf("- **started_by**: %s\n", host)
 	}
-	entry += fmt.Sprintf("- **assigned**: in-progress\n")
-	if found.Description != "" {
-		entry += fmt.Sprintf("- **description**: %s\n", found.Description)
-	}
-	entry += fmt.Sprintf("- **started**: %s\n", time.Now().Format("2006-01-02"))
 
 	_, err = f.WriteString(entry)
 	if err != nil {
@@ -146,25 +335,19 @@ func (m *Manager) StartTask(id string) error {
 	}
 
 	// Remove from backlog by rewriting without the task
-	return m.removeTaskFromFile("backlog.md", id)
+	return m.removeTaskFromFile(filename, id)
 }
 
 // CompleteTask moves a task from active to completed.
 func (m *Manager) CompleteTask(id string) error {
-	activeTasks, err := m.ListActive()
+	unlock, err := m.lock()
 	if err != nil {
-		return fmt.Errorf("reading active: %w", err)
-	}
-
-	var found *Task
-	for i := range activeTasks {
-		if activeTasks[i].ID == id {
-			found = &activeTasks[i]
-			break
-		}
+		return err
 	}
+	defer unlock()
 
-	if found == nil {
+	found, filename, err := m.findTask(id)
+	if err != nil || filename != "active.md" {
 		return fmt.Errorf("task %s not found in active tasks", id)
 	}
 
@@ -177,23 +360,633 @@ func (m *Manager) CompleteTask(id string) error {
 	defer f.Close()
 
 	entry := fmt.Sprintf("\n### [%s] %s\n", found.ID, found.Title)
-	if found.Repo != "" {
-		entry += fmt.Sprintf("- **repo**: %s\n", found.Repo)
+	entry += fieldLines(found.RawText, "completed")
+	entry += fmt.Sprintf("- **completed**: %s\n", time.Now().Format("2006-01-02"))
+
+	_, err = f.WriteString(entry)
+	if err != nil {
+		return err
 	}
-	if found.Type != "" {
-		entry += fmt.Sprintf("- **type**: %s\n", found.Type)
+
+	return m.removeTaskFromFile("active.md", id)
+}
+
+// PauseTask moves a task from active to paused, recording when and why.
+func (m *Manager) PauseTask(id, reason string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
 	}
-	entry += fmt.Sprintf("- **completed**: %s\n", time.Now().Format("2006-01-02"))
-	if found.Description != "" {
-		entry += fmt.Sprintf("- **description**: %s\n", found.Description)
+	defer unlock()
+
+	found, filename, err := m.findTask(id)
+	if err != nil || filename != "active.md" {
+		return fmt.Errorf("task %s not found in active tasks", id)
+	}
+
+	entry := fmt.Sprintf("\n### [%s] %s\n", found.ID, found.Title)
+	entry += fieldLines(found.RawText, "paused_at", "pause_reason")
+	entry += fmt.Sprintf("- **paused_at**: %s\n", time.Now().UTC().Format(time.RFC3339))
+	if reason != "" {
+		entry += fmt.Sprintf("- **pause_reason**: %s\n", reason)
+	}
+
+	if err := m.appendToFile("paused.md", "# Paused Tasks\n\nIn-progress work that has been parked. Resume to move back to `active.md`.\n", entry); err != nil {
+		return err
+	}
+	return m.removeTaskFromFile("active.md", id)
+}
+
+// ResumeTask moves a task from paused back to active, dropping the pause fields.
+func (m *Manager) ResumeTask(id string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	found, filename, err := m.findTask(id)
+	if err != nil || filename != "paused.md" {
+		return fmt.Errorf("task %s not found in paused tasks", id)
+	}
+
+	entry := fmt.Sprintf("\n### [%s] %s\n", found.ID, found.Title)
+	entry += fieldLines(found.RawText, "paused_at", "pause_reason")
+
+	if err := m.appendToFile("active.md", "", entry); err != nil {
+		return err
+	}
+	return m.removeTaskFromFile("paused.md", id)
+}
+
+// AddToBacklog adds a new task to backlog.md, or to the shard chosen by
+// backlogShard when the backlog is sharded. It is filed under the
+// "## <Priority> Priority" section matching the task's priority when one
+// exists, and appended to the end of the file otherwise. The ID must not
+// already be used by a task in any state file, and must have a recognized
+// source prefix unless AllowCustomPrefix is set. An empty ID is assigned the
+// next T-NNN ID. Created defaults to today. It returns the task's ID.
+func (m *Manager) AddToBacklog(t Task) (string, error) {
+	if t.Title == "" {
+		return "", fmt.Errorf("task must have a title")
+	}
+	if strings.ContainsAny(t.Title, "\r\n") {
+		return "", fmt.Errorf("task title must be a single line")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return m.addToBacklog(t)
+}
+
+// addToBacklog implements AddToBacklog. The caller must hold the lock.
+func (m *Manager) addToBacklog(t Task) (string, error) {
+	if t.Created == "" {
+		t.Created = time.Now().Format(dueDateLayout)
+	}
+
+	var err error
+	if t.ID == "" {
+		if t.ID, err = m.nextTaskID(); err != nil {
+			return "", err
+		}
+	} else if _, _, err := m.findTask(t.ID); err == nil {
+		return "", fmt.Errorf("task %s already exists", t.ID)
+	} else if !m.AllowCustomPrefix && taskSource(t.ID) == SourceUnknown {
+		return "", fmt.Errorf("task ID %s has no recognized source prefix (GH-N, LI-N, or T-N)", t.ID)
+	}
+
+	filename, header := legacyBacklog, "# Backlog\n"
+	if m.Sharded() {
+		filename = m.backlogShard(t)
+		header = fmt.Sprintf("# Backlog: %s\n", strings.TrimSuffix(filepath.Base(filename), ".md"))
+	}
+	path := filepath.Join(m.tasksDir, filename)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data = []byte(header)
+	} else if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	format := taskEntry
+	if firstTaskIsFrontmatter(lines) {
+		format = frontmatterEntry
+	}
+	entry := strings.Split(strings.TrimRight(format(t), "\n"), "\n")
+	// A frontmatter description is written unindented, so it must not
+	// contain lines that read back as another task.
+	if parsed := parseTasks(strings.Join(entry, "\n")); len(parsed) != 1 {
+		return "", fmt.Errorf("task %s: description would start another task", t.ID)
+	}
+	at := len(lines)
+	if t.Priority != "" {
+		if i := prioritySectionEnd(lines, t.Priority); i >= 0 {
+			at = i
+		}
+	}
+
+	var result []string
+	result = append(result, lines[:at]...)
+	result = append(result, "")
+	result = append(result, entry...)
+	if at < len(lines) {
+		result = append(result, "")
+	}
+	result = append(result, lines[at:]...)
+
+	if err := writeFileAtomic(path, []byte(strings.Join(result, "\n")+"\n")); err != nil {
+		return "", err
+	}
+	return t.ID, nil
+}
+
+// generatedIDRe matches IDs assigned by AddToBacklog.
+var generatedIDRe = regexp.MustCompile(`^T-(\d+)$`)
+
+// nextTaskID returns T-NNN, one past the highest T- ID in any state file.
+func (m *Manager) nextTaskID() (string, error) {
+	files, err := m.stateFiles()
+	if err != nil {
+		return "", err
+	}
+	highest := 0
+	for _, name := range files {
+		list, err := m.ParseTasks(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		for _, t := range list {
+			if matches := generatedIDRe.FindStringSubmatch(t.ID); matches != nil {
+				if n, _ := strconv.Atoi(matches[1]); n > highest {
+					highest = n
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("T-%03d", highest+1), nil
+}
+
+// firstTaskIsFrontmatter reports whether the first task in lines uses YAML
+// frontmatter, which AddToBacklog then follows for new tasks.
+func firstTaskIsFrontmatter(lines []string) bool {
+	for i := range lines {
+		if _, ok := taskStart(lines, i); ok {
+			return isFrontmatterStart(lines, i)
+		}
+	}
+	return false
+}
+
+// taskEntry formats a task as a markdown block with its non-empty fields.
+func taskEntry(t Task) string {
+	entry := fmt.Sprintf("### [%s] %s\n", t.ID, t.Title)
+	for _, f := range entryFields(t) {
+		if f[1] != "" {
+			entry += formatField(f[0], f[1])
+		}
+	}
+	return entry
+}
+
+// entryFields lists a task's fields in the order new entries write them.
+func entryFields(t Task) [][2]string {
+	return [][2]string{
+		{"repo", t.Repo},
+		{"type", t.Type},
+		{"priority", t.Priority},
+		{"assigned", t.Assigned},
+		{"tags", strings.Join(t.Tags, ", ")},
+		{"links", strings.Join(t.Links, ", ")},
+		{"parent", t.Parent},
+		{"split_into", strings.Join(t.SplitInto, ", ")},
+		{"description", t.Description},
+		{"branch", t.Branch},
+		{"due", t.DueDate},
+		{"created", t.Created},
+		{"started_at", formatStartedAt(t.StartedAt)},
+		{"started_by", t.StartedBy},
+		{"completed", t.Completed},
+	}
+}
+
+func formatStartedAt(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// prioritySectionEnd returns the index just past the last non-blank line of
+// the "## <priority> Priority" section, or -1 if there is no such section.
+func prioritySectionEnd(lines []string, priority string) int {
+	heading := strings.ToLower("## " + priority + " priority")
+	start := -1
+	for i, line := range lines {
+		if strings.ToLower(strings.TrimSpace(line)) == heading {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return -1
 	}
 
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			break
+		}
+		if strings.TrimSpace(lines[i]) != "" {
+			end = i + 1
+		}
+	}
+	return end
+}
+
+// appendToFile appends an entry to a task file, creating it with header if it
+// does not exist yet.
+func (m *Manager) appendToFile(filename, header, entry string) error {
+	path := filepath.Join(m.tasksDir, filename)
+	if _, err := os.Stat(path); os.IsNotExist(err) && header != "" {
+		entry = header + entry
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
 	_, err = f.WriteString(entry)
+	return err
+}
+
+// fieldLines returns the "- **field**: value" lines, with their continuation
+// lines, from a task's raw text, omitting the named fields. A frontmatter
+// task's fields and body are converted to that format.
+func fieldLines(raw string, drop ...string) string {
+	if lines := strings.Split(raw, "\n"); isFrontmatterStart(lines, 0) {
+		return frontmatterFieldLines(raw, drop)
+	}
+	var out string
+	inField, keep := false, false
+	blanks := 0
+	for _, line := range strings.Split(raw, "\n") {
+		if inField && isContinuation(line) {
+			if keep {
+				out += strings.Repeat("\n", blanks) + line + "\n"
+			}
+			blanks = 0
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			blanks++
+			continue
+		}
+		blanks = 0
+		matches := fieldRe.FindStringSubmatch(line)
+		inField = matches != nil
+		if !inField {
+			continue
+		}
+		keep = !slices.Contains(drop, strings.ToLower(matches[1]))
+		if keep {
+			out += line + "\n"
+		}
+	}
+	return out
+}
+
+// stateFiles lists the task files searched for cross-state operations, in
+// order: the backlog file or shards, then active, paused, and completed.
+func (m *Manager) stateFiles() ([]string, error) {
+	files, err := m.backlogFiles()
+	if err != nil {
+		return nil, err
+	}
+	return append(files, "active.md", "paused.md", "completed.md"), nil
+}
+
+// TaskBlock returns the markdown block for a task (its header and field lines)
+// and the file it was found in.
+func (m *Manager) TaskBlock(id string) (filename, block string, err error) {
+	files, err := m.stateFiles()
+	if err != nil {
+		return "", "", err
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(m.tasksDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+		lines := strings.Split(string(data), "\n")
+		if start, end, ok := taskBlockBounds(lines, id); ok {
+			return name, strings.Join(lines[start:end], "\n") + "\n", nil
+		}
+	}
+	return "", "", fmt.Errorf("task %s not found", id)
+}
+
+// ReplaceTaskBlock replaces a task's block in filename with newBlock. The new
+// block must parse as exactly one task with the same ID and a title. The file
+// is rewritten atomically.
+func (m *Manager) ReplaceTaskBlock(filename, id, newBlock string) error {
+	parsed := parseTasks(newBlock)
+	if len(parsed) != 1 {
+		return fmt.Errorf("edited text must contain exactly one task header, found %d", len(parsed))
+	}
+	if parsed[0].ID != id {
+		return fmt.Errorf("task ID cannot be changed (was %s, now %s)", id, parsed[0].ID)
+	}
+	if parsed[0].Title == "" {
+		return fmt.Errorf("task %s must have a title", id)
+	}
+
+	unlock, err := m.lock()
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	return m.removeTaskFromFile("active.md", id)
+	path := filepath.Join(m.tasksDir, filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	start, end, ok := taskBlockBounds(lines, id)
+	if !ok {
+		return fmt.Errorf("task %s not found in %s", id, filename)
+	}
+
+	replacement := strings.Split(strings.TrimRight(newBlock, "\n"), "\n")
+	var result []string
+	result = append(result, lines[:start]...)
+	result = append(result, replacement...)
+	result = append(result, lines[end:]...)
+
+	return writeFileAtomic(path, []byte(strings.Join(result, "\n")))
+}
+
+// fieldNameRe restricts field names to those fieldRe can parse back.
+var fieldNameRe = regexp.MustCompile(`^\w+$`)
+
+// UpdateTaskField sets a "- **field**: value" line on a task in backlog,
+// active, or paused, replacing any existing value. An empty value removes the
+// field. Completed tasks cannot be changed. The file is rewritten atomically.
+func (m *Manager) UpdateTaskField(id, field, value string) error {
+	return m.UpdateTask(id, map[string]string{field: value})
+}
+
+// UpdateTask applies several field updates to a task in one atomic rewrite,
+// with the same rules as UpdateTaskField. Values must be a single line,
+// except for description, which may span lines as long as none of them
+// reads back as another task. Nothing is written if any field name or value
+// is invalid.
+func (m *Manager) UpdateTask(id string, updates map[string]string) error {
+	fields := make([]string, 0, len(updates))
+	for field, value := range updates {
+		if !fieldNameRe.MatchString(field) {
+			return fmt.Errorf("invalid field name %q", field)
+		}
+		multiline := "\r\n"
+		if strings.EqualFold(field, "description") {
+			multiline = "\r"
+		}
+		if strings.ContainsAny(value, multiline) {
+			return fmt.Errorf("%s must be a single line", field)
+		}
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return m.updateTask(id, fields, updates)
+}
+
+// updateTask applies updates, in the order of fields, to a task. The caller
+// must hold the lock.
+func (m *Manager) updateTask(id string, fields []string, updates map[string]string) error {
+	_, filename, err := m.findTask(id)
+	if err != nil {
+		return err
+	}
+	if filename == "completed.md" {
+		return fmt.Errorf("task %s is completed and cannot be changed", id)
+	}
+
+	path := filepath.Join(m.tasksDir, filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	start, end, _ := taskBlockBounds(lines, id)
+
+	block := lines[start:end]
+	set := setField
+	if isFrontmatterStart(block, 0) {
+		set = setFrontmatterField
+	}
+	for _, field := range fields {
+		block = set(block, field, updates[field])
+	}
+	// A frontmatter description is written unindented, so a multi-line one
+	// must not contain lines that read back as another task.
+	if parsed := parseTasks(strings.Join(block, "\n")); len(parsed) != 1 || parsed[0].ID != id {
+		return fmt.Errorf("task %s: description would start another task", id)
+	}
+
+	var result []string
+	result = append(result, lines[:start]...)
+	result = append(result, block...)
+	result = append(result, lines[end:]...)
+	return writeFileAtomic(path, []byte(strings.Join(result, "\n")))
+}
+
+// setField returns block with field set to value, replacing the first
+// existing line for it and dropping duplicates, along with their
+// continuation lines. An empty value removes it.
+func setField(block []string, field, value string) []string {
+	formatted := strings.Split(strings.TrimSuffix(formatField(field, value), "\n"), "\n")
+	var out, blanks []string
+	replaced, dropping := false, false
+	for _, line := range block {
+		switch {
+		case dropping && isContinuation(line):
+			blanks = nil
+			continue
+		case strings.TrimSpace(line) == "":
+			blanks = append(blanks, line)
+			continue
+		}
+		out = append(out, blanks...)
+		blanks = nil
+		dropping = false
+		if matches := fieldRe.FindStringSubmatch(line); matches != nil && strings.EqualFold(matches[1], field) {
+			if value != "" && !replaced {
+				out = append(out, formatted...)
+			}
+			replaced, dropping = true, true
+			continue
+		}
+		out = append(out, line)
+	}
+	out = append(out, blanks...)
+	if !replaced && value != "" {
+		out = append(out, formatted...)
+	}
+	return out
+}
+
+// GetTask returns a task by ID from any state file, with State set.
+func (m *Manager) GetTask(id string) (Task, error) {
+	t, state, err := m.FindTask(id)
+	if err != nil {
+		return Task{}, err
+	}
+	t.State = state
+	return t, nil
+}
+
+// FindTask searches the backlog, active, paused, and completed files in that
+// order and returns the first task with the given ID, along with the state it
+// was found in: "backlog", "active", "paused", or "completed".
+func (m *Manager) FindTask(id string) (Task, string, error) {
+	t, filename, err := m.findTask(id)
+	if err != nil {
+		return Task{}, "", err
+	}
+	return *t, stateName(filename), nil
+}
+
+// findTask is FindTask returning the file the task was found in, relative to
+// tasks/, for callers that go on to rewrite it.
+func (m *Manager) findTask(id string) (*Task, string, error) {
+	filename, block, err := m.TaskBlock(id)
+	if err != nil {
+		return nil, "", err
+	}
+	parsed := parseTasks(block)
+	if len(parsed) != 1 {
+		return nil, "", fmt.Errorf("task %s: malformed block in %s", id, filename)
+	}
+	return &parsed[0], filename, nil
+}
+
+// stateName maps a file returned by TaskBlock to its task state.
+func stateName(filename string) string {
+	if isBacklogFile(filename) {
+		return "backlog"
+	}
+	return strings.TrimSuffix(filename, ".md")
+}
+
+// IsAssignedTo reports whether the task is assigned to assignee, ignoring
+// case and surrounding whitespace.
+func (t Task) IsAssignedTo(assignee string) bool {
+	return t.Assigned != "" && strings.EqualFold(strings.TrimSpace(t.Assigned), strings.TrimSpace(assignee))
+}
+
+// TasksByAssignee returns the active and backlog tasks assigned to assignee,
+// compared case-insensitively, with State set. Active tasks come first.
+func (m *Manager) TasksByAssignee(assignee string) ([]Task, error) {
+	if strings.TrimSpace(assignee) == "" {
+		return nil, fmt.Errorf("assignee is required")
+	}
+	var assigned []Task
+	for _, list := range []struct {
+		state string
+		load  func() ([]Task, error)
+	}{{"active", m.ListActive}, {"backlog", m.ListBacklog}} {
+		tasks, err := list.load()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			if t.IsAssignedTo(assignee) {
+				t.State = list.state
+				assigned = append(assigned, t)
+			}
+		}
+	}
+	return assigned, nil
+}
+
+// AssignTask records who owns a task in backlog, active, or paused.
+func (m *Manager) AssignTask(id, assignee string) error {
+	if strings.TrimSpace(assignee) == "" {
+		return fmt.Errorf("assignee is required")
+	}
+	return m.UpdateTaskField(id, "assigned", assignee)
+}
+
+// UnassignTask clears a task's assigned field.
+func (m *Manager) UnassignTask(id string) error {
+	return m.UpdateTaskField(id, "assigned", "")
+}
+
+// taskBlockBounds locates a task's header and field lines, or its
+// frontmatter and body. end is exclusive and excludes trailing blank lines.
+func taskBlockBounds(lines []string, id string) (start, end int, ok bool) {
+	start = -1
+	for i := range lines {
+		if taskID, ok := taskStart(lines, i); ok && taskID == id {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	return start, blockEnd(lines, start), true
+}
+
+// blockEnd returns the exclusive end of the task block whose header is at
+// lines[start], excluding trailing blank lines.
+func blockEnd(lines []string, start int) int {
+	if isFrontmatterStart(lines, start) {
+		return frontmatterBlockEnd(lines, start)
+	}
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if taskHeaderRe.MatchString(lines[i]) {
+			break
+		}
+		if strings.HasPrefix(trimmed, "- **") || isContinuation(lines[i]) {
+			end = i + 1
+			continue
+		}
+		if trimmed != "" {
+			break
+		}
+	}
+	return end
+}
+
+// writeFileAtomic writes data to a temp file and renames it over path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
 
 // removeTaskFromFile rewrites a task file without the specified task.
@@ -208,10 +1001,15 @@ func (m *Manager) removeTaskFromFile(filename, id string) error {
 	var result []string
 	skip := false
 
-	for _, line := range lines {
-		if matches := taskHeaderRe.FindStringSubmatch(line); matches != nil {
-			if matches[1] == id {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if taskID, ok := taskStart(lines, i); ok {
+			if taskID == id {
 				skip = true
+				// A frontmatter body is free-form, so skip it as a whole.
+				if isFrontmatterStart(lines, i) {
+					i = frontmatterBlockEnd(lines, i) - 1
+				}
 				continue
 			}
 			skip = false
@@ -219,7 +1017,7 @@ func (m *Manager) removeTaskFromFile(filename, id string) error {
 
 		if skip {
 			// Skip field lines that belong to the removed task
-			if strings.HasPrefix(strings.TrimSpace(line), "- **") {
+			if strings.HasPrefix(strings.TrimSpace(line), "- **") || isContinuation(line) {
 				continue
 			}
 			// Stop skipping on non-field, non-empty lines (next section header, etc.)
@@ -233,5 +1031,5 @@ func (m *Manager) removeTaskFromFile(filename, id string) error {
 		result = append(result, line)
 	}
 
-	return os.WriteFile(path, []byte(strings.Join(result, "\n")), 0644)
+	return writeFileAtomic(path, []byte(strings.Join(result, "\n")))
 }
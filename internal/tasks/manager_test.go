@@ -0,0 +1,837 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestManager creates a Manager rooted in a temp dir with the given task files.
+func newTestManager(t *testing.T, files map[string]string) *Manager {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "tasks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"backlog.md", "active.md", "completed.md"} {
+		content, ok := files[name]
+		if !ok {
+			content = "# " + name + "\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewManager(root)
+}
+
+func TestParseTasks_DueDate(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+### [task-001] Ship it
+- **repo**: alpha
+- **due**: 2025-06-01
+`,
+	})
+
+	backlog, err := mgr.ListBacklog()
+	if err != nil {
+		t.Fatalf("ListBacklog: %v", err)
+	}
+	if len(backlog) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(backlog))
+	}
+	if backlog[0].DueDate != "2025-06-01" {
+		t.Errorf("expected due date 2025-06-01, got %q", backlog[0].DueDate)
+	}
+}
+
+func TestTask_IsOverdue(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		due  string
+		want bool
+	}{
+		{"2025-06-01", true},
+		{"2025-06-02", false},
+		{"2025-07-01", false},
+		{"", false},
+		{"not-a-date", false},
+	}
+	for _, tt := range tests {
+		if got := (Task{DueDate: tt.due}).IsOverdue(now); got != tt.want {
+			t.Errorf("IsOverdue(%q): expected %v, got %v", tt.due, tt.want, got)
+		}
+	}
+}
+
+func TestOverdueTasks(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+### [task-001] Late
+- **due**: 2000-01-01
+
+### [task-002] Future
+- **due**: 2999-01-01
+
+### [task-003] Undated
+`,
+		"active.md": `# Active
+
+### [task-004] Late active
+- **due**: 2000-01-01
+`,
+	})
+
+	overdue, err := mgr.OverdueTasks()
+	if err != nil {
+		t.Fatalf("OverdueTasks: %v", err)
+	}
+	if len(overdue) != 2 {
+		t.Fatalf("expected 2 overdue tasks, got %d: %+v", len(overdue), overdue)
+	}
+	ids := map[string]bool{overdue[0].ID: true, overdue[1].ID: true}
+	if !ids["task-001"] || !ids["task-004"] {
+		t.Errorf("unexpected overdue tasks: %+v", overdue)
+	}
+}
+
+func TestListCompleted(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"completed.md": `# Completed Tasks
+
+### [task-010] Finished work
+- **repo**: alpha
+- **completed**: 2025-05-30
+`,
+	})
+
+	completed, err := mgr.ListCompleted()
+	if err != nil {
+		t.Fatalf("ListCompleted: %v", err)
+	}
+	if len(completed) != 1 {
+		t.Fatalf("expected 1 completed task, got %d", len(completed))
+	}
+	if completed[0].ID != "task-010" || completed[0].Completed != "2025-05-30" {
+		t.Errorf("unexpected completed task: %+v", completed[0])
+	}
+}
+
+func TestStartTask_RecordsStartMetadata(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+### [task-001] Do the thing
+- **repo**: alpha
+`,
+	})
+
+	before := time.Now().Add(-time.Second)
+	if err := mgr.StartTask("task-001"); err != nil {
+		t.Fatalf("StartTask: %v", err)
+	}
+
+	active, err := mgr.ListActive()
+	if err != nil {
+		t.Fatalf("ListActive: %v", err)
+	}
+	if len(active) != 1 {
+		t.Fatalf("expected 1 active task, got %d", len(active))
+	}
+	task := active[0]
+	if task.StartedAt.Before(before) || task.StartedAt.After(time.Now()) {
+		t.Errorf("unexpected started_at %v", task.StartedAt)
+	}
+	host, _ := os.Hostname()
+	if task.StartedBy != host {
+		t.Errorf("expected started_by %q, got %q", host, task.StartedBy)
+	}
+
+	if err := mgr.CompleteTask("task-001"); err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+	completed, _ := mgr.ListCompleted()
+	if len(completed) != 1 || !completed[0].StartedAt.Equal(task.StartedAt) {
+		t.Errorf("expected started_at carried to completed, got %+v", completed)
+	}
+}
+
+func TestPauseAndResumeTask(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"active.md": `# Active
+
+### [task-001] Long running
+- **repo**: alpha
+- **assigned**: in-progress
+- **started_at**: 2025-06-01T14:32:00Z
+`,
+	})
+
+	if paused, err := mgr.ListPaused(); err != nil || len(paused) != 0 {
+		t.Fatalf("expected no paused tasks before pausing, got %v, %v", paused, err)
+	}
+
+	if err := mgr.PauseTask("task-001", "waiting on review"); err != nil {
+		t.Fatalf("PauseTask: %v", err)
+	}
+
+	active, _ := mgr.ListActive()
+	if len(active) != 0 {
+		t.Errorf("expected task removed from active, got %+v", active)
+	}
+	paused, err := mgr.ListPaused()
+	if err != nil {
+		t.Fatalf("ListPaused: %v", err)
+	}
+	if len(paused) != 1 || paused[0].Repo != "alpha" {
+		t.Fatalf("unexpected paused tasks: %+v", paused)
+	}
+	if !strings.Contains(paused[0].RawText, "- **pause_reason**: waiting on review") {
+		t.Errorf("pause reason not recorded:\n%s", paused[0].RawText)
+	}
+
+	if err := mgr.ResumeTask("task-001"); err != nil {
+		t.Fatalf("ResumeTask: %v", err)
+	}
+	paused, _ = mgr.ListPaused()
+	if len(paused) != 0 {
+		t.Errorf("expected no paused tasks after resume, got %+v", paused)
+	}
+	active, _ = mgr.ListActive()
+	if len(active) != 1 || active[0].StartedAt.IsZero() {
+		t.Fatalf("expected resumed task with start time, got %+v", active)
+	}
+	if strings.Contains(active[0].RawText, "paused_at") {
+		t.Errorf("pause fields should be dropped on resume:\n%s", active[0].RawText)
+	}
+}
+
+func TestPauseTask_NotActive(t *testing.T) {
+	mgr := newTestManager(t, nil)
+	if err := mgr.PauseTask("task-404", ""); err == nil {
+		t.Error("expected error pausing unknown task")
+	}
+}
+
+func TestTaskBlockAndReplace(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+## High Priority
+
+### [task-001] First
+- **repo**: alpha
+- **priority**: high
+
+### [task-002] Second
+- **repo**: beta
+
+## Low Priority
+`,
+	})
+
+	filename, block, err := mgr.TaskBlock("task-001")
+	if err != nil {
+		t.Fatalf("TaskBlock: %v", err)
+	}
+	if filename != "backlog.md" {
+		t.Errorf("expected backlog.md, got %s", filename)
+	}
+	want := "### [task-001] First\n- **repo**: alpha\n- **priority**: high\n"
+	if block != want {
+		t.Errorf("unexpected block:\n%q", block)
+	}
+
+	edited := "### [task-001] First, renamed\n- **repo**: alpha\n- **priority**: low\n"
+	if err := mgr.ReplaceTaskBlock(filename, "task-001", edited); err != nil {
+		t.Fatalf("ReplaceTaskBlock: %v", err)
+	}
+
+	backlog, _ := mgr.ListBacklog()
+	if len(backlog) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(backlog))
+	}
+	if backlog[0].Title != "First, renamed" || backlog[0].Priority != "low" {
+		t.Errorf("edit not applied: %+v", backlog[0])
+	}
+	if backlog[1].ID != "task-002" || backlog[1].Repo != "beta" {
+		t.Errorf("neighbouring task disturbed: %+v", backlog[1])
+	}
+
+	data, _ := os.ReadFile(filepath.Join(mgr.tasksDir, "backlog.md"))
+	if !strings.Contains(string(data), "## Low Priority") {
+		t.Error("section headers should be preserved")
+	}
+}
+
+func TestReplaceTaskBlock_Validation(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [task-001] First\n- **repo**: alpha\n",
+	})
+
+	tests := map[string]string{
+		"no header":   "- **repo**: alpha\n",
+		"changed id":  "### [task-999] First\n",
+		"two headers": "### [task-001] First\n### [task-002] Second\n",
+	}
+	for name, block := range tests {
+		if err := mgr.ReplaceTaskBlock("backlog.md", "task-001", block); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestAddToBacklog_PrioritySection(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n## High Priority\n\n### [task-001] Existing\n- **repo**: alpha\n\n## Low Priority\n\n<!-- Add low priority tasks here -->\n",
+	})
+
+	if _, err := mgr.AddToBacklog(Task{ID: "GH-7", Title: "Urgent", Priority: "high", Repo: "beta"}); err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+	if _, err := mgr.AddToBacklog(Task{ID: "GH-8", Title: "Unsorted"}); err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+
+	backlog, _ := mgr.ListBacklog()
+	var ids []string
+	for _, task := range backlog {
+		ids = append(ids, task.ID)
+	}
+	if strings.Join(ids, ",") != "task-001,GH-7,GH-8" {
+		t.Fatalf("unexpected order: %v", ids)
+	}
+	if backlog[1].Repo != "beta" || backlog[1].Priority != "high" {
+		t.Errorf("fields not written: %+v", backlog[1])
+	}
+
+	data, _ := os.ReadFile(filepath.Join(mgr.tasksDir, "backlog.md"))
+	high := strings.Index(string(data), "[GH-7]")
+	low := strings.Index(string(data), "## Low Priority")
+	if high < 0 || high > low {
+		t.Errorf("GH-7 should be filed under High Priority:\n%s", data)
+	}
+}
+
+func TestAddToBacklog_RejectsDuplicateID(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"active.md": "# Active\n\n### [GH-1] Already started\n",
+	})
+	if _, err := mgr.AddToBacklog(Task{ID: "GH-1", Title: "Again"}); err == nil {
+		t.Error("expected error for ID already in active.md")
+	}
+	if _, err := mgr.AddToBacklog(Task{ID: "GH-2"}); err == nil {
+		t.Error("expected error for missing title")
+	}
+}
+
+func TestAddToBacklog_RejectsInjectedTasks(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{"backlog.md": "# Backlog\n"})
+	if _, err := mgr.AddToBacklog(Task{Title: "Real\n### [T-999] Fake"}); err == nil {
+		t.Error("expected error for a multi-line title")
+	}
+
+	// An indented description line cannot start a task.
+	id, err := mgr.AddToBacklog(Task{Title: "Real", Description: "first\n### [T-999] Fake"})
+	if err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+	list, _ := mgr.ListBacklog()
+	if len(list) != 1 || list[0].ID != id || list[0].Description != "first\n### [T-999] Fake" {
+		t.Errorf("expected one task keeping its description, got %+v", list)
+	}
+
+	fm := newTestManager(t, map[string]string{"backlog.md": "# Backlog\n\n---\nid: T-001\ntitle: First\n---\n"})
+	if _, err := fm.AddToBacklog(Task{Title: "Real", Description: "first\n### [T-999] Fake"}); err == nil {
+		t.Error("expected error for a frontmatter description that starts a task")
+	}
+	if list, _ := fm.ListBacklog(); len(list) != 1 {
+		t.Errorf("expected the backlog to be unchanged, got %+v", list)
+	}
+}
+
+func TestStartTask_Concurrent(t *testing.T) {
+	backlog := "# Backlog\n"
+	for i := 0; i < 10; i++ {
+		backlog += fmt.Sprintf("\n### [task-%03d] Task %d\n- **repo**: alpha\n", i, i)
+	}
+	mgr := newTestManager(t, map[string]string{"backlog.md": backlog})
+	// A second Manager on the same directory stands in for another process,
+	// so only the file lock orders it against the first.
+	other := &Manager{tasksDir: mgr.tasksDir}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		m := mgr
+		if i%2 == 1 {
+			m = other
+		}
+		wg.Add(1)
+		go func(m *Manager, id string) {
+			defer wg.Done()
+			if err := m.StartTask(id); err != nil {
+				errs <- fmt.Errorf("%s: %w", id, err)
+			}
+		}(m, fmt.Sprintf("task-%03d", i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	remaining, _ := mgr.ListBacklog()
+	active, _ := mgr.ListActive()
+	if len(remaining) != 0 || len(active) != 10 {
+		t.Errorf("expected 0 backlog and 10 active, got %d and %d", len(remaining), len(active))
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	now := time.Date(2025, 6, 20, 12, 0, 0, 0, time.Local)
+	started := time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local)
+
+	backlog := []Task{
+		{ID: "a", Priority: "high", Created: "2025-06-10"},
+		{ID: "b", Priority: "high", Created: "2025-06-18"},
+		{ID: "c", Created: "2025-06-19"},
+		{ID: "d"},
+	}
+	active := []Task{{ID: "e", Priority: "low"}}
+	completed := []Task{
+		{ID: "f", StartedAt: started, Completed: "2025-06-05"},
+		{ID: "g", StartedAt: started, Completed: "2025-06-03"},
+		{ID: "h", Completed: "2025-06-04"},
+	}
+
+	s := computeStats(backlog, active, nil, completed, now)
+	if s.Backlog != 4 || s.Active != 1 || s.Paused != 0 || s.Completed != 3 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.CycleSamples != 2 || s.AvgCycleDays != 3 {
+		t.Errorf("expected avg cycle 3 days over 2 tasks, got %v over %d", s.AvgCycleDays, s.CycleSamples)
+	}
+	if s.AgeSamples != 3 || s.MedianBacklogAgeDays != 2.5 {
+		t.Errorf("expected median age 2.5 days over 3 tasks, got %v over %d", s.MedianBacklogAgeDays, s.AgeSamples)
+	}
+	if s.ByPriority["high"] != 2 || s.ByPriority["low"] != 1 || s.ByPriority["unset"] != 2 {
+		t.Errorf("unexpected priority breakdown: %v", s.ByPriority)
+	}
+}
+
+func TestAddToBacklog_GeneratesID(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md":   "# Backlog\n\n### [T-007] Seventh\n",
+		"completed.md": "# Completed\n\n### [T-041] Done\n### [task-900] Other scheme\n",
+	})
+
+	id, err := mgr.AddToBacklog(Task{Title: "Generated"})
+	if err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+	if id != "T-042" {
+		t.Errorf("expected T-042, got %s", id)
+	}
+
+	id, _ = mgr.AddToBacklog(Task{Title: "Next"})
+	if id != "T-043" {
+		t.Errorf("expected T-043, got %s", id)
+	}
+}
+
+func TestUpdateTaskField(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md":   "# Backlog\n\n### [task-001] First\n- **repo**: alpha\n- **priority**: low\n\n### [task-002] Second\n- **repo**: beta\n",
+		"completed.md": "# Completed\n\n### [task-003] Done\n- **repo**: alpha\n",
+	})
+
+	if err := mgr.UpdateTaskField("task-001", "repo", "gamma"); err != nil {
+		t.Fatalf("UpdateTaskField: %v", err)
+	}
+	if err := mgr.UpdateTaskField("task-001", "assigned", "sam"); err != nil {
+		t.Fatalf("UpdateTaskField: %v", err)
+	}
+	if err := mgr.UpdateTaskField("task-001", "priority", ""); err != nil {
+		t.Fatalf("UpdateTaskField: %v", err)
+	}
+
+	backlog, _ := mgr.ListBacklog()
+	first := backlog[0]
+	if first.Repo != "gamma" || first.Assigned != "sam" || first.Priority != "" {
+		t.Errorf("fields not updated: %+v", first)
+	}
+	if backlog[1].Repo != "beta" {
+		t.Errorf("neighbouring task disturbed: %+v", backlog[1])
+	}
+
+	if err := mgr.UpdateTaskField("task-003", "repo", "gamma"); err == nil {
+		t.Error("expected error updating a completed task")
+	}
+	if err := mgr.UpdateTaskField("task-001", "bad field", "x"); err == nil {
+		t.Error("expected error for invalid field name")
+	}
+	if err := mgr.UpdateTaskField("task-404", "repo", "x"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestAssignTask(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [task-001] First\n- **repo**: alpha\n",
+		"active.md":  "# Active\n",
+	})
+
+	if err := mgr.AssignTask("task-001", "claude-worker-3"); err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	if err := mgr.AssignTask("task-001", " "); err == nil {
+		t.Error("expected error for empty assignee")
+	}
+
+	// Starting the task keeps the assignee rather than resetting it.
+	if err := mgr.StartTask("task-001"); err != nil {
+		t.Fatalf("StartTask: %v", err)
+	}
+	active, _ := mgr.ListActive()
+	if len(active) != 1 || active[0].Assigned != "claude-worker-3" {
+		t.Fatalf("expected assignee to survive start, got %+v", active)
+	}
+
+	if err := mgr.UnassignTask("task-001"); err != nil {
+		t.Fatalf("UnassignTask: %v", err)
+	}
+	active, _ = mgr.ListActive()
+	if active[0].Assigned != "" {
+		t.Errorf("expected assignee cleared, got %q", active[0].Assigned)
+	}
+}
+
+func TestTasksByAssignee(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md":   "# Backlog\n\n### [T-001] Mine\n- **assigned**: Alice\n\n### [T-002] Theirs\n- **assigned**: bob\n",
+		"active.md":    "# Active\n\n### [T-003] Doing\n- **assigned**: alice\n",
+		"completed.md": "# Completed\n\n### [T-004] Done\n- **assigned**: alice\n",
+	})
+
+	got, err := mgr.TasksByAssignee("ALICE")
+	if err != nil {
+		t.Fatalf("TasksByAssignee: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "T-003" || got[0].State != "active" || got[1].ID != "T-001" || got[1].State != "backlog" {
+		t.Errorf("unexpected tasks: %+v", got)
+	}
+
+	if got, err := mgr.TasksByAssignee("carol"); err != nil || len(got) != 0 {
+		t.Errorf("expected no tasks for carol, got %+v, %v", got, err)
+	}
+	if _, err := mgr.TasksByAssignee(" "); err == nil {
+		t.Error("expected error for empty assignee")
+	}
+}
+
+func TestExportTasks(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md":   "# Backlog\n\n### [task-001] First\n- **repo**: alpha\n- **priority**: high\n<!-- stray comment -->\n",
+		"active.md":    "# Active\n\n### [task-002] Second\n- **started_at**: 2025-06-01T09:00:00Z\n",
+		"completed.md": "# Completed\n\n### [task-003] Third\n- **completed**: 2025-06-02\n",
+	})
+
+	all, err := mgr.ExportTasks([]string{"all"})
+	if err != nil {
+		t.Fatalf("ExportTasks: %v", err)
+	}
+	var got []string
+	for _, task := range all {
+		got = append(got, task.ID+":"+task.State)
+	}
+	if strings.Join(got, ",") != "task-001:backlog,task-002:active,task-003:completed" {
+		t.Errorf("unexpected export: %v", got)
+	}
+
+	if _, err := mgr.ExportTasks([]string{"archived"}); err == nil {
+		t.Error("expected error for unknown state")
+	}
+
+	md := FormatMarkdown(all)
+	want := "# Backlog\n\n### [task-001] First\n- **repo**: alpha\n- **priority**: high\n\n" +
+		"# Active\n\n### [task-002] Second\n- **started_at**: 2025-06-01T09:00:00Z\n\n" +
+		"# Completed\n\n### [task-003] Third\n- **completed**: 2025-06-02\n"
+	if md != want {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+}
+
+func TestShardedBacklog(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n## High Priority\n\n### [task-001] First\n- **priority**: high\n\n## Other\n\n### [task-002] Second\n- **repo**: my/service\n\n### [task-003] Third\nNotes that are not a field.\n",
+	})
+	if mgr.Sharded() {
+		t.Fatal("expected legacy mode with only backlog.md")
+	}
+
+	if err := mgr.MigrateToSharded(); err != nil {
+		t.Fatalf("MigrateToSharded: %v", err)
+	}
+	if !mgr.Sharded() {
+		t.Fatal("expected sharded mode after migration")
+	}
+	if _, err := os.Stat(filepath.Join(mgr.tasksDir, "backlog.md")); !os.IsNotExist(err) {
+		t.Error("expected backlog.md to be removed")
+	}
+	for _, name := range []string{"high.md", "repo-my-service.md", "unsorted.md"} {
+		if _, err := os.Stat(filepath.Join(mgr.tasksDir, "backlog", name)); err != nil {
+			t.Errorf("expected shard %s: %v", name, err)
+		}
+	}
+	if err := mgr.MigrateToSharded(); err == nil {
+		t.Error("expected error migrating twice")
+	}
+	high, _ := os.ReadFile(filepath.Join(mgr.tasksDir, "backlog", "high.md"))
+	unsorted, _ := os.ReadFile(filepath.Join(mgr.tasksDir, "backlog", "unsorted.md"))
+	if strings.Contains(string(high), "## Other") || !strings.Contains(string(unsorted), "\nNotes that are not a field.\n") {
+		t.Errorf("expected notes kept and headings dropped, got:\n%s\n%s", high, unsorted)
+	}
+
+	// A high-priority task for my/service goes to the existing priority shard.
+	if _, err := mgr.AddToBacklog(Task{ID: "T-004", Title: "Fourth", Priority: "high", Repo: "my/service"}); err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+	// With no priority shard, the repo shard is used.
+	if _, err := mgr.AddToBacklog(Task{ID: "T-005", Title: "Fifth", Priority: "low", Repo: "my/service"}); err != nil {
+		t.Fatalf("AddToBacklog: %v", err)
+	}
+	highTasks, _ := mgr.ParseTasks("backlog/high.md")
+	repo, _ := mgr.ParseTasks("backlog/repo-my-service.md")
+	if len(highTasks) != 2 || len(repo) != 2 {
+		t.Errorf("unexpected shard contents: high=%+v repo=%+v", highTasks, repo)
+	}
+
+	backlog, err := mgr.ListBacklog()
+	if err != nil || len(backlog) != 5 {
+		t.Fatalf("expected 5 backlog tasks, got %d (%v)", len(backlog), err)
+	}
+	if _, err := mgr.AddToBacklog(Task{ID: "task-002", Title: "Dup"}); err == nil {
+		t.Error("expected duplicate ID in another shard to be rejected")
+	}
+
+	if err := mgr.StartTask("task-002"); err != nil {
+		t.Fatalf("StartTask: %v", err)
+	}
+	repo, _ = mgr.ParseTasks("backlog/repo-my-service.md")
+	if len(repo) != 1 || repo[0].ID != "T-005" {
+		t.Errorf("expected task-002 removed from its shard, got %+v", repo)
+	}
+	if err := mgr.UpdateTaskField("task-003", "repo", "alpha"); err != nil {
+		t.Fatalf("UpdateTaskField on shard: %v", err)
+	}
+}
+
+func TestUpdateTaskAndGetTask(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [T-042] Answer\n- **repo**: alpha\n- **priority**: low\n- **type**: bug\n",
+	})
+
+	err := mgr.UpdateTask("T-042", map[string]string{"priority": "high", "description": "new desc"})
+	if err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	got, err := mgr.GetTask("T-042")
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.Priority != "high" || got.Description != "new desc" || got.Repo != "alpha" || got.Type != "bug" || got.State != "backlog" {
+		t.Errorf("unexpected task after update: %+v", got)
+	}
+
+	if err := mgr.UpdateTask("T-042", map[string]string{"repo": "beta", "bad field": "x"}); err == nil {
+		t.Error("expected error for invalid field name")
+	}
+	if got, _ := mgr.GetTask("T-042"); got.Repo != "alpha" {
+		t.Errorf("expected no partial update, got repo %q", got.Repo)
+	}
+	if _, err := mgr.GetTask("T-404"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestUpdateTask_MultilineDescription(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [T-001] Bullet\n- **repo**: alpha\n\n" +
+			"### [T-002] Next\n- **repo**: beta\n",
+	})
+
+	desc := "First line.\n\n### [T-999] Not a task\nLast line."
+	if err := mgr.UpdateTask("T-001", map[string]string{"description": desc}); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	backlog, _ := mgr.ListBacklog()
+	if len(backlog) != 2 || backlog[0].Description != desc || backlog[1].ID != "T-002" {
+		t.Errorf("unexpected backlog after update: %+v", backlog)
+	}
+	if err := mgr.UpdateTask("T-001", map[string]string{"repo": "alpha\nbeta"}); err == nil {
+		t.Error("expected error for a multi-line repo")
+	}
+
+	fm := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n---\nid: T-010\ntitle: Frontmatter\n---\nOld.\n",
+	})
+	injected := "Fine.\n\n### [T-011] Injected\n- **repo**: x"
+	if err := fm.UpdateTask("T-010", map[string]string{"description": injected}); err == nil {
+		t.Error("expected error for a description that starts another task")
+	}
+	if err := fm.UpdateTask("T-010", map[string]string{"description": "New.\nSecond line."}); err != nil {
+		t.Fatalf("UpdateTask on frontmatter: %v", err)
+	}
+	if got, _ := fm.GetTask("T-010"); got.Description != "New.\nSecond line." {
+		t.Errorf("unexpected frontmatter description %q", got.Description)
+	}
+}
+
+func TestFindTask(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md":   "# Backlog\n\n### [B-1] Queued\n- **repo**: alpha\n",
+		"active.md":    "# Active\n\n### [A-1] Working\n- **assigned**: paul\n",
+		"completed.md": "# Completed\n\n### [C-1] Done\n- **completed**: 2025-01-01\n",
+	})
+	os.WriteFile(filepath.Join(mgr.tasksDir, "paused.md"), []byte("# Paused\n\n### [P-1] Parked\n- **pause_reason**: waiting\n"), 0644)
+
+	for _, tc := range []struct {
+		id, state, title string
+	}{
+		{"B-1", "backlog", "Queued"},
+		{"A-1", "active", "Working"},
+		{"P-1", "paused", "Parked"},
+		{"C-1", "completed", "Done"},
+	} {
+		got, state, err := mgr.FindTask(tc.id)
+		if err != nil {
+			t.Errorf("FindTask(%s): %v", tc.id, err)
+			continue
+		}
+		if state != tc.state || got.ID != tc.id || got.Title != tc.title {
+			t.Errorf("FindTask(%s) = %+v in %q, want %q in %q", tc.id, got, state, tc.title, tc.state)
+		}
+	}
+	if _, _, err := mgr.FindTask("X-1"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+
+	// Shards report as backlog too.
+	if err := mgr.MigrateToSharded(); err != nil {
+		t.Fatal(err)
+	}
+	if _, state, err := mgr.FindTask("B-1"); err != nil || state != "backlog" {
+		t.Errorf("FindTask(B-1) after sharding = %q, %v", state, err)
+	}
+}
+
+func TestTags_SurviveStartAndComplete(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [T-1] Tagged\n- **tags**: sprint-2, ui\n",
+	})
+	if err := mgr.StartTask("T-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.CompleteTask("T-1"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := mgr.GetTask("T-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != "completed" || !got.HasTag("sprint-2") || !got.HasTag("ui") || got.HasTag("sprint") {
+		t.Errorf("expected tags to be kept, got %+v", got)
+	}
+}
+
+func TestFields_SurviveStartAndComplete(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [T-1] Busy\n- **priority**: high\n- **assigned**: alice\n" +
+			"- **branch**: feature/x\n- **due**: 2026-12-01\n- **created**: 2026-10-01\n",
+	})
+	if err := mgr.StartTask("T-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.CompleteTask("T-1"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := mgr.GetTask("T-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != "completed" || got.Priority != "high" || got.Assigned != "alice" || got.Branch != "feature/x" ||
+		got.DueDate != "2026-12-01" || got.Created != "2026-10-01" || got.StartedAt.IsZero() || got.Completed == "" {
+		t.Errorf("expected every field to be kept, got %+v", got)
+	}
+}
+
+func TestParseTasks_MultiLineDescription(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+### [task-001] Rework the scanner
+- **repo**: alpha
+- **description**: The scanner shells out to git once per field,
+    which is slow on large monorepos.
+    Batch the calls into one porcelain v2 invocation.
+
+    Keep the existing JSON output unchanged.
+- **priority**: high
+
+### [task-002] Next
+- **repo**: beta
+`,
+	})
+
+	want := "The scanner shells out to git once per field,\n" +
+		"which is slow on large monorepos.\n" +
+		"Batch the calls into one porcelain v2 invocation.\n" +
+		"\n" +
+		"Keep the existing JSON output unchanged."
+
+	backlog, err := mgr.ListBacklog()
+	if err != nil {
+		t.Fatalf("ListBacklog: %v", err)
+	}
+	if len(backlog) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(backlog))
+	}
+	if backlog[0].Description != want {
+		t.Errorf("Description = %q, want %q", backlog[0].Description, want)
+	}
+	if backlog[0].Priority != "high" || backlog[1].Repo != "beta" {
+		t.Errorf("fields after the description were lost: %+v", backlog)
+	}
+
+	// The description survives moving between files and editing other fields.
+	if err := mgr.StartTask("task-001"); err != nil {
+		t.Fatalf("StartTask: %v", err)
+	}
+	if err := mgr.UpdateTaskField("task-001", "repo", "gamma"); err != nil {
+		t.Fatalf("UpdateTaskField: %v", err)
+	}
+	if err := mgr.PauseTask("task-001", "blocked"); err != nil {
+		t.Fatalf("PauseTask: %v", err)
+	}
+	paused, err := mgr.GetTask("task-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paused.Description != want || paused.Repo != "gamma" {
+		t.Errorf("after start, update, and pause got %+v", paused)
+	}
+	backlog, _ = mgr.ListBacklog()
+	if len(backlog) != 1 || backlog[0].ID != "task-002" || strings.Contains(backlog[0].RawText, "porcelain") {
+		t.Errorf("continuation lines left behind in backlog: %+v", backlog)
+	}
+}
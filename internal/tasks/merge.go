@@ -0,0 +1,109 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MergeNew is the MergeTasks target that creates a new task from both.
+const MergeNew = "new"
+
+// mergeSeparator divides the descriptions combined by MergeTasks.
+const mergeSeparator = "\n\n---\n\n"
+
+// MergeTasks consolidates two backlog, active, or paused tasks. With target
+// set to id1 or id2, the other task's description is appended to the
+// target's after a "---" separator, its links are added to the target's,
+// and it is deleted. With target MergeNew, a new backlog task titled
+// "<title1> / <title2>" is created with both descriptions and all links,
+// taking repo, type, and priority from id1 where set and id2 otherwise, and
+// both originals are deleted. It returns the ID of the merged task.
+func (m *Manager) MergeTasks(id1, id2, target string) (string, error) {
+	if id1 == id2 {
+		return "", fmt.Errorf("cannot merge task %s into itself", id1)
+	}
+	if target != id1 && target != id2 && target != MergeNew {
+		return "", fmt.Errorf("merge target must be %s, %s, or %s, not %q", id1, id2, MergeNew, target)
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	first, firstFile, err := m.findTask(id1)
+	if err != nil {
+		return "", err
+	}
+	second, secondFile, err := m.findTask(id2)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range []struct{ id, file string }{{id1, firstFile}, {id2, secondFile}} {
+		if f.file == "completed.md" {
+			return "", fmt.Errorf("task %s is completed and cannot be merged", f.id)
+		}
+	}
+
+	if target == MergeNew {
+		merged := Task{
+			Title:       first.Title + " / " + second.Title,
+			Repo:        firstNonEmpty(first.Repo, second.Repo),
+			Type:        firstNonEmpty(first.Type, second.Type),
+			Priority:    firstNonEmpty(first.Priority, second.Priority),
+			Description: mergeDescriptions(first.Description, second.Description),
+			Links:       mergeLinks(first.Links, second.Links),
+		}
+		newID, err := m.addToBacklog(merged)
+		if err != nil {
+			return "", err
+		}
+		if err := m.removeTaskFromFile(firstFile, id1); err != nil {
+			return newID, err
+		}
+		return newID, m.removeTaskFromFile(secondFile, id2)
+	}
+
+	keep, drop, dropFile := first, second, secondFile
+	if target == id2 {
+		keep, drop, dropFile = second, first, firstFile
+	}
+	updates := map[string]string{
+		"description": mergeDescriptions(keep.Description, drop.Description),
+		"links":       strings.Join(mergeLinks(keep.Links, drop.Links), ", "),
+	}
+	if err := m.updateTask(keep.ID, []string{"description", "links"}, updates); err != nil {
+		return "", err
+	}
+	return keep.ID, m.removeTaskFromFile(dropFile, drop.ID)
+}
+
+// firstNonEmpty returns a if it is non-empty and b otherwise.
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+// mergeDescriptions joins two descriptions with mergeSeparator, or returns
+// whichever is non-empty.
+func mergeDescriptions(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + mergeSeparator + b
+}
+
+// mergeLinks returns a followed by the links in b that a lacks.
+func mergeLinks(a, b []string) []string {
+	merged := slices.Clone(a)
+	for _, link := range b {
+		if !slices.Contains(merged, link) {
+			merged = append(merged, link)
+		}
+	}
+	return merged
+}
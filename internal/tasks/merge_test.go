@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+)
+
+const mergeBacklog = `# Backlog
+
+### [T-001] Fix login
+- **repo**: alpha
+- **links**: https://example.com/1
+- **description**: Login fails on Safari.
+
+### [T-002] Login broken
+- **priority**: high
+- **links**: https://example.com/1, https://example.com/2
+- **description**: Users cannot sign in.
+`
+
+func TestMergeTasks_IntoExisting(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{"backlog.md": mergeBacklog})
+
+	id, err := mgr.MergeTasks("T-001", "T-002", "T-001")
+	if err != nil || id != "T-001" {
+		t.Fatalf("MergeTasks = %q, %v", id, err)
+	}
+	merged, err := mgr.GetTask("T-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Login fails on Safari.\n\n---\n\nUsers cannot sign in."; merged.Description != want {
+		t.Errorf("Description = %q, want %q", merged.Description, want)
+	}
+	if want := []string{"https://example.com/1", "https://example.com/2"}; !reflect.DeepEqual(merged.Links, want) {
+		t.Errorf("Links = %v, want %v", merged.Links, want)
+	}
+	if _, err := mgr.GetTask("T-002"); err == nil {
+		t.Error("expected T-002 to be deleted")
+	}
+}
+
+func TestMergeTasks_IntoNew(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{"backlog.md": mergeBacklog})
+
+	id, err := mgr.MergeTasks("T-001", "T-002", MergeNew)
+	if err != nil {
+		t.Fatalf("MergeTasks: %v", err)
+	}
+	merged, err := mgr.GetTask(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Title != "Fix login / Login broken" || merged.Repo != "alpha" || merged.Priority != "high" ||
+		merged.Description != "Login fails on Safari.\n\n---\n\nUsers cannot sign in." || len(merged.Links) != 2 {
+		t.Errorf("unexpected merged task: %+v", merged)
+	}
+	backlog, _ := mgr.ListBacklog()
+	if len(backlog) != 1 {
+		t.Errorf("expected only the merged task to remain, got %+v", backlog)
+	}
+}
+
+func TestMergeTasks_Invalid(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md":   mergeBacklog,
+		"completed.md": "# Completed\n\n### [T-003] Done\n",
+	})
+	for _, tc := range [][3]string{
+		{"T-001", "T-001", "T-001"},
+		{"T-001", "T-002", "T-009"},
+		{"T-001", "T-009", "T-001"},
+		{"T-001", "T-003", "T-001"},
+	} {
+		if _, err := mgr.MergeTasks(tc[0], tc[1], tc[2]); err == nil {
+			t.Errorf("MergeTasks%v: expected an error", tc)
+		}
+	}
+	if backlog, _ := mgr.ListBacklog(); len(backlog) != 2 {
+		t.Errorf("failed merges changed the backlog: %+v", backlog)
+	}
+}
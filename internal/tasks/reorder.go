@@ -0,0 +1,131 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BacklogGroup is one backlog file and its tasks, in file order. The ranks
+// MoveTask and MoveTaskTo report count within a single group.
+type BacklogGroup struct {
+	File  string // relative to tasks/, e.g. backlog.md or backlog/high.md
+	Tasks []Task
+}
+
+// BacklogGroups returns the backlog one file at a time, in the order
+// ListBacklog reads them. Without sharding there is a single group for
+// backlog.md.
+func (m *Manager) BacklogGroups() ([]BacklogGroup, error) {
+	files, err := m.backlogFiles()
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]BacklogGroup, 0, len(files))
+	for _, name := range files {
+		list, err := m.ParseTasks(name)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, BacklogGroup{File: name, Tasks: list})
+	}
+	return groups, nil
+}
+
+// MoveTask moves a backlog task offset places within its backlog file:
+// negative offsets move it up (higher priority), positive ones down. Offsets
+// past either end stop at the first or last position. It returns the task's
+// new 1-based rank in the file.
+func (m *Manager) MoveTask(id string, offset int) (int, error) {
+	return m.moveTask(id, func(cur, n int) int {
+		switch {
+		case offset < -cur:
+			return 0
+		case offset > n-1-cur:
+			return n - 1
+		}
+		return cur + offset
+	})
+}
+
+// MoveTaskTo moves a backlog task to the given 1-based rank within its
+// backlog file, clamped to the number of tasks in it, and returns the rank
+// it ended up at.
+func (m *Manager) MoveTaskTo(id string, rank int) (int, error) {
+	return m.moveTask(id, func(cur, n int) int {
+		return min(max(rank, 1), n) - 1
+	})
+}
+
+// moveTask reorders the tasks in the backlog file holding id. Task blocks
+// trade places while all other text, such as priority headings, stays where
+// it is, so a task moved past a heading falls under it. target maps the
+// task's current 0-based index among n tasks to its new one. The file is
+// rewritten atomically.
+func (m *Manager) moveTask(id string, target func(cur, n int) int) (int, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	_, filename, err := m.findTask(id)
+	if err != nil {
+		return 0, err
+	}
+	if !isBacklogFile(filename) {
+		return 0, fmt.Errorf("task %s is %s, not in the backlog", id, stateName(filename))
+	}
+
+	path := filepath.Join(m.tasksDir, filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	type span struct{ start, end int }
+	var spans []span
+	var blocks [][]string
+	cur := -1
+	for i := 0; i < len(lines); i++ {
+		taskID, ok := taskStart(lines, i)
+		if !ok {
+			continue
+		}
+		if taskID == id && cur < 0 {
+			cur = len(spans)
+		}
+		end := blockEnd(lines, i)
+		spans = append(spans, span{i, end})
+		blocks = append(blocks, lines[i:end])
+		i = end - 1
+	}
+	if cur < 0 {
+		return 0, fmt.Errorf("task %s not found in %s", id, filename)
+	}
+
+	pos := target(cur, len(blocks))
+	if pos == cur {
+		return pos + 1, nil
+	}
+	moved := blocks[cur]
+	blocks = append(blocks[:cur:cur], blocks[cur+1:]...)
+	blocks = append(blocks[:pos], append([][]string{moved}, blocks[pos:]...)...)
+
+	result := append([]string{}, lines[:spans[0].start]...)
+	for k, s := range spans {
+		result = append(result, blocks[k]...)
+		next := len(lines)
+		if k+1 < len(spans) {
+			next = spans[k+1].start
+		}
+		result = append(result, lines[s.end:next]...)
+	}
+
+	if err := writeFileAtomic(path, []byte(strings.Join(result, "\n"))); err != nil {
+		return 0, err
+	}
+	return pos + 1, nil
+}
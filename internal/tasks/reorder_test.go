@@ -0,0 +1,119 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMoveTask(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+## High Priority
+
+### [a] First
+- **priority**: high
+
+### [b] Second
+
+## Low Priority
+
+### [c] Third
+- **repo**: alpha
+`,
+		"active.md": "# Active\n\n### [x] Running\n",
+	})
+
+	ids := func() string {
+		t.Helper()
+		backlog, err := mgr.ListBacklog()
+		if err != nil {
+			t.Fatalf("ListBacklog: %v", err)
+		}
+		var ids []string
+		for _, task := range backlog {
+			ids = append(ids, task.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	tests := []struct {
+		name     string
+		move     func() (int, error)
+		wantRank int
+		want     string
+	}{
+		{"down one", func() (int, error) { return mgr.MoveTask("a", 1) }, 2, "b,a,c"},
+		{"up past top", func() (int, error) { return mgr.MoveTask("c", -10) }, 1, "c,b,a"},
+		{"to bottom", func() (int, error) { return mgr.MoveTaskTo("c", 99) }, 3, "b,a,c"},
+		{"to rank", func() (int, error) { return mgr.MoveTaskTo("a", 1) }, 1, "a,b,c"},
+	}
+	for _, tt := range tests {
+		rank, err := tt.move()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if rank != tt.wantRank {
+			t.Errorf("%s: rank = %d, want %d", tt.name, rank, tt.wantRank)
+		}
+		if got := ids(); got != tt.want {
+			t.Errorf("%s: order = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// Headings stay put and each block keeps its fields.
+	if _, err := mgr.MoveTask("c", -2); err != nil {
+		t.Fatal(err)
+	}
+	_, block, err := mgr.TaskBlock("c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block != "### [c] Third\n- **repo**: alpha\n" {
+		t.Errorf("block for c = %q", block)
+	}
+	backlog, _ := mgr.ParseTasks("backlog.md")
+	if backlog[0].ID != "c" || backlog[2].Priority != "" || backlog[1].Priority != "high" {
+		t.Errorf("unexpected backlog after move: %+v", backlog)
+	}
+
+	if _, err := mgr.MoveTask("x", 1); err == nil || !strings.Contains(err.Error(), "active") {
+		t.Errorf("moving an active task: err = %v", err)
+	}
+}
+
+func TestBacklogGroups(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [a] First\n- **priority**: high\n\n### [b] Second\n- **priority**: low\n\n### [c] Third\n- **priority**: high\n",
+	})
+
+	groups, err := mgr.BacklogGroups()
+	if err != nil {
+		t.Fatalf("BacklogGroups: %v", err)
+	}
+	if len(groups) != 1 || groups[0].File != "backlog.md" || len(groups[0].Tasks) != 3 {
+		t.Fatalf("unexpected groups before sharding: %+v", groups)
+	}
+
+	if err := mgr.MigrateToSharded(); err != nil {
+		t.Fatal(err)
+	}
+	groups, err = mgr.BacklogGroups()
+	if err != nil {
+		t.Fatalf("BacklogGroups: %v", err)
+	}
+	var got []string
+	for _, g := range groups {
+		var ids []string
+		for _, task := range g.Tasks {
+			ids = append(ids, task.ID)
+		}
+		got = append(got, g.File+"="+strings.Join(ids, ","))
+	}
+	if strings.Join(got, " ") != "backlog/high.md=a,c backlog/low.md=b" {
+		t.Errorf("groups = %v", got)
+	}
+	if rank, err := mgr.MoveTaskTo("c", 1); err != nil || rank != 1 {
+		t.Errorf("MoveTaskTo within a shard = %d, %v", rank, err)
+	}
+}
@@ -0,0 +1,78 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Task sources, identified by the prefix of a task's ID.
+const (
+	SourceGitHub  = "github"
+	SourceLinear  = "linear"
+	SourceLocal   = "local"
+	SourceUnknown = "unknown"
+)
+
+// sourceIDRe matches IDs with a recognized prefix: GH-123 for GitHub issues,
+// LI-456 for Linear issues, and T-007 for tasks created locally.
+var sourceIDRe = regexp.MustCompile(`^(GH|LI|T)-\d+$`)
+
+var sourcesByPrefix = map[string]string{
+	"GH": SourceGitHub,
+	"LI": SourceLinear,
+	"T":  SourceLocal,
+}
+
+// TaskSource returns the source a task ID belongs to: SourceGitHub,
+// SourceLinear, SourceLocal, or SourceUnknown if the ID has no recognized
+// prefix.
+func (m *Manager) TaskSource(id string) string {
+	return taskSource(id)
+}
+
+// Source returns the source of the task's ID; see Manager.TaskSource.
+func (t Task) Source() string {
+	return taskSource(t.ID)
+}
+
+func taskSource(id string) string {
+	matches := sourceIDRe.FindStringSubmatch(id)
+	if matches == nil {
+		return SourceUnknown
+	}
+	return sourcesByPrefix[matches[1]]
+}
+
+// SearchBySource returns the tasks in every state file whose ID belongs to
+// source, with State set. Use SourceUnknown to find custom-prefixed IDs.
+func (m *Manager) SearchBySource(source string) ([]Task, error) {
+	switch source {
+	case SourceGitHub, SourceLinear, SourceLocal, SourceUnknown:
+	default:
+		return nil, fmt.Errorf("unknown source %q (want %s, %s, %s, or %s)",
+			source, SourceGitHub, SourceLinear, SourceLocal, SourceUnknown)
+	}
+
+	files, err := m.stateFiles()
+	if err != nil {
+		return nil, err
+	}
+	var found []Task
+	for _, name := range files {
+		list, err := m.ParseTasks(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range list {
+			if taskSource(t.ID) == source {
+				t.State = stateName(name)
+				found = append(found, t)
+			}
+		}
+	}
+	return found, nil
+}
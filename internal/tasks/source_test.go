@@ -0,0 +1,57 @@
+package tasks
+
+import "testing"
+
+func TestTaskSource(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+	for id, want := range map[string]string{
+		"GH-123":   SourceGitHub,
+		"LI-456":   SourceLinear,
+		"T-007":    SourceLocal,
+		"task-001": SourceUnknown,
+		"GH-":      SourceUnknown,
+		"gh-12":    SourceUnknown,
+		"T-7x":     SourceUnknown,
+	} {
+		if got := mgr.TaskSource(id); got != want {
+			t.Errorf("TaskSource(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestSearchBySource(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": "# Backlog\n\n### [GH-1] Issue\n\n### [T-001] Local\n\n### [custom-1] Custom\n",
+		"active.md":  "# Active\n\n### [GH-2] Started issue\n",
+	})
+
+	got, err := mgr.SearchBySource(SourceGitHub)
+	if err != nil {
+		t.Fatalf("SearchBySource: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "GH-1" || got[0].State != "backlog" || got[1].ID != "GH-2" || got[1].State != "active" {
+		t.Errorf("unexpected github tasks: %+v", got)
+	}
+	if got, _ := mgr.SearchBySource(SourceUnknown); len(got) != 1 || got[0].ID != "custom-1" {
+		t.Errorf("unexpected unknown tasks: %+v", got)
+	}
+	if _, err := mgr.SearchBySource("jira"); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
+
+func TestAddToBacklog_CustomPrefix(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{"backlog.md": "# Backlog\n"})
+
+	if _, err := mgr.AddToBacklog(Task{ID: "JIRA-9", Title: "Custom"}); err == nil {
+		t.Error("expected an unrecognized prefix to be rejected")
+	}
+	if _, err := mgr.AddToBacklog(Task{ID: "LI-9", Title: "Linear"}); err != nil {
+		t.Errorf("AddToBacklog LI-9: %v", err)
+	}
+
+	mgr.AllowCustomPrefix = true
+	if _, err := mgr.AddToBacklog(Task{ID: "JIRA-9", Title: "Custom"}); err != nil {
+		t.Errorf("AddToBacklog with AllowCustomPrefix: %v", err)
+	}
+}
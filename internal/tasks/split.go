@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultSplitSuffix is the title suffix SplitTask uses when none is given;
+// {n} is replaced by the part number.
+const DefaultSplitSuffix = "Part {n}"
+
+// SplitTask divides a backlog, active, or paused task into parts new backlog
+// tasks titled "<title> <suffix>", with {n} in suffix replaced by the part
+// number (1-based). Each part inherits the original's repo, type, and
+// priority and records it as its parent; the original records the new IDs
+// in split_into. It returns the new IDs.
+func (m *Manager) SplitTask(id string, parts int, suffix string) ([]string, error) {
+	if parts < 2 {
+		return nil, fmt.Errorf("a task must be split into at least 2 parts")
+	}
+	if suffix == "" {
+		suffix = DefaultSplitSuffix
+	}
+	if !strings.Contains(suffix, "{n}") {
+		return nil, fmt.Errorf("suffix %q must contain {n}", suffix)
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	original, filename, err := m.findTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if filename == "completed.md" {
+		return nil, fmt.Errorf("task %s is completed and cannot be split", id)
+	}
+
+	var ids []string
+	for n := 1; n <= parts; n++ {
+		newID, err := m.addToBacklog(Task{
+			Title:    original.Title + " " + strings.ReplaceAll(suffix, "{n}", strconv.Itoa(n)),
+			Repo:     original.Repo,
+			Type:     original.Type,
+			Priority: original.Priority,
+			Parent:   original.ID,
+		})
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, newID)
+	}
+
+	all := append(original.SplitInto, ids...)
+	if err := m.updateTask(id, []string{"split_into"}, map[string]string{"split_into": strings.Join(all, ", ")}); err != nil {
+		return ids, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,74 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitTask(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md": `# Backlog
+
+### [T-010] Migrate storage
+- **repo**: alpha
+- **type**: feature
+- **priority**: high
+- **tags**: big
+`,
+	})
+
+	ids, err := mgr.SplitTask("T-010", 3, "")
+	if err != nil {
+		t.Fatalf("SplitTask: %v", err)
+	}
+	if want := []string{"T-011", "T-012", "T-013"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+
+	part, err := mgr.GetTask("T-012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part.Title != "Migrate storage Part 2" || part.Repo != "alpha" || part.Type != "feature" ||
+		part.Priority != "high" || part.Parent != "T-010" || len(part.Tags) != 0 {
+		t.Errorf("unexpected part: %+v", part)
+	}
+	original, _ := mgr.GetTask("T-010")
+	if !reflect.DeepEqual(original.SplitInto, ids) {
+		t.Errorf("SplitInto = %v, want %v", original.SplitInto, ids)
+	}
+
+	// The parent link moves with the task.
+	if err := mgr.StartTask("T-011"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.CompleteTask("T-011"); err != nil {
+		t.Fatal(err)
+	}
+	if done, _ := mgr.GetTask("T-011"); done.Parent != "T-010" {
+		t.Errorf("completed part lost its parent: %+v", done)
+	}
+
+	ids, err = mgr.SplitTask("T-013", 2, "({n}/2)")
+	if err != nil {
+		t.Fatalf("SplitTask with suffix: %v", err)
+	}
+	if part, _ := mgr.GetTask(ids[1]); part.Title != "Migrate storage Part 3 (2/2)" {
+		t.Errorf("title = %q", part.Title)
+	}
+
+	for _, tc := range []struct {
+		id     string
+		parts  int
+		suffix string
+	}{
+		{"T-010", 1, ""},
+		{"T-010", 2, "Part"},
+		{"T-999", 2, ""},
+		{"T-011", 2, ""},
+	} {
+		if _, err := mgr.SplitTask(tc.id, tc.parts, tc.suffix); err == nil {
+			t.Errorf("SplitTask(%q, %d, %q): expected an error", tc.id, tc.parts, tc.suffix)
+		}
+	}
+}
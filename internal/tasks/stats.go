@@ -0,0 +1,109 @@
+package tasks
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats summarizes the task files.
+type Stats struct {
+	Backlog   int `json:"backlog"`
+	Active    int `json:"active"`
+	Paused    int `json:"paused"`
+	Completed int `json:"completed"`
+
+	// AvgCycleDays is the mean number of days from started_at to completed,
+	// over the CycleSamples completed tasks that record both.
+	AvgCycleDays float64 `json:"avg_cycle_days"`
+	CycleSamples int     `json:"cycle_samples"`
+
+	// MedianBacklogAgeDays is the median age of backlog tasks, over the
+	// AgeSamples tasks that record a created date.
+	MedianBacklogAgeDays float64 `json:"median_backlog_age_days"`
+	AgeSamples           int     `json:"age_samples"`
+
+	// ByPriority counts open (backlog, active, and paused) tasks by priority.
+	// Tasks without a priority are counted under "unset".
+	ByPriority map[string]int `json:"by_priority"`
+}
+
+// TaskStats computes counts, cycle time, and backlog age across all task files.
+func (m *Manager) TaskStats() (Stats, error) {
+	backlog, err := m.ListBacklog()
+	if err != nil {
+		return Stats{}, err
+	}
+	active, err := m.ListActive()
+	if err != nil {
+		return Stats{}, err
+	}
+	paused, err := m.ListPaused()
+	if err != nil {
+		return Stats{}, err
+	}
+	completed, err := m.ListCompleted()
+	if err != nil {
+		return Stats{}, err
+	}
+	return computeStats(backlog, active, paused, completed, time.Now()), nil
+}
+
+func computeStats(backlog, active, paused, completed []Task, now time.Time) Stats {
+	s := Stats{
+		Backlog:    len(backlog),
+		Active:     len(active),
+		Paused:     len(paused),
+		Completed:  len(completed),
+		ByPriority: make(map[string]int),
+	}
+
+	var cycleTotal float64
+	for _, t := range completed {
+		done, err := time.ParseInLocation(dueDateLayout, t.Completed, time.Local)
+		if err != nil || t.StartedAt.IsZero() {
+			continue
+		}
+		start := t.StartedAt.In(time.Local)
+		startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+		cycleTotal += done.Sub(startDay).Hours() / 24
+		s.CycleSamples++
+	}
+	if s.CycleSamples > 0 {
+		s.AvgCycleDays = cycleTotal / float64(s.CycleSamples)
+	}
+
+	var ages []float64
+	for _, t := range backlog {
+		created, err := time.ParseInLocation(dueDateLayout, t.Created, now.Location())
+		if err != nil {
+			continue
+		}
+		ages = append(ages, now.Sub(created).Hours()/24)
+	}
+	s.AgeSamples = len(ages)
+	s.MedianBacklogAgeDays = median(ages)
+
+	for _, list := range [][]Task{backlog, active, paused} {
+		for _, t := range list {
+			p := t.Priority
+			if p == "" {
+				p = "unset"
+			}
+			s.ByPriority[p]++
+		}
+	}
+	return s
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"os"
+	"time"
+)
+
+// DefaultMaxActiveDays is how many days a task may stay active before task
+// list marks it stuck.
+const DefaultMaxActiveDays = 7
+
+// IsStuck reports whether the task was started more than maxDays days before
+// now. Tasks without a started_at are never stuck.
+func (t Task) IsStuck(now time.Time, maxDays int) bool {
+	if t.StartedAt.IsZero() {
+		return false
+	}
+	return now.Sub(t.StartedAt) > time.Duration(maxDays)*24*time.Hour
+}
+
+// DaysActive returns the number of whole days since the task was started, or
+// zero if it has no started_at.
+func (t Task) DaysActive(now time.Time) int {
+	if t.StartedAt.IsZero() {
+		return 0
+	}
+	return int(now.Sub(t.StartedAt) / (24 * time.Hour))
+}
+
+// StuckTasks returns the active tasks started more than maxDays days ago,
+// with State set. A missing active.md means no tasks are stuck.
+func (m *Manager) StuckTasks(maxDays int) ([]Task, error) {
+	active, err := m.ListActive()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stuck []Task
+	now := time.Now()
+	for _, t := range active {
+		if t.IsStuck(now, maxDays) {
+			t.State = "active"
+			stuck = append(stuck, t)
+		}
+	}
+	return stuck, nil
+}
@@ -0,0 +1,57 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStuckTasks(t *testing.T) {
+	started := func(days int) string {
+		return time.Now().Add(-time.Duration(days)*24*time.Hour - time.Hour).UTC().Format(time.RFC3339)
+	}
+	mgr := newTestManager(t, map[string]string{
+		"active.md": fmt.Sprintf(`# Active
+
+### [T-001] Forgotten
+- **started_at**: %s
+
+### [T-002] Recent
+- **started_at**: %s
+
+### [T-003] No start time
+`, started(10), started(2)),
+	})
+
+	stuck, err := mgr.StuckTasks(7)
+	if err != nil {
+		t.Fatalf("StuckTasks: %v", err)
+	}
+	if len(stuck) != 1 || stuck[0].ID != "T-001" || stuck[0].State != "active" {
+		t.Fatalf("expected only T-001, got %+v", stuck)
+	}
+	if days := stuck[0].DaysActive(time.Now()); days != 10 {
+		t.Errorf("DaysActive = %d, want 10", days)
+	}
+
+	if stuck, _ := mgr.StuckTasks(1); len(stuck) != 2 {
+		t.Errorf("expected 2 tasks stuck for more than a day, got %+v", stuck)
+	}
+}
+
+func TestStuckTasks_NoActiveFile(t *testing.T) {
+	mgr := newTestManager(t, nil)
+	if err := os.Remove(filepath.Join(mgr.tasksDir, "active.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	stuck, err := mgr.StuckTasks(DefaultMaxActiveDays)
+	if err != nil {
+		t.Fatalf("StuckTasks without active.md: %v", err)
+	}
+	if len(stuck) != 0 {
+		t.Errorf("expected no stuck tasks, got %+v", stuck)
+	}
+}
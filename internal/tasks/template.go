@@ -0,0 +1,173 @@
+package tasks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// templatesDir holds task templates, relative to tasks/.
+const templatesDir = "templates"
+
+// Template is a reusable task definition loaded from tasks/templates/<name>.yaml.
+// Field values may contain Go template placeholders such as {{.repo}}.
+type Template struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	Repo        string `json:"repo,omitempty"`
+}
+
+// ListTemplates returns the templates in tasks/templates/, sorted by name. A
+// missing directory means there are no templates.
+func (m *Manager) ListTemplates() ([]Template, error) {
+	var paths []string
+	for _, ext := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(m.tasksDir, templatesDir, ext))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+
+	var list []Template
+	for _, path := range paths {
+		t, err := loadTemplate(path)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// LoadTemplate reads the named template from tasks/templates/.
+func (m *Manager) LoadTemplate(name string) (Template, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return Template{}, fmt.Errorf("invalid template name %q", name)
+	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(m.tasksDir, templatesDir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return loadTemplate(path)
+		}
+	}
+	return Template{}, fmt.Errorf("template %s not found in %s", name, filepath.Join(m.tasksDir, templatesDir))
+}
+
+func loadTemplate(path string) (Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	t, err := parseTemplate(name, string(data))
+	if err != nil {
+		return Template{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// parseTemplate reads the flat "key: value" YAML subset used by templates:
+// one scalar per line, optionally single- or double-quoted, with # comments.
+func parseTemplate(name, content string) (Template, error) {
+	t := Template{Name: name}
+	fields := map[string]*string{
+		"title":       &t.Title,
+		"description": &t.Description,
+		"type":        &t.Type,
+		"priority":    &t.Priority,
+		"repo":        &t.Repo,
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			return Template{}, fmt.Errorf("line %d: expected key: value", n)
+		}
+		dst, known := fields[strings.TrimSpace(key)]
+		if !known {
+			return Template{}, fmt.Errorf("line %d: unknown field %q", n, strings.TrimSpace(key))
+		}
+		v, err := yamlScalar(strings.TrimSpace(val))
+		if err != nil {
+			return Template{}, fmt.Errorf("line %d: %w", n, err)
+		}
+		*dst = v
+	}
+	if err := scanner.Err(); err != nil {
+		return Template{}, err
+	}
+	if t.Title == "" {
+		return Template{}, fmt.Errorf("template must have a title")
+	}
+	return t, nil
+}
+
+// yamlScalar unquotes a single-line YAML scalar and strips trailing comments
+// from plain values.
+func yamlScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid double-quoted value %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("invalid single-quoted value %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.HasPrefix(s, "|") || strings.HasPrefix(s, ">"):
+		return "", fmt.Errorf("multi-line values are not supported")
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s, nil
+}
+
+// Expand fills the template's placeholders from vars and returns the task to
+// add. Placeholders without a matching var are an error.
+func (t Template) Expand(vars map[string]string) (Task, error) {
+	var task Task
+	for _, f := range []struct {
+		name string
+		src  string
+		dst  *string
+	}{
+		{"title", t.Title, &task.Title},
+		{"description", t.Description, &task.Description},
+		{"type", t.Type, &task.Type},
+		{"priority", t.Priority, &task.Priority},
+		{"repo", t.Repo, &task.Repo},
+	} {
+		tmpl, err := template.New(f.name).Option("missingkey=error").Parse(f.src)
+		if err != nil {
+			return Task{}, fmt.Errorf("template %s: %s: %w", t.Name, f.name, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, vars); err != nil {
+			return Task{}, fmt.Errorf("template %s: %s: %w", t.Name, f.name, err)
+		}
+		if strings.ContainsAny(b.String(), "\r\n") {
+			return Task{}, fmt.Errorf("template %s: %s must expand to a single line", t.Name, f.name)
+		}
+		*f.dst = b.String()
+	}
+	return task, nil
+}
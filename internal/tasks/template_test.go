@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplates(t *testing.T) {
+	mgr := newTestManager(t, nil)
+	dir := filepath.Join(mgr.tasksDir, "templates")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "add-tests.yaml"), []byte(`# Boilerplate for test coverage work
+title: "Add unit tests for {{.package}}"
+description: Cover the {{.package}} package in {{.repo}} # trailing comment
+type: test
+priority: 'medium'
+repo: "{{.repo}}"
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "docs.yml"), []byte("title: Write docs\n"), 0644)
+
+	list, err := mgr.ListTemplates()
+	if err != nil {
+		t.Fatalf("ListTemplates: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "add-tests" || list[1].Name != "docs" {
+		t.Fatalf("unexpected templates: %+v", list)
+	}
+
+	tmpl, err := mgr.LoadTemplate("add-tests")
+	if err != nil {
+		t.Fatalf("LoadTemplate: %v", err)
+	}
+	task, err := tmpl.Expand(map[string]string{"repo": "myrepo", "package": "auth"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if task.Title != "Add unit tests for auth" || task.Description != "Cover the auth package in myrepo" ||
+		task.Type != "test" || task.Priority != "medium" || task.Repo != "myrepo" {
+		t.Errorf("unexpected expansion: %+v", task)
+	}
+
+	if _, err := tmpl.Expand(map[string]string{"repo": "myrepo"}); err == nil {
+		t.Error("expected error for missing var")
+	}
+	if _, err := mgr.LoadTemplate("nope"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+	if _, err := mgr.LoadTemplate("../backlog"); err == nil {
+		t.Error("expected error for path-like template name")
+	}
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	for _, content := range []string{
+		"description: no title\n",
+		"title: x\nowner: sam\n",
+		"title: x\ndescription: |\n  multi\n",
+		"title: \"unterminated\n",
+		"just a line\n",
+	} {
+		if _, err := parseTemplate("bad", content); err == nil {
+			t.Errorf("expected error parsing %q", content)
+		}
+	}
+}
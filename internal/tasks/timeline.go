@@ -0,0 +1,148 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// TimelineEntry holds the dates recorded for one task.
+type TimelineEntry struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	State string `json:"state"`
+	// Start is the task's started_at, and End its completed date. Either is
+	// zero when the task file does not record it.
+	Start time.Time `json:"start,omitzero"`
+	End   time.Time `json:"end,omitzero"`
+}
+
+// Timeline returns the start and completion dates of every task, in the
+// order ExportTasks lists them.
+func (m *Manager) Timeline() ([]TimelineEntry, error) {
+	list, err := m.ExportTasks([]string{"all"})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]TimelineEntry, 0, len(list))
+	for _, t := range list {
+		e := TimelineEntry{ID: t.ID, Title: t.Title, State: t.State, Start: t.StartedAt}
+		if end, err := time.ParseInLocation(dueDateLayout, t.Completed, time.Local); err == nil {
+			e.End = end
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// ganttLabelWidth is the width of the task column, including a trailing space.
+const ganttLabelWidth = 32
+
+// minGanttDays is the fewest day columns a page is given, however narrow
+// the requested width.
+const minGanttDays = 7
+
+// WriteGantt draws entries as a text Gantt chart, one row per task and one
+// column per day from the earliest recorded date to now. Completed tasks are
+// drawn with █ from start to completion, active and paused tasks with ▒ from
+// start to today, and backlog tasks are left blank. A completed task without
+// a start date is drawn on its completion day alone. When the range does not
+// fit in width columns the chart is split into pages of consecutive days.
+func WriteGantt(w io.Writer, entries []TimelineEntry, now time.Time, width int) {
+	today := dayOf(now)
+	var first time.Time
+	for _, e := range entries {
+		for _, t := range []time.Time{e.Start, e.End} {
+			if !t.IsZero() && (first.IsZero() || dayOf(t).Before(first)) {
+				first = dayOf(t)
+			}
+		}
+	}
+	if first.IsZero() {
+		fmt.Fprintln(w, "No started or completed tasks.")
+		return
+	}
+	if first.After(today) {
+		first = today
+	}
+
+	total := daysBetween(first, today) + 1
+	perPage := max(width-ganttLabelWidth-2, minGanttDays)
+	pages := (total + perPage - 1) / perPage
+
+	for page := 0; page < pages; page++ {
+		from := page * perPage
+		to := min(from+perPage, total)
+		if page > 0 {
+			fmt.Fprintln(w)
+		}
+		if pages > 1 {
+			fmt.Fprintf(w, "Page %d/%d: %s to %s\n", page+1, pages,
+				first.AddDate(0, 0, from).Format(dueDateLayout), first.AddDate(0, 0, to-1).Format(dueDateLayout))
+		}
+		fmt.Fprintf(w, "%-*s%s\n", ganttLabelWidth-1, "", ganttAxis(first, from, to))
+		for _, e := range entries {
+			label := e.ID + " " + e.Title
+			if r := []rune(label); len(r) > ganttLabelWidth-1 {
+				label = string(r[:ganttLabelWidth-2]) + "…"
+			}
+			fmt.Fprintf(w, "%-*s|%s|\n", ganttLabelWidth-1, label, ganttBar(e, first, today, from, to))
+		}
+	}
+}
+
+// ganttAxis labels the columns from day offset from to to with MM-DD at each
+// week boundary that has room for it. The first rune sits above the bar's
+// opening "|".
+func ganttAxis(first time.Time, from, to int) string {
+	axis := []rune(strings.Repeat(" ", to-from+2))
+	for i := from; i < to; i++ {
+		if (i-from)%7 != 0 || to-i < 5 {
+			continue
+		}
+		copy(axis[i-from+1:], []rune(first.AddDate(0, 0, i).Format("01-02")))
+	}
+	return strings.TrimRight(string(axis), " ")
+}
+
+// ganttBar renders a task's cells for day offsets from to to.
+func ganttBar(e TimelineEntry, first, today time.Time, from, to int) string {
+	start, end, fill := -1, -1, ' '
+	switch {
+	case e.State == "completed" && !e.End.IsZero():
+		end = daysBetween(first, dayOf(e.End))
+		start = end
+		if !e.Start.IsZero() {
+			start = daysBetween(first, dayOf(e.Start))
+		}
+		fill = '█'
+	case (e.State == "active" || e.State == "paused") && !e.Start.IsZero():
+		start = daysBetween(first, dayOf(e.Start))
+		end = daysBetween(first, today)
+		fill = '▒'
+	}
+
+	var b strings.Builder
+	for i := from; i < to; i++ {
+		if start >= 0 && i >= start && i <= end {
+			b.WriteRune(fill)
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// dayOf returns local midnight on t's date.
+func dayOf(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// daysBetween counts whole days from a to b, both at midnight. Rounding
+// absorbs daylight saving transitions.
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
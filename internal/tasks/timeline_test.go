@@ -0,0 +1,76 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	mgr := newTestManager(t, map[string]string{
+		"backlog.md":   "# Backlog\n\n### [B-1] Later\n",
+		"active.md":    "# Active\n\n### [A-1] Working\n- **started_at**: 2025-06-03T09:00:00Z\n",
+		"completed.md": "# Completed\n\n### [C-1] Done\n- **started_at**: 2025-06-01T09:00:00Z\n- **completed**: 2025-06-02\n",
+	})
+	os.WriteFile(filepath.Join(mgr.tasksDir, "paused.md"), []byte("# Paused\n"), 0644)
+
+	entries, err := mgr.Timeline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	byID := map[string]TimelineEntry{}
+	for _, e := range entries {
+		byID[e.ID] = e
+	}
+	if e := byID["C-1"]; e.State != "completed" || e.Start.IsZero() || e.End.Format(dueDateLayout) != "2025-06-02" {
+		t.Errorf("unexpected completed entry %+v", e)
+	}
+	if e := byID["A-1"]; e.State != "active" || e.Start.IsZero() || !e.End.IsZero() {
+		t.Errorf("unexpected active entry %+v", e)
+	}
+	if e := byID["B-1"]; !e.Start.IsZero() || !e.End.IsZero() {
+		t.Errorf("expected no dates for backlog entry, got %+v", e)
+	}
+}
+
+func TestWriteGantt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 12, 0, 0, 0, time.Local) }
+	entries := []TimelineEntry{
+		{ID: "C-1", Title: "Done", State: "completed", Start: day(1), End: day(3)},
+		{ID: "A-1", Title: "Working", State: "active", Start: day(4)},
+		{ID: "B-1", Title: "Later", State: "backlog"},
+	}
+
+	var b strings.Builder
+	WriteGantt(&b, entries, day(6), 80)
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected axis and 3 rows, got:\n%s", b.String())
+	}
+	if !strings.Contains(lines[0], "06-01") {
+		t.Errorf("expected axis to start at 06-01, got %q", lines[0])
+	}
+	for i, want := range []string{"|███   |", "|   ▒▒▒|", "|      |"} {
+		if !strings.HasSuffix(lines[i+1], want) {
+			t.Errorf("row %d: expected suffix %q, got %q", i, want, lines[i+1])
+		}
+	}
+
+	// A width too narrow for a week still pages by minGanttDays.
+	b.Reset()
+	WriteGantt(&b, entries, day(20), 10)
+	if !strings.Contains(b.String(), "Page 1/3: 2025-06-01 to 2025-06-07") || !strings.Contains(b.String(), "Page 3/3: 2025-06-15 to 2025-06-20") {
+		t.Errorf("expected three pages, got:\n%s", b.String())
+	}
+
+	b.Reset()
+	WriteGantt(&b, []TimelineEntry{{ID: "B-1", State: "backlog"}}, day(6), 80)
+	if !strings.Contains(b.String(), "No started or completed tasks") {
+		t.Errorf("expected empty message, got %q", b.String())
+	}
+}
@@ -0,0 +1,44 @@
+// Package version holds build-time version information shared by the
+// orchestrator binaries. Values are overridden via ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/PaulSnow/orchestrator/internal/version.Version=v1.2.3"
+package version
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	// Version is the release version of the binary.
+	Version = "dev"
+	// Commit is the git commit the binary was built from.
+	Commit = "unknown"
+	// BuildDate is when the binary was built, in RFC 3339 UTC.
+	BuildDate = "unknown"
+)
+
+// Major returns the major version of a release version such as v1.2.3, or
+// "" for dev builds and other strings that are not a release version.
+func Major(v string) string {
+	v = strings.TrimPrefix(v, "v")
+	major, _, _ := strings.Cut(v, ".")
+	if major == "" || strings.Trim(major, "0123456789") != "" {
+		return ""
+	}
+	return "v" + major
+}
+
+// WarnIfIncompatible prints a warning to stderr when the state file name was
+// written by a binary of a different major version than this one. Files
+// without a recorded version, and dev builds on either side, are not
+// checked. It reports whether it warned.
+func WarnIfIncompatible(name, writtenBy string) bool {
+	theirs, ours := Major(writtenBy), Major(Version)
+	if theirs == "" || ours == "" || theirs == ours {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s was written by orchestrator %s, this is %s; rerun to refresh it\n", name, writtenBy, Version)
+	return true
+}
@@ -0,0 +1,19 @@
+package version
+
+import "testing"
+
+func TestMajor(t *testing.T) {
+	for v, want := range map[string]string{
+		"v1.2.3":          "v1",
+		"2.0.0":           "v2",
+		"v10.1.0-rc1":     "v10",
+		"dev":             "",
+		"":                "",
+		"abc1234-dirty":   "",
+		"v1.2.3-4-gabcde": "v1",
+	} {
+		if got := Major(v); got != want {
+			t.Errorf("Major(%q) = %q, want %q", v, got, want)
+		}
+	}
+}
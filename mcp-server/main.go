@@ -2,11 +2,21 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
+
+	"github.com/PaulSnow/orchestrator/internal/repos"
+	"github.com/PaulSnow/orchestrator/internal/tasks"
+	"github.com/PaulSnow/orchestrator/internal/version"
 )
 
 const orchestratorRoot = "/home/paul/go/src/github.com/PaulSnow/orchestrator"
@@ -23,12 +33,18 @@ type Response struct {
 	Result interface{} `json:"result,omitempty"`
 	Error  *RpcError   `json:"error,omitempty"`
 	ID     interface{} `json:"id,omitempty"`
+	// RequestID is the server-assigned ID for this request, also used in
+	// its log lines and get-request-log. It is not part of JSON-RPC.
+	RequestID string `json:"x-request-id,omitempty"`
+	// Meta carries the tool and server versions for dispatched methods.
+	Meta *responseMeta `json:"meta,omitempty"`
 }
 
 // RpcError represents an error in the response.
 type RpcError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 func main() {
@@ -39,13 +55,34 @@ func main() {
 		rootPath = env
 	}
 
-	// Also allow override via -root flag for convenience.
+	// Also allow override via -root flag for convenience. --metrics-addr
+	// serves Prometheus metrics over HTTP and --ws accepts WebSocket clients,
+	// both alongside the stdin transport. WebSocket clients must present
+	// --ws-token, or ORCHESTRATOR_WS_TOKEN, as a bearer token, and cannot use
+	// run-command.
+	var metricsAddr, wsAddr string
+	wsToken := os.Getenv(wsTokenEnv)
 	for i, arg := range os.Args[1:] {
-		if arg == "-root" && i+1 < len(os.Args)-1 {
+		if i+1 >= len(os.Args)-1 {
+			break
+		}
+		switch arg {
+		case "-root":
 			rootPath = os.Args[i+2]
+		case "-metrics-addr", "--metrics-addr":
+			metricsAddr = os.Args[i+2]
+		case "-ws", "--ws":
+			wsAddr = os.Args[i+2]
+		case "-ws-token", "--ws-token":
+			wsToken = os.Args[i+2]
 		}
 	}
 
+	if wsAddr != "" && wsToken == "" {
+		fmt.Fprintf(os.Stderr, "--ws requires a token: set --ws-token or %s\n", wsTokenEnv)
+		os.Exit(1)
+	}
+
 	// Resolve to absolute path.
 	absPath, err := filepath.Abs(rootPath)
 	if err == nil {
@@ -59,45 +96,143 @@ func main() {
 	}
 	defer srv.Shutdown()
 
+	if metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", srv.metrics.Handler())
+		go func() {
+			if err := http.ListenAndServe(metricsAddr, mux); err != nil {
+				logf("ERROR", "metrics server: %v", err)
+			}
+		}()
+		fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", metricsAddr)
+	}
+
+	var ws *wsServer
+	if wsAddr != "" {
+		ws = startWebSocket(srv, wsAddr, wsToken)
+		fmt.Fprintf(os.Stderr, "Accepting WebSocket clients on %s\n", ws.http.Addr)
+	}
+
 	fmt.Fprintf(os.Stderr, "orchestrator-mcp-server ready (root: %s)\n", rootPath)
 	fmt.Fprintf(os.Stderr, "Reading JSON requests from stdin. One JSON object per line.\n")
 
+	if ws == nil {
+		if err := serveStdin(srv); err != nil {
+			fmt.Fprintf(os.Stderr, "stdin read error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// With WebSocket clients, closing stdin does not stop the server; an
+	// interrupt or SIGTERM does, after closing every connection.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stdinDone := make(chan error, 1)
+	go func() { stdinDone <- serveStdin(srv) }()
+
+	select {
+	case err := <-stdinDone:
+		if err != nil {
+			logf("WARN", "stdin read error: %v", err)
+		}
+		logf("INFO", "stdin closed; serving WebSocket clients until interrupted")
+		<-signals
+	case <-signals:
+	}
+	logf("INFO", "shutting down")
+	ws.Close()
+}
+
+// serveStdin answers newline-delimited requests from stdin on stdout until
+// stdin is closed.
+func serveStdin(srv *Server) error {
 	scanner := bufio.NewScanner(os.Stdin)
 	// Allow up to 1MB per line for large responses.
 	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
 			continue
 		}
-
-		var req Request
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
-			writeResponse(Response{
-				Error: &RpcError{Code: -32700, Message: "parse error: " + err.Error()},
-			})
-			continue
+		if resp, ok := handleRequest(srv, line, false); ok {
+			writeResponse(resp)
 		}
+	}
+	return scanner.Err()
+}
+
+// stdinOnlyMethods are refused over WebSocket: they run arbitrary programs,
+// so they are only offered to the local process that started the server.
+var stdinOnlyMethods = map[string]bool{
+	"run-command": true,
+}
 
-		resp := dispatch(srv, req)
-		resp.ID = req.ID
-		writeResponse(resp)
+// handleRequest decodes and dispatches one request; remote is true for
+// requests arriving over WebSocket. ok is false for notifications, which
+// must not be answered. It is safe to call from several goroutines at once.
+func handleRequest(srv *Server, data []byte, remote bool) (resp Response, ok bool) {
+	start := time.Now()
+	requestID := newRequestID()
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		resp = Response{
+			Error:     &RpcError{Code: -32700, Message: "parse error: " + err.Error()},
+			RequestID: requestID,
+		}
+		srv.logRequest(requestID, "", start, resp)
+		return resp, true
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "stdin read error: %v\n", err)
-		os.Exit(1)
+	logf("DEBUG", "method=%s id=%s start", req.Method, requestID)
+	if remote && stdinOnlyMethods[req.Method] {
+		resp = errorResponse(-32601, req.Method+" is only available over stdin")
+	} else {
+		resp = dispatch(srv, req)
+	}
+	srv.metrics.ObserveRequest(req.Method, resp.Error != nil, time.Since(start))
+	srv.logRequest(requestID, req.Method, start, resp)
+	if isNotification(req.Method) {
+		return Response{}, false
 	}
+	resp.ID = req.ID
+	resp.RequestID = requestID
+	resp.Meta = metaFor(req.Method)
+	return resp, true
+}
+
+// isNotification reports whether a method is a client notification that
+// must not receive a response.
+func isNotification(method string) bool {
+	return method == "initialized"
 }
 
 func dispatch(srv *Server, req Request) Response {
 	switch req.Method {
 
+	case "initialize":
+		return Response{Result: initializeResult()}
+
+	case "initialized":
+		return Response{}
+
 	case "scan-repos":
 		result, err := ToolScanRepos(srv)
 		return makeResponse(result, err)
 
+	case "diff-repos":
+		result, err := ToolDiffRepos(srv)
+		return makeResponse(result, err)
+
+	case "search-repos":
+		filter, err := parseRepoSearchParams(req.Params)
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolSearchRepos(srv, filter)
+		return makeResponse(result, err)
+
 	case "repo-status":
 		name, err := extractStringParam(req.Params, "repo")
 		if err != nil {
@@ -111,7 +246,27 @@ func dispatch(srv *Server, req Request) Response {
 		if err != nil {
 			return errorResponse(-32602, "invalid params: "+err.Error())
 		}
-		result, err := ToolRunTests(srv, name)
+		junitOutput, err := extractOptionalStringParam(req.Params, "junit_output")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolRunTests(srv, name, junitOutput)
+		return makeResponse(result, err)
+
+	case "run-race-tests":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolRunRaceTests(srv, name)
+		return makeResponse(result, err)
+
+	case "run-tests-verbose":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolRunTestsVerbose(srv, name)
 		return makeResponse(result, err)
 
 	case "build-repo":
@@ -122,8 +277,180 @@ func dispatch(srv *Server, req Request) Response {
 		result, err := ToolBuildRepo(srv, name)
 		return makeResponse(result, err)
 
+	case "sync-repo":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolSyncRepo(srv, name)
+		return makeResponse(result, err)
+
+	case "run-command":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		command, err := extractStringParam(req.Params, "command")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		if command == "" {
+			return errorResponse(-32602, "invalid params: command must not be empty")
+		}
+		args, err := extractStringSliceParam(req.Params, "args")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolRunCommand(srv, name, command, args)
+		return makeResponse(result, err)
+
+	case "lint-repo":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolLintRepo(srv, name)
+		return makeResponse(result, err)
+
+	case "benchmark-repo":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		bench, err := extractOptionalStringParam(req.Params, "bench")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		save, err := extractBoolParam(req.Params, "save_baseline")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolBenchmarkRepo(srv, name, bench, save)
+		return makeResponse(result, err)
+
+	case "get-log":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		logType, err := extractStringParam(req.Params, "type")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		lines, err := extractIntParam(req.Params, "lines", 100)
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolGetLog(srv, name, logType, lines)
+		return makeResponse(result, err)
+
+	case "list-logs":
+		result, err := ToolListLogs(srv)
+		return makeResponse(result, err)
+
+	case "git-log":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		limit, err := extractIntParam(req.Params, "limit", 10)
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		if limit < 1 || limit > maxGitLogLimit {
+			return errorResponse(-32602, fmt.Sprintf("invalid params: limit must be between 1 and %d", maxGitLogLimit))
+		}
+		branch, err := extractOptionalStringParam(req.Params, "branch")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolGitLog(srv, name, branch, limit)
+		return makeResponse(result, err)
+
+	case "git-diff":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		staged, err := extractBoolParam(req.Params, "staged")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		contextLines, err := extractIntParam(req.Params, "context_lines", 3)
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		if contextLines < 0 {
+			return errorResponse(-32602, "invalid params: context_lines must not be negative")
+		}
+		result, err := ToolGitDiff(srv, name, staged, contextLines)
+		return makeResponse(result, err)
+
+	case "create-branch":
+		name, err := extractStringParam(req.Params, "repo")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		branch, err := extractStringParam(req.Params, "branch")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		from, err := extractOptionalStringParam(req.Params, "from")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		if err := repos.ValidateRefName(branch); err != nil {
+			return errorResponse(-32602, "invalid params: branch: "+err.Error())
+		}
+		if from != "" {
+			if err := repos.ValidateRefName(from); err != nil {
+				return errorResponse(-32602, "invalid params: from: "+err.Error())
+			}
+		}
+		result, err := ToolCreateBranch(srv, name, branch, from)
+		return makeResponse(result, err)
+
 	case "list-tasks":
-		result, err := ToolListTasks(srv)
+		includeCompleted, err := extractBoolParam(req.Params, "include_completed")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		assignedTo, err := extractOptionalStringParam(req.Params, "assigned_to")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolListTasks(srv, includeCompleted, assignedTo)
+		return makeResponse(result, err)
+
+	case "list-overdue-tasks":
+		result, err := ToolListOverdueTasks(srv)
+		return makeResponse(result, err)
+
+	case "check-stuck-tasks":
+		maxDays, err := extractIntParam(req.Params, "max_active_days", tasks.DefaultMaxActiveDays)
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		if maxDays < 0 {
+			return errorResponse(-32602, "invalid params: max_active_days must not be negative")
+		}
+		result, err := ToolCheckStuckTasks(srv, maxDays)
+		return makeResponse(result, err)
+
+	case "create-task":
+		params, err := parseCreateTaskParams(req.Params)
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolCreateTask(srv, params)
+		return makeResponse(result, err)
+
+	case "update-task":
+		id, updates, err := parseUpdateTaskParams(req.Params)
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolUpdateTask(srv, id, updates)
 		return makeResponse(result, err)
 
 	case "start-task":
@@ -134,6 +461,26 @@ func dispatch(srv *Server, req Request) Response {
 		result, err := ToolStartTask(srv, id)
 		return makeResponse(result, err)
 
+	case "assign-task":
+		id, err := extractStringParam(req.Params, "id")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		assignee, err := extractStringParam(req.Params, "assignee")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolAssignTask(srv, id, assignee)
+		return makeResponse(result, err)
+
+	case "unassign-task":
+		id, err := extractStringParam(req.Params, "id")
+		if err != nil {
+			return errorResponse(-32602, "invalid params: "+err.Error())
+		}
+		result, err := ToolUnassignTask(srv, id)
+		return makeResponse(result, err)
+
 	case "complete-task":
 		id, err := extractStringParam(req.Params, "id")
 		if err != nil {
@@ -145,45 +492,235 @@ func dispatch(srv *Server, req Request) Response {
 	case "list-tools":
 		return Response{Result: listTools()}
 
+	case "get-request-log":
+		return Response{Result: srv.requests.snapshot()}
+
+	case "tool-changelog":
+		return Response{Result: toolChangelog}
+
+	case "reload-config":
+		count, err := srv.ReloadConfig()
+		if err != nil {
+			return errorResponse(-32000, err.Error())
+		}
+		return Response{Result: map[string]interface{}{"reloaded": true, "repos": count}}
+
 	default:
 		return errorResponse(-32601, "unknown method: "+req.Method)
 	}
 }
 
-// listTools returns metadata about all available tools.
+// initializeResult returns the capability document sent in reply to "initialize".
+func initializeResult() map[string]interface{} {
+	var names []string
+	for _, t := range listTools() {
+		names = append(names, t["name"].(string))
+	}
+	return map[string]interface{}{
+		"serverInfo": map[string]interface{}{
+			"name":    "orchestrator",
+			"version": version.Version,
+		},
+		"tools": names,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{"listChanged": false},
+		},
+	}
+}
+
+// listTools returns metadata about all available tools, each with its
+// current version.
 func listTools() []map[string]interface{} {
-	return []map[string]interface{}{
+	tools := []map[string]interface{}{
 		{
 			"name":        "scan-repos",
 			"description": "Scan all configured repositories and return their git statuses",
 			"params":      map[string]interface{}{},
 		},
+		{
+			"name":        "diff-repos",
+			"description": "Scan all repositories and return the changes since the previous scan, or no_previous_scan if there is none",
+			"params":      map[string]interface{}{},
+		},
+		{
+			"name":        "search-repos",
+			"description": "Filter the last scan in state/repo-status.json without rescanning; omitted filters match everything. Adds \"stale\": true when the scan is over 5 minutes old",
+			"params": map[string]interface{}{
+				"language": "string (optional) - configured language, e.g. go",
+				"tag":      "string (optional) - configured tag",
+				"dirty":    "boolean (optional) - true for repos with uncommitted work, false for clean ones",
+				"missing":  "boolean (optional) - true for repos whose checkout does not exist",
+			},
+		},
 		{
 			"name":        "repo-status",
-			"description": "Get the git status of a single named repository",
+			"description": "Get the git status of a single named repository, including go_module and go_version from go.mod",
 			"params": map[string]interface{}{
 				"repo": "string (required) - repository name",
 			},
 		},
 		{
 			"name":        "run-tests",
-			"description": "Run tests for a named repository",
+			"description": "Run tests for a named repository; failed Go runs include failed_tests with each failing test's output, and output holds the last 50 lines the command printed; log_checksum is the SHA-256 of log_file",
+			"params": map[string]interface{}{
+				"repo":         "string (required) - repository name",
+				"junit_output": "string (optional) - also write the result as JUnit XML to this path, relative to state/",
+			},
+		},
+		{
+			"name":        "run-race-tests",
+			"description": "Run Go tests with the race detector; race_conditions lists each reported data race with its goroutines and location",
+			"params": map[string]interface{}{
+				"repo": "string (required) - repository name",
+			},
+		},
+		{
+			"name":        "run-tests-verbose",
+			"description": "Run Go tests with -json and return every test event plus failed test output",
 			"params": map[string]interface{}{
 				"repo": "string (required) - repository name",
 			},
 		},
 		{
 			"name":        "build-repo",
-			"description": "Build a named repository",
+			"description": "Build a named repository; output holds the last 50 lines the build printed",
+			"params": map[string]interface{}{
+				"repo": "string (required) - repository name",
+			},
+		},
+		{
+			"name":        "sync-repo",
+			"description": "Run git fetch origin and git pull --ff-only in a named repository; returns both results plus synced, was_up_to_date, and failure_kind",
 			"params": map[string]interface{}{
 				"repo": "string (required) - repository name",
 			},
 		},
+		{
+			"name":        "run-command",
+			"description": "Run an arbitrary command in a named repository's directory, logging to /tmp/orchestrator-run-<repo>.log; stdin transport only",
+			"params": map[string]interface{}{
+				"repo":    "string (required) - repository name",
+				"command": "string (required) - program to run, e.g. go",
+				"args":    "array of strings (optional) - arguments, e.g. [\"mod\", \"tidy\"]",
+			},
+		},
+		{
+			"name":        "lint-repo",
+			"description": "Build a named repository and run static analysis on it",
+			"params": map[string]interface{}{
+				"repo": "string (required) - repository name",
+			},
+		},
+		{
+			"name":        "benchmark-repo",
+			"description": "Run Go benchmarks for a repository and compare them to the stored baseline",
+			"params": map[string]interface{}{
+				"repo":          "string (required) - repository name",
+				"bench":         "string (optional) - benchmark regex (default \".\")",
+				"save_baseline": "bool (optional) - store these results as the new baseline",
+			},
+		},
+		{
+			"name":        "get-log",
+			"description": "Return the header, exit footer, and last N output lines of a build, test, or sync log for a repository",
+			"params": map[string]interface{}{
+				"repo":  "string (required) - repository name",
+				"type":  "string (required) - log type, e.g. build, test, sync-pull",
+				"lines": "int (optional) - number of lines to return (default 100)",
+			},
+		},
+		{
+			"name":        "list-logs",
+			"description": "List all /tmp/orchestrator-*.log files with sizes and modification times",
+			"params":      map[string]interface{}{},
+		},
+		{
+			"name":        "git-log",
+			"description": "List recent commits in a repository (sha, author, time, subject)",
+			"params": map[string]interface{}{
+				"repo":   "string (required) - repository name",
+				"limit":  "int (optional) - number of commits, 1-100 (default 10)",
+				"branch": "string (optional) - branch or ref to list (default HEAD)",
+			},
+		},
+		{
+			"name":        "git-diff",
+			"description": "Return the unified diff of uncommitted changes in a repository, up to 100 KB; truncated is set when the diff was cut, and diff is empty when there are no changes",
+			"params": map[string]interface{}{
+				"repo":          "string (required) - repository name",
+				"staged":        "bool (optional) - diff the index against HEAD (git diff --cached) instead of the working tree",
+				"context_lines": "int (optional) - lines of context around each change (default 3)",
+			},
+		},
+		{
+			"name":        "create-branch",
+			"description": "Create and check out a git branch with git checkout -b; fails with error data branch_exists if the branch already exists",
+			"params": map[string]interface{}{
+				"repo":   "string (required) - repository name",
+				"branch": "string (required) - new branch name; whitespace and shell metacharacters are rejected",
+				"from":   "string (optional) - branch or ref to start from (default HEAD)",
+			},
+		},
+		{
+			"name":        "tool-changelog",
+			"description": "Return the version history of every tool, keyed by tool name; the last entry is the current version reported in each response's meta.tool_version",
+			"params":      map[string]interface{}{},
+		},
+		{
+			"name":        "get-request-log",
+			"description": "Return the last 1000 handled requests, oldest first, with request ID, method, start time, duration_ms, and status",
+			"params":      map[string]interface{}{},
+		},
+		{
+			"name":        "reload-config",
+			"description": "Reload config/repos.json immediately and return the new repo count",
+			"params":      map[string]interface{}{},
+		},
 		{
 			"name":        "list-tasks",
 			"description": "List all backlog and active tasks",
+			"params": map[string]interface{}{
+				"include_completed": "bool (optional) - also return completed tasks",
+				"assigned_to":       "string (optional) - only tasks assigned to this name, case-insensitive",
+			},
+		},
+		{
+			"name":        "list-overdue-tasks",
+			"description": "List backlog and active tasks whose due date has passed",
 			"params":      map[string]interface{}{},
 		},
+		{
+			"name":        "check-stuck-tasks",
+			"description": "List active tasks started more than max_active_days ago, with started_at and days_active",
+			"params": map[string]interface{}{
+				"max_active_days": "int (optional) - days a task may stay active (default 7)",
+			},
+		},
+		{
+			"name":        "create-task",
+			"description": "Add a new task to the backlog and return its generated ID",
+			"params": map[string]interface{}{
+				"title":       "string (required) - task title",
+				"repo":        "string (optional) - repository the task applies to",
+				"type":        "string (optional) - task type, e.g. feature, bugfix",
+				"priority":    "string (optional) - high, medium, or low",
+				"description": "string (optional) - task description",
+				"links":       "array of strings (optional) - issue, ticket, or PR URLs",
+			},
+		},
+		{
+			"name":        "update-task",
+			"description": "Change fields of a backlog, active, or paused task and return the updated task; omitted fields are left alone and an empty string removes a field",
+			"params": map[string]interface{}{
+				"id":          "string (required) - task ID",
+				"repo":        "string (optional) - repository the task applies to",
+				"type":        "string (optional) - task type, e.g. feature, bugfix",
+				"priority":    "string (optional) - high, medium, or low",
+				"description": "string (optional) - task description; may span several lines",
+				"due_date":    "string (optional) - due date as YYYY-MM-DD",
+				"links":       "array of strings (optional) - replaces the task's URLs; an empty array removes them",
+			},
+		},
 		{
 			"name":        "start-task",
 			"description": "Move a task from backlog to active by ID",
@@ -191,6 +728,21 @@ func listTools() []map[string]interface{} {
 				"id": "string (required) - task ID",
 			},
 		},
+		{
+			"name":        "assign-task",
+			"description": "Set the assignee of a backlog, active, or paused task",
+			"params": map[string]interface{}{
+				"id":       "string (required) - task ID",
+				"assignee": "string (required) - username or agent name",
+			},
+		},
+		{
+			"name":        "unassign-task",
+			"description": "Clear the assignee of a backlog, active, or paused task",
+			"params": map[string]interface{}{
+				"id": "string (required) - task ID",
+			},
+		},
 		{
 			"name":        "complete-task",
 			"description": "Complete a task by ID (move from active to completed)",
@@ -199,6 +751,10 @@ func listTools() []map[string]interface{} {
 			},
 		},
 	}
+	for _, t := range tools {
+		t["version"] = toolVersion(t["name"].(string))
+	}
+	return tools
 }
 
 // extractStringParam pulls a named string from JSON params.
@@ -229,9 +785,247 @@ func extractStringParam(raw json.RawMessage, key string) (string, error) {
 	return "", fmt.Errorf("params must be an object with %q key or a bare string", key)
 }
 
+// createTaskParams are the accepted create-task params.
+type createTaskParams struct {
+	Title       string `json:"title"`
+	Repo        string `json:"repo"`
+	Type        string `json:"type"`
+	Priority    string `json:"priority"`
+	Description string `json:"description"`
+	// Links are issue, ticket, or PR URLs; see tasks.CheckLink.
+	Links []string `json:"links"`
+}
+
+// parseCreateTaskParams decodes create-task params, rejecting unknown fields,
+// a missing title, and priorities other than high, medium, or low.
+func parseCreateTaskParams(raw json.RawMessage) (createTaskParams, error) {
+	var p createTaskParams
+	if len(raw) == 0 {
+		return p, fmt.Errorf("title is required")
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return p, err
+	}
+	if strings.TrimSpace(p.Title) == "" {
+		return p, fmt.Errorf("title is required")
+	}
+	switch p.Priority {
+	case "", "high", "medium", "low":
+	default:
+		return p, fmt.Errorf("priority must be high, medium, or low")
+	}
+	for _, link := range p.Links {
+		if err := tasks.CheckLink(link); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// updateTaskFields maps update-task params to task file field names.
+var updateTaskFields = map[string]string{
+	"repo":        "repo",
+	"type":        "type",
+	"priority":    "priority",
+	"description": "description",
+	"due_date":    "due",
+}
+
+// parseUpdateTaskParams decodes update-task params into a task ID and the
+// field updates to apply. Unknown keys, non-string values other than the
+// links array, and invalid priorities, due dates, or links are rejected.
+func parseUpdateTaskParams(raw json.RawMessage) (string, map[string]string, error) {
+	var obj map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &obj) != nil {
+		return "", nil, fmt.Errorf("params must be an object with an id")
+	}
+
+	var id string
+	updates := make(map[string]string)
+	for key, val := range obj {
+		if key == "links" {
+			var links []string
+			if err := json.Unmarshal(val, &links); err != nil {
+				return "", nil, fmt.Errorf("links must be an array of strings")
+			}
+			for _, link := range links {
+				if err := tasks.CheckLink(link); err != nil {
+					return "", nil, err
+				}
+			}
+			updates["links"] = strings.Join(links, ", ")
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(val, &s); err != nil {
+			return "", nil, fmt.Errorf("%s must be a string", key)
+		}
+		if key == "id" {
+			id = s
+			continue
+		}
+		field, ok := updateTaskFields[key]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown field %q", key)
+		}
+		updates[field] = s
+	}
+
+	if id == "" {
+		return "", nil, fmt.Errorf("missing required parameter: id")
+	}
+	if len(updates) == 0 {
+		return "", nil, fmt.Errorf("no fields to update")
+	}
+	switch updates["priority"] {
+	case "", "high", "medium", "low":
+	default:
+		return "", nil, fmt.Errorf("priority must be high, medium, or low")
+	}
+	if due := updates["due"]; due != "" {
+		if _, err := time.Parse("2006-01-02", due); err != nil {
+			return "", nil, fmt.Errorf("due_date must be YYYY-MM-DD")
+		}
+	}
+	return id, updates, nil
+}
+
+// extractOptionalStringParam pulls an optional named string from JSON object
+// params. Missing params or a missing key yield "".
+func extractOptionalStringParam(raw json.RawMessage, key string) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return "", fmt.Errorf("params must be an object")
+	}
+	v, ok := obj[key]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return s, nil
+}
+
+// extractStringSliceParam pulls an optional named array of strings from JSON
+// object params. Missing params or a missing key yield nil.
+func extractStringSliceParam(raw json.RawMessage, key string) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("params must be an object")
+	}
+	v, ok := obj[key]
+	if !ok {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal(v, &list); err != nil {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	return list, nil
+}
+
+// extractIntParam pulls an optional named integer from JSON object params,
+// returning def when it is absent.
+func extractIntParam(raw json.RawMessage, key string, def int) (int, error) {
+	if len(raw) == 0 {
+		return def, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return def, nil
+	}
+	v, ok := obj[key]
+	if !ok {
+		return def, nil
+	}
+	n, ok := v.(float64)
+	if !ok || n != float64(int(n)) {
+		return 0, fmt.Errorf("%s must be an integer", key)
+	}
+	return int(n), nil
+}
+
+// extractBoolParam pulls an optional named boolean from JSON object params.
+// Missing params or a missing key yield false.
+func extractBoolParam(raw json.RawMessage, key string) (bool, error) {
+	if len(raw) == 0 {
+		return false, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return false, fmt.Errorf("params must be an object")
+	}
+	v, ok := obj[key]
+	if !ok {
+		return false, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+	return b, nil
+}
+
+// extractOptionalBoolParam pulls an optional named boolean from JSON object
+// params, returning nil when it is absent.
+func extractOptionalBoolParam(raw json.RawMessage, key string) (*bool, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("params must be an object")
+	}
+	v, ok := obj[key]
+	if !ok {
+		return nil, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a boolean", key)
+	}
+	return &b, nil
+}
+
+// parseRepoSearchParams reads the search-repos filters.
+func parseRepoSearchParams(raw json.RawMessage) (repoSearch, error) {
+	var f repoSearch
+	var err error
+	if f.Language, err = extractOptionalStringParam(raw, "language"); err != nil {
+		return f, err
+	}
+	if f.Tag, err = extractOptionalStringParam(raw, "tag"); err != nil {
+		return f, err
+	}
+	if f.Dirty, err = extractOptionalBoolParam(raw, "dirty"); err != nil {
+		return f, err
+	}
+	if f.Missing, err = extractOptionalBoolParam(raw, "missing"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
 func makeResponse(result string, err error) Response {
 	if err != nil {
-		return errorResponse(-32000, err.Error())
+		resp := errorResponse(-32000, err.Error())
+		switch {
+		case errors.Is(err, repos.ErrRepoMissing):
+			resp.Error.Data = map[string]interface{}{"repo_missing": true}
+		case errors.Is(err, repos.ErrBranchExists):
+			resp.Error.Data = map[string]interface{}{"branch_exists": true}
+		}
+		return resp
 	}
 	// Return the result string as raw JSON if it's valid JSON, otherwise as a string.
 	var js json.RawMessage
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/PaulSnow/orchestrator/internal/repos"
+	"github.com/PaulSnow/orchestrator/internal/tasks"
+)
+
+// serverMetrics holds the Prometheus collectors for the server. Metrics are
+// always recorded; they are only served when a metrics address is configured.
+type serverMetrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	repos    *prometheus.GaugeVec
+}
+
+func newServerMetrics(mgr *tasks.Manager) *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orchestrator_rpc_requests_total",
+			Help: "RPC requests handled, by method and status (ok or error).",
+		}, []string{"method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "orchestrator_rpc_duration_seconds",
+			Help: "RPC handling time by method.",
+			// Tools range from instant lookups to multi-minute test runs.
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 9),
+		}, []string{"method"}),
+		repos: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "orchestrator_repos_total",
+			Help: "Repositories by state as of the last scan.",
+		}, []string{"state"}),
+	}
+	m.registry.MustRegister(m.requests, m.duration, m.repos, taskCollector{mgr})
+	return m
+}
+
+// ObserveRequest records one handled request. Methods outside the known set
+// are recorded as "unknown" to keep label cardinality bounded.
+func (m *serverMetrics) ObserveRequest(method string, failed bool, elapsed time.Duration) {
+	if !knownMethods[method] {
+		method = "unknown"
+	}
+	status := "ok"
+	if failed {
+		status = "error"
+	}
+	m.requests.WithLabelValues(method, status).Inc()
+	m.duration.WithLabelValues(method).Observe(elapsed.Seconds())
+}
+
+// SetRepoStatuses replaces the repo state gauge with the results of a scan.
+func (m *serverMetrics) SetRepoStatuses(statuses []repos.RepoStatus) {
+	counts := map[string]int{"clean": 0, "dirty": 0, "missing": 0, "error": 0}
+	for _, s := range statuses {
+		counts[repoState(s)]++
+	}
+	for state, n := range counts {
+		m.repos.WithLabelValues(state).Set(float64(n))
+	}
+}
+
+// Handler serves the registry in Prometheus text format.
+func (m *serverMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func repoState(s repos.RepoStatus) string {
+	switch {
+	case !s.Exists:
+		return "missing"
+	case s.Error != "":
+		return "error"
+	case !s.Clean:
+		return "dirty"
+	default:
+		return "clean"
+	}
+}
+
+// knownMethods is every method dispatch accepts.
+var knownMethods = func() map[string]bool {
+	m := map[string]bool{"initialize": true, "initialized": true}
+	for _, tool := range listTools() {
+		m[tool["name"].(string)] = true
+	}
+	return m
+}()
+
+var taskDesc = prometheus.NewDesc("orchestrator_tasks_total", "Tasks by state.", []string{"state"}, nil)
+
+// taskCollector reads task counts from tasks/*.md at scrape time, so the
+// gauge reflects edits made outside the server too.
+type taskCollector struct {
+	mgr *tasks.Manager
+}
+
+func (c taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- taskDesc
+}
+
+func (c taskCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.mgr.TaskStats()
+	if err != nil {
+		logf("WARN", "collecting task metrics: %v", err)
+		return
+	}
+	for state, n := range map[string]int{
+		"backlog":   stats.Backlog,
+		"active":    stats.Active,
+		"paused":    stats.Paused,
+		"completed": stats.Completed,
+	} {
+		ch <- prometheus.MustNewConstMetric(taskDesc, prometheus.GaugeValue, float64(n), state)
+	}
+}
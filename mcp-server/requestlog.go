@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// requestLogSize is how many completed requests get-request-log returns.
+const requestLogSize = 1000
+
+// requestLogEntry records one handled request.
+type requestLogEntry struct {
+	RequestID  string    `json:"request_id"`
+	Method     string    `json:"method"`
+	Start      time.Time `json:"start"`
+	DurationMS int64     `json:"duration_ms"`
+	Status     string    `json:"status"`
+	Code       int       `json:"code,omitempty"`
+}
+
+// requestLog keeps the most recent requestLogSize entries in a ring buffer.
+type requestLog struct {
+	mu      sync.Mutex
+	entries []requestLogEntry
+	next    int
+	full    bool
+}
+
+func newRequestLog() *requestLog {
+	return &requestLog{entries: make([]requestLogEntry, requestLogSize)}
+}
+
+// add records an entry, overwriting the oldest once the buffer is full.
+func (l *requestLog) add(e requestLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[l.next] = e
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// snapshot returns the recorded entries, oldest first.
+func (l *requestLog) snapshot() []requestLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]requestLogEntry, 0, len(l.entries))
+	if l.full {
+		out = append(out, l.entries[l.next:]...)
+	}
+	return append(out, l.entries[:l.next]...)
+}
+
+// newRequestID returns a random (version 4) UUID.
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// logRequest writes the completion line for a request and records it.
+func (s *Server) logRequest(id, method string, start time.Time, resp Response) {
+	e := requestLogEntry{
+		RequestID:  id,
+		Method:     method,
+		Start:      start,
+		DurationMS: time.Since(start).Milliseconds(),
+		Status:     "ok",
+	}
+	if resp.Error != nil {
+		e.Status, e.Code = "error", resp.Error.Code
+		logf("INFO", "method=%s id=%s duration_ms=%d status=error code=%d", method, id, e.DurationMS, e.Code)
+	} else {
+		logf("INFO", "method=%s id=%s duration_ms=%d status=ok", method, id, e.DurationMS)
+	}
+	s.requests.add(e)
+}
@@ -2,33 +2,155 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/PaulSnow/orchestrator/internal/config"
 	"github.com/PaulSnow/orchestrator/internal/tasks"
 )
 
+// configPollInterval is how often repos.json and repos.local.json are checked
+// for changes.
+const configPollInterval = 30 * time.Second
+
 // Server holds the orchestrator configuration and provides access to tools.
 type Server struct {
-	Config   *config.Config
 	TaskMgr  *tasks.Manager
 	RootPath string
+
+	mu          sync.RWMutex
+	cfg         *config.Config
+	configMtime configMtimes
+	stop        chan struct{}
+	metrics     *serverMetrics
+	requests    *requestLog
 }
 
 // NewServer creates a new MCP server with the given orchestrator root path.
+// The server polls config/repos.json and repos.local.json in the background
+// and reloads them when either modification time changes.
 func NewServer(rootPath string) (*Server, error) {
 	cfg, err := config.Load(rootPath)
 	if err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
 
-	return &Server{
-		Config:   cfg,
+	s := &Server{
 		TaskMgr:  tasks.NewManager(rootPath),
 		RootPath: rootPath,
-	}, nil
+		cfg:      cfg,
+		stop:     make(chan struct{}),
+		requests: newRequestLog(),
+	}
+	s.metrics = newServerMetrics(s.TaskMgr)
+	s.configMtime = s.configFileMtimes()
+	logConfigWarnings(cfg)
+
+	go s.watchConfig()
+	return s, nil
+}
+
+// Config returns the current configuration. The returned value is never
+// mutated after load, so callers may use it without further locking.
+func (s *Server) Config() *config.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// ReloadConfig re-reads the configuration and swaps it in, returning the new repo count.
+// On error the current configuration is kept.
+func (s *Server) ReloadConfig() (int, error) {
+	mtime := s.configFileMtimes()
+	cfg, err := config.Load(s.RootPath)
+	if err != nil {
+		return 0, fmt.Errorf("loading config: %w", err)
+	}
+
+	s.mu.Lock()
+	oldCount := len(s.cfg.AllRepos())
+	s.cfg = cfg
+	s.configMtime = mtime
+	s.mu.Unlock()
+
+	newCount := len(cfg.AllRepos())
+	logf("INFO", "config reloaded: %d -> %d repos", oldCount, newCount)
+	logConfigWarnings(cfg)
+	return newCount, nil
+}
+
+// watchConfig reloads the configuration whenever repos.json or
+// repos.local.json changes on disk.
+func (s *Server) watchConfig() {
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			mtime := s.configFileMtimes()
+			s.mu.RLock()
+			changed := !mtime.repos.IsZero() && !mtime.equal(s.configMtime)
+			s.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			if _, err := s.ReloadConfig(); err != nil {
+				logf("WARN", "config reload failed: %v", err)
+			}
+		}
+	}
+}
+
+// configMtimes holds the modification times of the files config.Load reads.
+// A zero time means the file is missing, so creating or deleting
+// repos.local.json also counts as a change.
+type configMtimes struct {
+	repos time.Time
+	local time.Time
+}
+
+func (m configMtimes) equal(o configMtimes) bool {
+	return m.repos.Equal(o.repos) && m.local.Equal(o.local)
+}
+
+func (s *Server) configFileMtimes() configMtimes {
+	return configMtimes{
+		repos: fileMtime(config.ReposPath(s.RootPath)),
+		local: fileMtime(config.LocalReposPath(s.RootPath)),
+	}
+}
+
+func fileMtime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
 }
 
 // Shutdown performs any cleanup needed when the server stops.
 func (s *Server) Shutdown() {
-	// Nothing to clean up currently; placeholder for future use.
+	close(s.stop)
+}
+
+func logConfigWarnings(cfg *config.Config) {
+	for _, w := range cfg.Warnings {
+		logf("WARN", "repos.json: %v", w)
+	}
+}
+
+// DebugEnv enables DEBUG log lines when set to "1".
+const DebugEnv = "MCP_DEBUG"
+
+// logf writes a leveled log line to stderr; stdout is reserved for responses.
+// DEBUG lines are dropped unless DebugEnv is set.
+func logf(level, format string, args ...interface{}) {
+	if level == "DEBUG" && os.Getenv(DebugEnv) != "1" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s %s\n", level, fmt.Sprintf(format, args...))
 }
@@ -3,8 +3,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/PaulSnow/orchestrator/internal/config"
 	"github.com/PaulSnow/orchestrator/internal/repos"
 	"github.com/PaulSnow/orchestrator/internal/runner"
 	"github.com/PaulSnow/orchestrator/internal/tasks"
@@ -12,10 +17,12 @@ import (
 
 // ToolScanRepos scans all configured repositories and returns their git statuses.
 func ToolScanRepos(s *Server) (string, error) {
-	statuses := repos.ScanAll(s.Config)
+	statuses := repos.ScanAll(s.Config())
+	s.metrics.SetRepoStatuses(statuses)
 
-	// Also persist the status file for other consumers.
+	// Also persist the status and health files for other consumers.
 	_ = repos.WriteStatusFile(s.RootPath, statuses)
+	_ = repos.WriteHealthFile(s.RootPath, statuses)
 
 	data, err := json.MarshalIndent(statuses, "", "  ")
 	if err != nil {
@@ -24,9 +31,120 @@ func ToolScanRepos(s *Server) (string, error) {
 	return string(data), nil
 }
 
+// ToolDiffRepos scans all repositories and returns the changes relative to the
+// previously persisted status file. Without a previous scan there is nothing
+// to compare against, so the changes are empty and no_previous_scan is set.
+func ToolDiffRepos(s *Server) (string, error) {
+	previous, err := repos.LoadStatusFile(s.RootPath)
+	noPrevious := os.IsNotExist(err)
+	if err != nil && !noPrevious {
+		return "", fmt.Errorf("reading previous scan: %w", err)
+	}
+	statuses := repos.ScanAll(s.Config())
+	s.metrics.SetRepoStatuses(statuses)
+	if err := repos.WriteStatusFile(s.RootPath, statuses); err != nil {
+		return "", fmt.Errorf("writing status file: %w", err)
+	}
+	if err := repos.WriteHealthFile(s.RootPath, statuses); err != nil {
+		return "", fmt.Errorf("writing health file: %w", err)
+	}
+
+	changes := make([]repos.StatusChange, 0)
+	if !noPrevious {
+		changes = append(changes, repos.DiffStatus(previous, statuses)...)
+	}
+	response := map[string]interface{}{"changes": changes}
+	if noPrevious {
+		response["no_previous_scan"] = true
+	}
+
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling diff: %w", err)
+	}
+	return string(data), nil
+}
+
+// staleScanAge is how old the snapshot search-repos reads may be before the
+// response is marked stale.
+const staleScanAge = 5 * time.Minute
+
+// repoSearch holds the search-repos filters. Empty strings and nil pointers
+// match every repository.
+type repoSearch struct {
+	Language string
+	Tag      string
+	Dirty    *bool
+	Missing  *bool
+}
+
+// matches reports whether a scanned repository passes the filters. repo is
+// its current configuration, if it is still configured.
+func (f repoSearch) matches(status repos.RepoStatus, repo config.RepoConfig) bool {
+	switch {
+	case f.Language != "" && repo.Language != f.Language:
+		return false
+	case f.Tag != "" && !repo.HasTag(f.Tag):
+		return false
+	case f.Dirty != nil && *f.Dirty != (status.Exists && !status.Clean):
+		return false
+	case f.Missing != nil && *f.Missing != !status.Exists:
+		return false
+	}
+	return true
+}
+
+// ToolSearchRepos filters the snapshot in state/repo-status.json, joined with
+// each repository's configured language and tags, without scanning. The
+// response is marked stale when the oldest entry was scanned more than
+// staleScanAge ago.
+func ToolSearchRepos(s *Server, f repoSearch) (string, error) {
+	statuses, err := repos.LoadStatusFile(s.RootPath)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no scan results in state/repo-status.json (run scan-repos first)")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	type match struct {
+		repos.RepoStatus
+		Language string   `json:"language,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+	}
+	cfg := s.Config()
+	matches := make([]match, 0)
+	var oldest time.Time
+	for _, st := range statuses {
+		if oldest.IsZero() || st.ScannedAt.Before(oldest) {
+			oldest = st.ScannedAt
+		}
+		repo, _ := cfg.GetRepo(st.Name)
+		if f.matches(st, repo) {
+			matches = append(matches, match{RepoStatus: st, Language: repo.Language, Tags: repo.Tags})
+		}
+	}
+
+	response := map[string]interface{}{
+		"repos": matches,
+		"count": len(matches),
+	}
+	if !oldest.IsZero() {
+		response["scanned_at"] = oldest
+		if time.Since(oldest) > staleScanAge {
+			response["stale"] = true
+		}
+	}
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling search results: %w", err)
+	}
+	return string(data), nil
+}
+
 // ToolRepoStatus returns the git status of a single named repository.
 func ToolRepoStatus(s *Server, repoName string) (string, error) {
-	repo, ok := s.Config.GetRepo(repoName)
+	repo, ok := s.Config().GetRepo(repoName)
 	if !ok {
 		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
 	}
@@ -39,14 +157,28 @@ func ToolRepoStatus(s *Server, repoName string) (string, error) {
 	return string(data), nil
 }
 
-// ToolRunTests runs tests for a named repository and returns the result.
-func ToolRunTests(s *Server, repoName string) (string, error) {
-	repo, ok := s.Config.GetRepo(repoName)
+// ToolRunTests runs tests for a named repository and returns the result,
+// also writing it as a JUnit XML report to junitOutput, a path relative to
+// the state directory, when set.
+func ToolRunTests(s *Server, repoName, junitOutput string) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
 	if !ok {
 		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
 	}
+	var junitPath string
+	if junitOutput != "" {
+		var err error
+		if junitPath, err = stateFilePath(s.RootPath, junitOutput); err != nil {
+			return "", fmt.Errorf("invalid junit_output: %w", err)
+		}
+	}
 
 	result := runner.TestRepo(repo)
+	if junitPath != "" {
+		if err := runner.WriteJUnitXMLFile(junitPath, []runner.Result{result}); err != nil {
+			return "", fmt.Errorf("writing JUnit XML: %w", err)
+		}
+	}
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("marshaling test result: %w", err)
@@ -54,9 +186,95 @@ func ToolRunTests(s *Server, repoName string) (string, error) {
 	return string(data), nil
 }
 
+// stateFilePath resolves name inside the state directory under rootPath. name
+// must be relative and must not contain "..", so clients cannot write outside
+// state/.
+func stateFilePath(rootPath, name string) (string, error) {
+	if filepath.IsAbs(name) || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%q must be a relative path inside state/", name)
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(name), "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("%q must not contain ..", name)
+		}
+	}
+	return filepath.Join(rootPath, "state", name), nil
+}
+
+// ToolRunRaceTests runs Go tests with the race detector for a named repository
+// and returns the result.
+func ToolRunRaceTests(s *Server, repoName string) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	result := runner.TestRepoRace(repo)
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling test result: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolRunTestsVerbose runs Go tests with -json and returns the result, the full
+// event list, and the aggregated output of failed tests.
+func ToolRunTestsVerbose(s *Server, repoName string) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	result, events, err := runner.TestRepoJSON(repo)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"result":       result,
+		"events":       events,
+		"failed_tests": runner.FailedTests(events),
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling test result: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolRunCommand runs command with args in a named repository's directory and
+// returns the result.
+func ToolRunCommand(s *Server, repoName, command string, args []string) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	result := runner.RunInRepo(repo, command, args, "run")
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling run result: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolSyncRepo fetches and fast-forwards a named repository and returns the
+// result.
+func ToolSyncRepo(s *Server, repoName string) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	result := runner.SyncRepo(repo)
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling sync result: %w", err)
+	}
+	return string(data), nil
+}
+
 // ToolBuildRepo builds a named repository and returns the result.
 func ToolBuildRepo(s *Server, repoName string) (string, error) {
-	repo, ok := s.Config.GetRepo(repoName)
+	repo, ok := s.Config().GetRepo(repoName)
 	if !ok {
 		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
 	}
@@ -69,15 +287,219 @@ func ToolBuildRepo(s *Server, repoName string) (string, error) {
 	return string(data), nil
 }
 
-// ToolListTasks returns all backlog and active tasks as JSON.
-func ToolListTasks(s *Server) (string, error) {
-	backlog, backlogErr := s.TaskMgr.ListBacklog()
-	active, activeErr := s.TaskMgr.ListActive()
+// ToolLintRepo builds and lints a named repository and returns the result.
+func ToolLintRepo(s *Server, repoName string) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	result := runner.BuildAndLintRepo(repo)
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling lint result: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolBenchmarkRepo runs benchmarks for a repository and compares them to the
+// stored baseline. The baseline is written on the first run or when save is set.
+func ToolBenchmarkRepo(s *Server, repoName, bench string, save bool) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	result := runner.BenchmarkRepo(repo, bench)
+	response := map[string]interface{}{"result": result}
+
+	if result.Success {
+		benchmarks, err := runner.ParseBenchOutput(result.LogFile)
+		if err != nil {
+			return "", fmt.Errorf("parsing benchmark output: %w", err)
+		}
+		response["benchmarks"] = benchmarks
+
+		deltas, err := runner.CompareBenchToBaseline(s.RootPath, repo.Name, benchmarks)
+		switch {
+		case os.IsNotExist(err):
+			save = true
+		case err != nil:
+			return "", fmt.Errorf("comparing to baseline: %w", err)
+		default:
+			response["comparison"] = deltas
+		}
+
+		if save {
+			if err := runner.WriteBenchBaseline(s.RootPath, repo.Name, benchmarks); err != nil {
+				return "", fmt.Errorf("writing baseline: %w", err)
+			}
+			response["baseline_saved"] = true
+		}
+	}
+
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling benchmark result: %w", err)
+	}
+	return string(data), nil
+}
+
+// logTypeRe matches the log types runners write, such as build or sync-pull,
+// so a type cannot add path separators to the log file name.
+var logTypeRe = regexp.MustCompile(`^[a-z][a-z-]*$`)
+
+// ToolGetLog returns the last lines of a runner log file for a repository,
+// along with the log's header and exit footer when it has them. The footer is
+// not counted in, or included with, the returned lines.
+func ToolGetLog(s *Server, repoName, logType string, lines int) (string, error) {
+	if _, ok := s.Config().GetRepo(repoName); !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+	if !logTypeRe.MatchString(logType) {
+		return "", fmt.Errorf("invalid log type %q (use e.g. build, test, sync-pull)", logType)
+	}
+
+	path := runner.LogPath(logType, repoName)
+	footer, footerErr := runner.ReadLogFooter(path)
+	n := lines
+	if footerErr == nil && n > 0 {
+		n++
+	}
+	tail, err := runner.TailLines(path, n)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no %s log for %s at %s (has the command been run?)", logType, repoName, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	response := map[string]interface{}{
+		"path": path,
+	}
+	if header, err := runner.ParseLogHeader(path); err == nil {
+		response["header"] = header
+	}
+	if footerErr == nil {
+		response["footer"] = footer
+		tail = tail[:len(tail)-1]
+	}
+	response["lines"] = tail
+
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling log: %w", err)
+	}
+	return string(data), nil
+}
+
+// maxGitLogLimit caps the number of commits returned by git-log.
+const maxGitLogLimit = 100
+
+// ToolGitLog returns the most recent commits in a repository as a JSON array.
+// A repository that has not been cloned yields an error wrapping
+// repos.ErrRepoMissing.
+func ToolGitLog(s *Server, repoName, branch string, limit int) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	commits, err := repos.GitLog(repo, branch, limit)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", repoName, err)
+	}
+
+	data, err := json.MarshalIndent(commits, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling commits: %w", err)
+	}
+	return string(data), nil
+}
+
+// maxGitDiffBytes caps the diff returned by git-diff.
+const maxGitDiffBytes = 100 * 1024
+
+// ToolGitDiff returns the uncommitted changes in a named repository as a
+// unified diff, staged or unstaged.
+func ToolGitDiff(s *Server, repoName string, staged bool, contextLines int) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
+
+	diff, truncated, err := repos.GitDiff(repo, staged, contextLines, maxGitDiffBytes)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", repoName, err)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"diff":      diff,
+		"truncated": truncated,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling diff: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolCreateBranch creates and checks out branch from the from ref in a named
+// repository. An existing branch yields an error wrapping repos.ErrBranchExists.
+func ToolCreateBranch(s *Server, repoName, branch, from string) (string, error) {
+	repo, ok := s.Config().GetRepo(repoName)
+	if !ok {
+		return "", fmt.Errorf("unknown repo: %s (available: %s)", repoName, allRepoNames(s))
+	}
 
+	sha, err := repos.CreateBranch(repo, branch, from)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", repoName, err)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"created":  true,
+		"branch":   branch,
+		"from_sha": sha,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling branch: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolListLogs returns all orchestrator log files in the log directory.
+func ToolListLogs(s *Server) (string, error) {
+	logs, err := runner.FindLogs(nil, "", "")
+	if err != nil {
+		return "", fmt.Errorf("listing logs: %w", err)
+	}
+
+	type logEntry struct {
+		Path    string    `json:"path"`
+		Size    int64     `json:"size"`
+		ModTime time.Time `json:"mod_time"`
+	}
+	result := make([]logEntry, 0, len(logs))
+	for _, l := range logs {
+		result = append(result, logEntry{Path: l.Path, Size: l.Size, ModTime: l.ModTime})
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling logs: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolListTasks returns all backlog and active tasks as JSON, plus completed
+// tasks when includeCompleted is set. A non-empty assignedTo keeps only the
+// tasks assigned to that name.
+func ToolListTasks(s *Server, includeCompleted bool, assignedTo string) (string, error) {
 	type taskList struct {
-		Active  []taskSummary `json:"active"`
-		Backlog []taskSummary `json:"backlog"`
-		Errors  []string      `json:"errors,omitempty"`
+		Active    []taskSummary `json:"active"`
+		Backlog   []taskSummary `json:"backlog"`
+		Completed []taskSummary `json:"completed,omitempty"`
+		Errors    []string      `json:"errors,omitempty"`
 	}
 
 	result := taskList{
@@ -85,18 +507,100 @@ func ToolListTasks(s *Server) (string, error) {
 		Backlog: make([]taskSummary, 0),
 	}
 
-	for _, t := range active {
-		result.Active = append(result.Active, summarizeTask(t))
+	// keep applies the optional assigned_to filter.
+	keep := func(t tasks.Task) bool {
+		return assignedTo == "" || t.IsAssignedTo(assignedTo)
+	}
+	if assignedTo != "" && !includeCompleted {
+		mine, err := s.TaskMgr.TasksByAssignee(assignedTo)
+		for _, t := range mine {
+			if t.State == "active" {
+				result.Active = append(result.Active, summarizeTask(t))
+			} else {
+				result.Backlog = append(result.Backlog, summarizeTask(t))
+			}
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, "tasks: "+err.Error())
+		}
+	} else {
+		backlog, backlogErr := s.TaskMgr.ListBacklog()
+		active, activeErr := s.TaskMgr.ListActive()
+		for _, t := range active {
+			if keep(t) {
+				result.Active = append(result.Active, summarizeTask(t))
+			}
+		}
+		for _, t := range backlog {
+			if keep(t) {
+				result.Backlog = append(result.Backlog, summarizeTask(t))
+			}
+		}
+
+		if backlogErr != nil {
+			result.Errors = append(result.Errors, "backlog: "+backlogErr.Error())
+		}
+		if activeErr != nil {
+			result.Errors = append(result.Errors, "active: "+activeErr.Error())
+		}
 	}
-	for _, t := range backlog {
-		result.Backlog = append(result.Backlog, summarizeTask(t))
+
+	if includeCompleted {
+		completed, err := s.TaskMgr.ListCompleted()
+		result.Completed = make([]taskSummary, 0, len(completed))
+		for _, t := range completed {
+			if keep(t) {
+				result.Completed = append(result.Completed, summarizeTask(t))
+			}
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, "completed: "+err.Error())
+		}
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling tasks: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolListOverdueTasks returns only the backlog and active tasks that are past due.
+func ToolListOverdueTasks(s *Server) (string, error) {
+	overdue, err := s.TaskMgr.OverdueTasks()
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]taskSummary, 0, len(overdue))
+	for _, t := range overdue {
+		result = append(result, summarizeTask(t))
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling tasks: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolCheckStuckTasks returns the active tasks started more than maxDays days
+// ago.
+func ToolCheckStuckTasks(s *Server, maxDays int) (string, error) {
+	stuck, err := s.TaskMgr.StuckTasks(maxDays)
+	if err != nil {
+		return "", err
 	}
 
-	if backlogErr != nil {
-		result.Errors = append(result.Errors, "backlog: "+backlogErr.Error())
+	type stuckTask struct {
+		taskSummary
+		StartedAt  time.Time `json:"started_at"`
+		DaysActive int       `json:"days_active"`
 	}
-	if activeErr != nil {
-		result.Errors = append(result.Errors, "active: "+activeErr.Error())
+	now := time.Now()
+	result := make([]stuckTask, 0, len(stuck))
+	for _, t := range stuck {
+		result = append(result, stuckTask{summarizeTask(t), t.StartedAt, t.DaysActive(now)})
 	}
 
 	data, err := json.MarshalIndent(result, "", "  ")
@@ -106,6 +610,44 @@ func ToolListTasks(s *Server) (string, error) {
 	return string(data), nil
 }
 
+// ToolCreateTask adds a task to the backlog with a generated ID.
+func ToolCreateTask(s *Server, p createTaskParams) (string, error) {
+	id, err := s.TaskMgr.AddToBacklog(tasks.Task{
+		Title:       strings.TrimSpace(p.Title),
+		Repo:        p.Repo,
+		Type:        p.Type,
+		Priority:    p.Priority,
+		Description: p.Description,
+		Links:       p.Links,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"id": id, "created": true})
+	if err != nil {
+		return "", fmt.Errorf("marshaling result: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToolUpdateTask applies field updates to a task and returns the updated task.
+func ToolUpdateTask(s *Server, taskID string, updates map[string]string) (string, error) {
+	if err := s.TaskMgr.UpdateTask(taskID, updates); err != nil {
+		return "", err
+	}
+	t, err := s.TaskMgr.GetTask(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling task: %w", err)
+	}
+	return string(data), nil
+}
+
 // ToolStartTask moves a task from backlog to active.
 func ToolStartTask(s *Server, taskID string) (string, error) {
 	if err := s.TaskMgr.StartTask(taskID); err != nil {
@@ -114,6 +656,22 @@ func ToolStartTask(s *Server, taskID string) (string, error) {
 	return fmt.Sprintf("Task %s moved to active.", taskID), nil
 }
 
+// ToolAssignTask sets a task's assignee.
+func ToolAssignTask(s *Server, taskID, assignee string) (string, error) {
+	if err := s.TaskMgr.AssignTask(taskID, assignee); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Task %s assigned to %s.", taskID, assignee), nil
+}
+
+// ToolUnassignTask clears a task's assignee.
+func ToolUnassignTask(s *Server, taskID string) (string, error) {
+	if err := s.TaskMgr.UnassignTask(taskID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Task %s unassigned.", taskID), nil
+}
+
 // ToolCompleteTask moves a task from active to completed.
 func ToolCompleteTask(s *Server, taskID string) (string, error) {
 	if err := s.TaskMgr.CompleteTask(taskID); err != nil {
@@ -131,6 +689,9 @@ type taskSummary struct {
 	Priority    string `json:"priority,omitempty"`
 	Assigned    string `json:"assigned,omitempty"`
 	Description string `json:"description,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+	Completed   string `json:"completed,omitempty"`
+	Overdue     bool   `json:"overdue"`
 }
 
 func summarizeTask(t tasks.Task) taskSummary {
@@ -142,12 +703,15 @@ func summarizeTask(t tasks.Task) taskSummary {
 		Priority:    t.Priority,
 		Assigned:    t.Assigned,
 		Description: t.Description,
+		DueDate:     t.DueDate,
+		Completed:   t.Completed,
+		Overdue:     t.Completed == "" && t.IsOverdue(time.Now()),
 	}
 }
 
 func allRepoNames(s *Server) string {
 	var names []string
-	for _, r := range s.Config.AllRepos() {
+	for _, r := range s.Config().AllRepos() {
 		names = append(names, r.Name)
 	}
 	return strings.Join(names, ", ")
@@ -0,0 +1,70 @@
+package main
+
+import "github.com/PaulSnow/orchestrator/internal/version"
+
+// initialToolVersion summarizes the first entry in every tool's history.
+const initialToolVersion = "Initial versioned release."
+
+// responseMeta is the envelope attached to every tool response so clients
+// that cache the tool list can detect a changed tool or server.
+type responseMeta struct {
+	ToolVersion   string `json:"tool_version,omitempty"`
+	ServerVersion string `json:"server_version"`
+}
+
+// toolChange is one entry in a tool's version history.
+type toolChange struct {
+	Version string `json:"version"`
+	Summary string `json:"summary"`
+}
+
+// toolChangelog is the version history of each tool, oldest first; the last
+// entry is the current version. Tools are versioned independently: when a
+// tool's params or result change, append an entry with a bumped version,
+// using a new major version for changes that break existing callers.
+var toolChangelog = map[string][]toolChange{
+	"scan-repos":         {{"1.0.0", initialToolVersion}},
+	"diff-repos":         {{"1.0.0", initialToolVersion}, {"2.0.0", "Returns an object: changes, plus no_previous_scan on the first scan. Load and write errors are returned."}},
+	"search-repos":       {{"1.0.0", initialToolVersion}},
+	"repo-status":        {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds go_module and go_version for Go repositories."}},
+	"run-tests":          {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds tests_passed and tests_failed for Swift repositories."}, {"1.2.0", "Adds excluded_packages from .orchestrator-ignore."}, {"1.3.0", "Accepts junit_output to also write a JUnit XML report."}, {"1.4.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.5.0", "Adds error when the command could not be run."}, {"1.6.0", "junit_output must be a relative path and is written under state/."}},
+	"run-tests-verbose":  {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.2.0", "Adds error when the command could not be run."}},
+	"run-race-tests":     {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.2.0", "Adds error when the command could not be run."}},
+	"build-repo":         {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds excluded_packages from .orchestrator-ignore."}, {"1.2.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.3.0", "Adds error when the command could not be run."}},
+	"sync-repo":          {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.2.0", "Adds error when the command could not be run."}},
+	"run-command":        {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.2.0", "Adds error when the command could not be run."}, {"1.3.0", "Refused over WebSocket; only stdin clients may run commands."}},
+	"lint-repo":          {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.2.0", "Adds error when the command could not be run."}},
+	"benchmark-repo":     {{"1.0.0", initialToolVersion}, {"1.1.0", "Adds log_checksum, the SHA-256 of the log file."}, {"1.2.0", "Adds error when the command could not be run."}},
+	"get-log":            {{"1.0.0", initialToolVersion}},
+	"list-logs":          {{"1.0.0", initialToolVersion}},
+	"git-log":            {{"1.0.0", initialToolVersion}},
+	"git-diff":           {{"1.0.0", initialToolVersion}},
+	"create-branch":      {{"1.0.0", initialToolVersion}},
+	"get-request-log":    {{"1.0.0", initialToolVersion}},
+	"reload-config":      {{"1.0.0", initialToolVersion}},
+	"list-tasks":         {{"1.0.0", initialToolVersion}, {"1.1.0", "Accepts assigned_to to filter by assignee."}},
+	"list-overdue-tasks": {{"1.0.0", initialToolVersion}},
+	"check-stuck-tasks":  {{"1.0.0", initialToolVersion}},
+	"create-task":        {{"1.0.0", initialToolVersion}},
+	"update-task":        {{"1.0.0", initialToolVersion}, {"1.1.0", "Accepts a multi-line description."}},
+	"start-task":         {{"1.0.0", initialToolVersion}},
+	"assign-task":        {{"1.0.0", initialToolVersion}},
+	"unassign-task":      {{"1.0.0", initialToolVersion}},
+	"complete-task":      {{"1.0.0", initialToolVersion}},
+	"tool-changelog":     {{"1.0.0", initialToolVersion}},
+}
+
+// toolVersion returns the current version of a tool, or "" if it has no
+// changelog.
+func toolVersion(name string) string {
+	history := toolChangelog[name]
+	if len(history) == 0 {
+		return ""
+	}
+	return history[len(history)-1].Version
+}
+
+// metaFor returns the response envelope for a request to method.
+func metaFor(method string) *responseMeta {
+	return &responseMeta{ToolVersion: toolVersion(method), ServerVersion: version.Version}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsCloseTimeout bounds how long Close waits for each client to receive the
+// close frame.
+const wsCloseTimeout = 2 * time.Second
+
+// wsTokenEnv holds the shared token WebSocket clients must present when
+// --ws-token is not given.
+const wsTokenEnv = "ORCHESTRATOR_WS_TOKEN"
+
+// wsServer serves requests over WebSocket. Each text message is one request
+// and gets one response message; every connection runs its own loop against
+// the shared Server. A client must send "Authorization: Bearer <token>" with
+// the upgrade request, and browsers are only accepted from loopback origins.
+type wsServer struct {
+	srv      *Server
+	http     *http.Server
+	upgrader websocket.Upgrader
+	token    string
+
+	mu    sync.Mutex
+	conns map[*websocket.Conn]bool
+	wg    sync.WaitGroup
+}
+
+// startWebSocket starts accepting WebSocket connections on addr in the
+// background, for clients presenting token. Listen errors are logged.
+func startWebSocket(srv *Server, addr, token string) *wsServer {
+	ws := &wsServer{
+		srv:      srv,
+		upgrader: websocket.Upgrader{CheckOrigin: loopbackOrigin},
+		token:    token,
+		conns:    make(map[*websocket.Conn]bool),
+	}
+	ws.http = &http.Server{Addr: wsListenAddr(addr), Handler: ws}
+	go func() {
+		if err := ws.http.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logf("ERROR", "websocket server: %v", err)
+		}
+	}()
+	return ws
+}
+
+// ServeHTTP upgrades the connection and answers its requests until the
+// client disconnects or the server closes.
+func (ws *wsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !ws.authorized(r) {
+		logf("WARN", "websocket client %s: missing or wrong token", r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	conn, err := ws.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already replied with an HTTP error
+	}
+	if !ws.track(conn) {
+		conn.Close()
+		return
+	}
+	defer ws.untrack(conn)
+
+	logf("INFO", "websocket client connected: %s", r.RemoteAddr)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logf("WARN", "websocket client %s: %v", r.RemoteAddr, err)
+			}
+			return
+		}
+		resp, ok := handleRequest(ws.srv, data, true)
+		if !ok {
+			continue
+		}
+		out, err := json.Marshal(resp)
+		if err != nil {
+			out = []byte(`{"error":{"code":-32603,"message":"internal marshal error"}}`)
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, out); err != nil {
+			return
+		}
+	}
+}
+
+// authorized reports whether the request carries the shared token as a
+// bearer token.
+func (ws *wsServer) authorized(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(ws.token)) == 1
+}
+
+// loopbackOrigin accepts requests without an Origin header, as non-browser
+// clients send them, and browser requests from pages served on a loopback
+// host.
+func loopbackOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if u.Hostname() == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
+// wsListenAddr binds addr to 127.0.0.1 when it names only a port, such as
+// ":8765" or "8765". Listening on other interfaces takes an explicit host.
+func wsListenAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return net.JoinHostPort("127.0.0.1", addr)
+	}
+	if host == "" {
+		return net.JoinHostPort("127.0.0.1", port)
+	}
+	return addr
+}
+
+// track registers a connection, or reports false once the server is closing.
+func (ws *wsServer) track(conn *websocket.Conn) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.conns == nil {
+		return false
+	}
+	ws.conns[conn] = true
+	ws.wg.Add(1)
+	return true
+}
+
+func (ws *wsServer) untrack(conn *websocket.Conn) {
+	ws.mu.Lock()
+	delete(ws.conns, conn)
+	ws.mu.Unlock()
+	conn.Close()
+	ws.wg.Done()
+}
+
+// Close stops accepting connections, sends each client a going-away close
+// frame, and waits for their loops to finish. A request already being
+// handled runs to completion, but its response is dropped.
+func (ws *wsServer) Close() {
+	ws.http.Shutdown(context.Background())
+
+	ws.mu.Lock()
+	conns := ws.conns
+	ws.conns = nil
+	ws.mu.Unlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for conn := range conns {
+		conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsCloseTimeout))
+		conn.Close()
+	}
+	ws.wg.Wait()
+}
@@ -2,10 +2,11 @@
 // repositories, writing output to /tmp/orchestrator-test-*.log files.
 // Equivalent to running: orchestrator test-all
 //
-// Usage: go run ./scripts/run-all-tests/
+// Usage: go run ./scripts/run-all-tests/ [--append]
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -16,6 +17,9 @@ import (
 const orchestratorRoot = "/home/paul/go/src/github.com/PaulSnow/orchestrator"
 
 func main() {
+	appendResults := flag.Bool("append", false, "Merge results into the existing state/test-results.json instead of replacing it")
+	flag.Parse()
+
 	cfg, err := config.Load(orchestratorRoot)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
@@ -51,12 +55,23 @@ func main() {
 	}
 
 	// Write results to state directory
-	if err := runner.WriteResults(orchestratorRoot, "test-results.json", results); err != nil {
+	write := runner.WriteResults
+	if *appendResults {
+		write = runner.AppendResults
+	}
+	if err := write(orchestratorRoot, "test-results.json", results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+	} else if *appendResults {
+		if merged, err := runner.ReadResults(orchestratorRoot, "test-results.json"); err == nil {
+			results = merged
+		}
+	}
+	if err := runner.WriteResultsText(orchestratorRoot, "test-results.txt", results); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
 	}
 
 	fmt.Printf("\nResults: %d passed, %d failed, %d skipped (total: %d)\n",
 		passed, failed, skipped, len(allRepos))
-	fmt.Println("Results written to state/test-results.json")
+	fmt.Println("Results written to state/test-results.json and state/test-results.txt")
 	fmt.Println("Check individual logs: tail -50 /tmp/orchestrator-test-<repo>.log")
 }
@@ -1,5 +1,6 @@
 // scan-all-repos is a standalone convenience script that scans all configured
-// repositories and writes results to state/repo-status.json.
+// repositories and writes results to state/repo-status.json,
+// state/repo-status.txt, and state/health.json.
 // Equivalent to running: orchestrator scan
 //
 // Usage: go run ./scripts/scan-all-repos/
@@ -30,6 +31,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error writing status file: %v\n", err)
 		os.Exit(1)
 	}
+	if err := repos.WriteHealthFile(orchestratorRoot, statuses); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing health file: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Print summary
 	clean, dirty, missing := 0, 0, 0
@@ -50,5 +55,5 @@ func main() {
 
 	fmt.Printf("\nSummary: %d clean, %d dirty, %d missing (total: %d)\n",
 		clean, dirty, missing, len(statuses))
-	fmt.Println("State written to state/repo-status.json")
+	fmt.Println("State written to state/repo-status.json, state/repo-status.txt, and state/health.json")
 }
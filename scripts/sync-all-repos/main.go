@@ -2,20 +2,29 @@
 // all configured repositories. Output for each repo is written to
 // /tmp/orchestrator-sync-*.log files.
 //
-// Usage: go run ./scripts/sync-all-repos/
+// Usage: go run ./scripts/sync-all-repos/ [--repos a,b] [--tag <tag>] [--include-archived] [--dry-run]
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/PaulSnow/orchestrator/internal/config"
+	"github.com/PaulSnow/orchestrator/internal/repos"
 	"github.com/PaulSnow/orchestrator/internal/runner"
 )
 
 const orchestratorRoot = "/home/paul/go/src/github.com/PaulSnow/orchestrator"
 
 func main() {
+	tag := flag.String("tag", "", "Only sync repositories with this tag")
+	repoList := flag.String("repos", "", "Comma-separated repository names to sync")
+	includeArchived := flag.Bool("include-archived", false, "Also sync archived repositories")
+	dryRun := flag.Bool("dry-run", false, "Print what would be fetched and pulled without running git")
+	flag.Parse()
+
 	cfg, err := config.Load(orchestratorRoot)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
@@ -23,40 +32,79 @@ func main() {
 	}
 
 	allRepos := cfg.AllRepos()
+	if *includeArchived {
+		allRepos = cfg.AllReposIncludingArchived()
+	}
+	if *repoList != "" {
+		allRepos = nil
+		for _, name := range strings.Split(*repoList, ",") {
+			repo, ok := cfg.GetRepo(strings.TrimSpace(name))
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: unknown repo %s\n", name)
+				os.Exit(1)
+			}
+			allRepos = append(allRepos, repo)
+		}
+	}
+	if *tag != "" {
+		var tagged []config.RepoConfig
+		for _, r := range allRepos {
+			if r.HasTag(*tag) {
+				tagged = append(tagged, r)
+			}
+		}
+		allRepos = tagged
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: would sync %d repositories (git fetch origin && git pull --ff-only)\n\n", len(allRepos))
+		for _, repo := range allRepos {
+			result := runner.SyncRepoDryRun(repo)
+			if result.FailureKind == runner.FailureMissing {
+				fmt.Printf("  [MISSING] %s: %s does not exist\n", repo.Name, repo.Local)
+			} else {
+				fmt.Printf("  [SYNC]    %s (%s)\n", repo.Name, repo.Local)
+			}
+		}
+		return
+	}
+
 	fmt.Printf("Syncing %d repositories (git fetch && git pull --ff-only)...\n", len(allRepos))
 	fmt.Println("All output redirected to /tmp/orchestrator-sync-*.log files.")
 	fmt.Println()
 
-	passed, failed, missing := 0, 0, 0
+	passed := 0
+	failures := make(map[runner.FailureKind][]string)
 
 	for _, repo := range allRepos {
 		fmt.Printf("  Syncing %s... ", repo.Name)
 
-		// Step 1: git fetch origin
-		fetchResult := runner.RunInRepo(repo, "git", []string{"fetch", "origin"}, "sync-fetch")
-		if !fetchResult.Success {
-			if fetchResult.ExitCode == 1 && fetchResult.LogFile != "" {
-				fmt.Printf("[MISSING/FAIL] fetch failed -> %s\n", fetchResult.LogFile)
-			} else {
-				fmt.Printf("[FAIL] fetch failed (exit %d) -> %s\n", fetchResult.ExitCode, fetchResult.LogFile)
+		sync := runner.SyncRepo(repo)
+		if !sync.Synced {
+			result := sync.Last()
+			switch sync.FailureKind {
+			case runner.FailureMissing:
+				fmt.Printf("[MISSING] %s does not exist\n", repo.Local)
+			case runner.FailureFFOnlyConflict:
+				status := repos.ScanRepo(repo)
+				fmt.Printf("[DIVERGED] %d ahead, %d behind origin; rebase or merge needed -> %s\n",
+					status.Ahead, status.Behind, result.LogFile)
+			default:
+				fmt.Printf("[FAIL] %s failed (exit %d, %s) -> %s\n", result.Command, result.ExitCode, result.FailureKind, result.LogFile)
 			}
-			failed++
-			continue
-		}
-
-		// Step 2: git pull --ff-only
-		pullResult := runner.RunInRepo(repo, "git", []string{"pull", "--ff-only"}, "sync-pull")
-		if !pullResult.Success {
-			fmt.Printf("[FAIL] pull failed (exit %d) -> %s\n", pullResult.ExitCode, pullResult.LogFile)
-			failed++
+			failures[sync.FailureKind] = append(failures[sync.FailureKind], repo.Name)
 			continue
 		}
 
-		totalDuration := fetchResult.Duration + pullResult.Duration
-		fmt.Printf("[OK] (%.1fs) -> %s\n", totalDuration, pullResult.LogFile)
+		fmt.Printf("[OK] (%.1fs) -> %s\n", sync.Duration(), sync.PullResult.LogFile)
 		passed++
 	}
 
+	failed := 0
+	for _, names := range failures {
+		failed += len(names)
+	}
+
 	// Write sync results
 	var results []runner.Result
 	results = append(results, runner.Result{
@@ -67,8 +115,20 @@ func main() {
 	if err := runner.WriteResults(orchestratorRoot, "sync-results.json", results); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
 	}
+	if err := runner.WriteResultsText(orchestratorRoot, "sync-results.txt", results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+	}
 
-	fmt.Printf("\nResults: %d synced, %d failed, %d missing (total: %d)\n",
-		passed, failed, missing, len(allRepos))
+	fmt.Printf("\nResults: %d synced, %d failed (total: %d)\n", passed, failed, len(allRepos))
+	for _, kind := range []runner.FailureKind{
+		runner.FailureFFOnlyConflict,
+		runner.FailureNetwork,
+		runner.FailureMissing,
+		runner.FailureOther,
+	} {
+		if names := failures[kind]; len(names) > 0 {
+			fmt.Printf("  %-16s %d: %s\n", kind, len(names), strings.Join(names, ", "))
+		}
+	}
 	fmt.Println("Check individual logs: tail -50 /tmp/orchestrator-sync-*-<repo>.log")
 }